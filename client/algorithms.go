@@ -0,0 +1,55 @@
+package client
+
+import (
+	"golang.org/x/crypto/ssh"
+)
+
+// Algorithms overrides the SSH algorithms negotiated with the switch.
+// Empty lists keep the golang.org/x/crypto/ssh defaults.
+type Algorithms struct {
+	Ciphers           []string // e.g. "aes128-ctr", "aes128-cbc"
+	KeyExchanges      []string // e.g. "curve25519-sha256", "diffie-hellman-group1-sha1"
+	MACs              []string // e.g. "hmac-sha2-256", "hmac-sha1"
+	HostKeyAlgorithms []string // e.g. "ssh-ed25519", "ssh-rsa"
+}
+
+// LegacyAlgorithms extends the modern defaults with the weak algorithms
+// offered by older SG2xxx firmware. Only use it for devices that need it.
+var LegacyAlgorithms = Algorithms{
+	Ciphers: []string{
+		"aes128-gcm@openssh.com", "aes256-gcm@openssh.com", "chacha20-poly1305@openssh.com",
+		"aes128-ctr", "aes192-ctr", "aes256-ctr",
+		"aes128-cbc", "3des-cbc",
+	},
+	KeyExchanges: []string{
+		"curve25519-sha256", "curve25519-sha256@libssh.org",
+		"ecdh-sha2-nistp256", "ecdh-sha2-nistp384", "ecdh-sha2-nistp521",
+		"diffie-hellman-group14-sha256", "diffie-hellman-group14-sha1",
+		"diffie-hellman-group-exchange-sha256", "diffie-hellman-group-exchange-sha1",
+		"diffie-hellman-group1-sha1",
+	},
+	MACs: []string{
+		"hmac-sha2-256-etm@openssh.com", "hmac-sha2-512-etm@openssh.com",
+		"hmac-sha2-256", "hmac-sha2-512", "hmac-sha1", "hmac-sha1-96",
+	},
+	HostKeyAlgorithms: []string{
+		ssh.KeyAlgoED25519, ssh.KeyAlgoECDSA256, ssh.KeyAlgoECDSA384, ssh.KeyAlgoECDSA521,
+		ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSA, ssh.KeyAlgoDSA,
+	},
+}
+
+// apply copies the configured algorithms into cfg.
+func (a Algorithms) apply(cfg *ssh.ClientConfig) {
+	if len(a.Ciphers) > 0 {
+		cfg.Ciphers = a.Ciphers
+	}
+	if len(a.KeyExchanges) > 0 {
+		cfg.KeyExchanges = a.KeyExchanges
+	}
+	if len(a.MACs) > 0 {
+		cfg.MACs = a.MACs
+	}
+	if len(a.HostKeyAlgorithms) > 0 {
+		cfg.HostKeyAlgorithms = a.HostKeyAlgorithms
+	}
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// authMethods returns the SSH authentication methods offered to the switch.
+// Agent keys are tried first when enabled, then password auth, then
+// keyboard-interactive for firmware that only supports the latter.
+// The returned func releases resources held for authentication.
+func (c *Client) authMethods() ([]ssh.AuthMethod, func(), error) {
+	var methods []ssh.AuthMethod
+	cleanup := func() {}
+
+	if c.useAgent {
+		sock := c.agentSocket
+		if sock == "" {
+			sock = os.Getenv("SSH_AUTH_SOCK")
+		}
+		if sock == "" {
+			return nil, cleanup, fmt.Errorf("ssh-agent: SSH_AUTH_SOCK is not set")
+		}
+		conn, err := net.Dial("unix", sock)
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("ssh-agent: %w", err)
+		}
+		cleanup = func() { conn.Close() }
+		methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+	}
+
+	challenge := c.challenge
+	if challenge == nil {
+		challenge = c.passwordChallenge
+	}
+	if c.Password != "" || c.challenge != nil || !c.useAgent {
+		methods = append(methods,
+			ssh.Password(c.Password),
+			ssh.KeyboardInteractive(challenge),
+		)
+	}
+	return methods, cleanup, nil
+}
+
+// passwordChallenge answers every keyboard-interactive question with the login password.
+func (c *Client) passwordChallenge(user, instruction string, questions []string, echos []bool) ([]string, error) {
+	answers := make([]string, len(questions))
+	for i := range questions {
+		answers[i] = c.Password
+	}
+	return answers, nil
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// CommandResult is the outcome of one command run by RunCommands.
+type CommandResult struct {
+	Command string // Command as sent
+	Output  string // Output as returned by RunCommand
+	Err     error  // Error from RunCommand, if any
+}
+
+// BatchOption configures RunCommands.
+type BatchOption func(*batchConfig)
+
+type batchConfig struct {
+	stopOnError bool
+	timeout     time.Duration
+}
+
+// StopOnError makes RunCommands skip the remaining commands after a failure.
+func StopOnError() BatchOption {
+	return func(b *batchConfig) {
+		b.stopOnError = true
+	}
+}
+
+// PerCommandTimeout bounds each command of the batch individually, in
+// addition to any deadline of the batch context.
+func PerCommandTimeout(d time.Duration) BatchOption {
+	return func(b *batchConfig) {
+		b.timeout = d
+	}
+}
+
+// RunCommands runs cmds in order and returns a result per command run, along
+// with the first error encountered. Commands after a failure are still run
+// unless StopOnError is given. Commands from other goroutines are not
+// interleaved with the batch.
+func (c *Client) RunCommands(ctx context.Context, cmds []string, opts ...BatchOption) ([]CommandResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var cfg batchConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	results := make([]CommandResult, 0, len(cmds))
+	var firstErr error
+	for _, cmd := range cmds {
+		out, err := c.runWithTimeout(ctx, cmd, cfg.timeout)
+		results = append(results, CommandResult{Command: cmd, Output: out, Err: err})
+		if err == nil {
+			continue
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if cfg.stopOnError || ctx.Err() != nil {
+			break
+		}
+	}
+	return results, firstErr
+}
+
+// runWithTimeout runs cmd, bounded by timeout when it is positive. The
+// caller holds c.mu.
+func (c *Client) runWithTimeout(ctx context.Context, cmd string, timeout time.Duration) (string, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	f, err := c.runFrame(ctx, cmd, commandConfig{})
+	return f.Payload, err
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"sync"
+)
+
+// readBufSize is the size of each read from the switch.
+const readBufSize = 4096
+
+// readBufs recycles read buffers between the reader goroutine and the
+// consumers of its chunks, which copy the data out before releasing it.
+var readBufs = sync.Pool{
+	New: func() any {
+		b := make([]byte, readBufSize)
+		return &b
+	},
+}
+
+func getReadBuf() []byte {
+	return *readBufs.Get().(*[]byte)
+}
+
+// putReadBuf returns a buffer obtained from getReadBuf, possibly resliced.
+func putReadBuf(b []byte) {
+	if cap(b) < readBufSize {
+		return
+	}
+	b = b[:readBufSize]
+	readBufs.Put(&b)
+}
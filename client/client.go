@@ -1,140 +1,481 @@
-// Package client provides an SSH interface to communicate with TP-Link switches.
+// Package client provides SSH, telnet and serial console interfaces to communicate with TP-Link switches.
 package client
 
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/crypto/ssh"
 )
 
 var (
-	promptRegex = regexp.MustCompile(`(?m)[\r\n]*(SG2210XMP-M2(-N\d+)?(\([^)]*\))?[>#])\s*$`)
-	ansiEscape  = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
+	promptRegex     = regexp.MustCompile(`(?m)[\r\n]*(SG2210XMP-M2(-N\d+)?(\([^)]*\))?[>#])\s*$`)
+	ansiEscape      = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)|\x1b[()][0-9A-Za-z]`)
+	userPromptRegex = regexp.MustCompile(`(?i)(user(name)?|login)\s*:\s*$`)
+	passPromptRegex = regexp.MustCompile(`(?i)password\s*:\s*$`)
+	// confirmRegex matches confirmation questions such as "Continue? (Y/N):".
+	confirmRegex = regexp.MustCompile(`(?i)[^\r\n]*[(\[]\s*(y/n|yes/no)\s*[)\]]\s*[?:]?\s*$`)
 )
 
-// Client provides an SSH session to interact with TP-Link switches.
+// promptWindow is how much trailing output is searched for the prompt; it
+// only needs to hold the last line plus any escape sequences around it.
+const promptWindow = 1024
+
+// defaultCommandTimeout bounds prompt waits when neither the context nor
+// WithCommandTimeout sets a limit.
+const defaultCommandTimeout = 5 * time.Second
+
+// transport opens the byte stream carrying the switch CLI.
+type transport interface {
+	// open starts an interactive CLI stream to the switch.
+	open(ctx context.Context, c *Client) (stdin io.Writer, stdout io.Reader, err error)
+	// close releases the stream and its underlying connection.
+	close()
+	// interactiveLogin reports whether the CLI asks for credentials in-band.
+	interactiveLogin() bool
+}
+
+// Client provides a CLI session to interact with TP-Link switches.
 type Client struct {
-	Addr     string       // Address of the switch (host:port)
-	User     string       // SSH username
-	Password string       // SSH password
-	conn     *ssh.Client  // Underlying SSH connection
-	session  *ssh.Session // SSH session with PTY
-	stdin    io.Writer    // Pipe to session stdin
-	stdout   io.Reader    // Pipe from session stdout
-	outBuf   *bytes.Buffer
+	Addr           string    // Address of the switch (host:port)
+	User           string    // Login username
+	Password       string    // Login password
+	EnablePassword string    // Enable secret, Password is used when empty
+	transport      transport // SSH by default, telnet, serial or WithTransport
+	stdin          io.Writer // Pipe to session stdin
+	stdout         io.Reader // Pipe from session stdout
+	outBuf         *bytes.Buffer
+	reads          chan readResult // Chunks read from stdout by the reader goroutine
+	done           chan struct{}   // Closed by Close to stop the reader goroutine
+	lost           *atomic.Bool    // Set when the session's stream fails or a keepalive goes unanswered
+	mu             sync.Mutex      // Serializes use of the session
+	prompt         string          // Last CLI prompt seen, e.g. "SG2210XMP-M2#"
+	lastMatch      string          // Text matched by the last expect
+	window         []byte          // Tail of the output searched for prompts
+	secrets        []string        // Secrets of the running command, redacted like the passwords
+
+	commandTimeout time.Duration // prompt wait limit when ctx has no deadline
+	keepAlive      time.Duration // interval between keepalive probes, 0 disables
+	autoReconnect  bool          // reconnect a lost session before the next command
+	idleTimeout    time.Duration // close the session after this long unused, 0 disables
+	idleTimer      *time.Timer   // fires closeIdle
+	lastUsed       time.Time     // end of the last command
+	commandDelay   time.Duration // minimum gap between a prompt and the next command
+	charDelay      time.Duration // gap between characters sent, 0 sends lines whole
+	idle           bool          // the session was closed for being idle
+	dryRun         bool          // record commands instead of sending them
+	dryRunLog      []string      // commands recorded in dry-run mode
+
+	knownHostsPath  string   // known_hosts file used to verify the host key
+	trustOnFirstUse bool     // record unknown host keys instead of rejecting them
+	fingerprints    []string // pinned SHA256 host key fingerprints
+
+	challenge   ssh.KeyboardInteractiveChallenge // answers keyboard-interactive prompts
+	useAgent    bool                             // authenticate with ssh-agent keys
+	agentSocket string                           // agent socket, defaults to SSH_AUTH_SOCK
+
+	jumpHosts []JumpHost    // intermediate hosts, outermost first
+	hops      []*ssh.Client // open connections to jump hosts
+	dialer    Dialer        // opens the first network hop, e.g. via a proxy
+
+	algorithms Algorithms // SSH algorithm overrides for legacy firmware
+
+	baudRate int // serial console speed
+
+	promptRe    *regexp.Regexp // CLI prompt pattern, learned or set with WithPromptRegex
+	learnPrompt bool           // learn promptRe from the first prompt after login
+
+	logger *slog.Logger // destination for debug output, nil discards
+
+	retryPolicy RetryPolicy // retries for Connect and commands
+	hooks       []Hooks     // instrumentation called around commands
+
+	opts   []Option // options given to NewClient and Apply, reused by NewSession
+	optErr error    // first error reported while applying options
 }
 
-// NewClient returns a new initialized Client instance.
-func NewClient(addr, user, password string) *Client {
-	return &Client{
-		Addr:     addr,
-		User:     user,
-		Password: password,
-		outBuf:   new(bytes.Buffer),
-	}
+// NewClient returns a new initialized Client instance using SSH.
+func NewClient(addr, user, password string, opts ...Option) *Client {
+	return newClient(&sshTransport{}, addr, user, password, opts)
 }
 
-// Connect establishes the SSH connection and interactive shell session.
-func (c *Client) Connect(ctx context.Context) error {
-	cfg := &ssh.ClientConfig{
-		User:            c.User,
-		Auth:            []ssh.AuthMethod{ssh.Password(c.Password)},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         5 * time.Second,
+// newClient returns a Client speaking over t.
+func newClient(t transport, addr, user, password string, opts []Option) *Client {
+	c := &Client{
+		Addr:      addr,
+		User:      user,
+		Password:  password,
+		transport: t,
+		outBuf:    new(bytes.Buffer),
+		opts:      append([]Option(nil), opts...),
 	}
-
-	conn, err := ssh.Dial("tcp", c.Addr, cfg)
-	if err != nil {
-		return fmt.Errorf("SSH dial failed: %w", err)
+	for _, opt := range opts {
+		opt(c)
 	}
-	c.conn = conn
+	return c
+}
 
-	sess, err := conn.NewSession()
-	if err != nil {
-		return fmt.Errorf("SSH session failed: %w", err)
-	}
-	c.session = sess
+// Connect establishes the connection and interactive shell session.
+func (c *Client) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.retry(ctx, "connect", func(int) error {
+		return c.connect(ctx)
+	})
+}
 
-	stdin, err := sess.StdinPipe()
-	if err != nil {
-		return err
+// connect implements Connect; the caller holds c.mu.
+func (c *Client) connect(ctx context.Context) error {
+	if c.optErr != nil {
+		return c.optErr
 	}
-	stdout, err := sess.StdoutPipe()
+	c.log().InfoContext(ctx, "Connecting to switch", "host", c.Addr)
+	stdin, stdout, err := c.transport.open(ctx, c)
 	if err != nil {
+		c.log().ErrorContext(ctx, "Failed to connect", "host", c.Addr, "error", err)
+		c.close()
 		return err
 	}
+	c.log().InfoContext(ctx, "Connection established", "host", c.Addr)
 	c.stdin = stdin
 	c.stdout = stdout
+	c.lost = new(atomic.Bool)
+	c.startReader()
+	c.startKeepAlive()
 
-	modes := ssh.TerminalModes{
-		ssh.ECHO:          1,
-		ssh.TTY_OP_ISPEED: 14400,
-		ssh.TTY_OP_OSPEED: 14400,
+	if c.transport.interactiveLogin() {
+		err = c.login(ctx)
+	} else {
+		err = c.waitForPrompt(ctx)
 	}
-	if err := sess.RequestPty("xterm", 120, 40, modes); err != nil {
-		return err
+	if err == nil {
+		c.idle = false
+		c.touch()
 	}
-	if err := sess.Shell(); err != nil {
+	return err
+}
+
+// RunCommand sends a command to the switch and returns its output, without
+// the echoed command line and the trailing prompt. If the switch rejects the
+// command, the output is returned with a *CommandRejectedError.
+// With WithAutoReconnect, a lost session is re-established first.
+// Commands from concurrent goroutines are run one at a time.
+func (c *Client) RunCommand(ctx context.Context, cmd string) (string, error) {
+	f, err := c.RunCommandFrame(ctx, cmd)
+	return f.Payload, err
+}
+
+// RunCommandFrame is like RunCommand but returns the complete output frame.
+func (c *Client) RunCommandFrame(ctx context.Context, cmd string, opts ...CommandOption) (Frame, error) {
+	var cc commandConfig
+	for _, opt := range opts {
+		opt(&cc)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.runFrame(ctx, cmd, cc)
+}
+
+// runFrame implements RunCommandFrame; the caller holds c.mu.
+func (c *Client) runFrame(ctx context.Context, cmd string, cc commandConfig) (Frame, error) {
+	c.secrets = cc.secrets
+	defer func() { c.secrets = nil }()
+	var f Frame
+	op := func(attempt int) error {
+		var err error
+		if attempt > 0 && c.lost != nil && c.lost.Load() {
+			err = c.reconnect(ctx)
+		} else {
+			err = c.ensureConnected(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		f, err = c.runCommand(ctx, cmd, cc)
 		return err
 	}
+	if cc.noRetry {
+		return f, op(0)
+	}
+	err := c.retry(ctx, "command", op)
+	return f, err
+}
 
-	return c.waitForPrompt(ctx)
+// ensureConnected reconnects a session closed for being idle, or a lost
+// session when WithAutoReconnect is set.
+func (c *Client) ensureConnected(ctx context.Context) error {
+	if c.idle || (c.autoReconnect && c.lost != nil && c.lost.Load()) {
+		return c.reconnect(ctx)
+	}
+	return nil
 }
 
-// RunCommand sends a command to the switch and returns its output.
-func (c *Client) RunCommand(ctx context.Context, cmd string) (string, error) {
-	fmt.Fprint(c.stdin, cmd+"\r\n")
-	if err := c.waitForPrompt(ctx); err != nil {
-		return "", err
+// runCommand sends cmd and collects its output up to the next prompt,
+// answering an enable password prompt, and with Confirm or Answer the
+// questions it asks, on the way. On errors the frame holds the command and
+// the questions answered so far. After a prompt timeout the session is marked
+// lost: the late output of cmd would otherwise be read as the next
+// command's.
+func (c *Client) runCommand(ctx context.Context, cmd string, cc commandConfig) (f Frame, err error) {
+	sp := c.startCommand(ctx, cmd)
+	received := 0
+	defer func() {
+		if err != nil {
+			c.outBuf.Reset()
+		}
+		if errors.Is(err, ErrPromptTimeout) {
+			c.markLost()
+		}
+		c.touch()
+		sp.done(received, err)
+	}()
+	if c.dryRun {
+		return c.dryRunFrame(ctx, cmd), nil
+	}
+
+	var asked []string
+	fail := func(err error) (Frame, error) {
+		return Frame{Command: c.redact(cmd), Questions: asked}, err
+	}
+	c.log().InfoContext(ctx, "Sending command", "command", c.redact(cmd))
+	if err := c.sendLine(ctx, cmd); err != nil {
+		return fail(err)
+	}
+	patterns := []*regexp.Regexp{c.promptPattern(), passPromptRegex}
+	for _, a := range cc.answers {
+		patterns = append(patterns, a.question)
+	}
+	sentPassword := false
+	for {
+		i, err := c.expect(ctx, patterns...)
+		received = c.outBuf.Len()
+		if err != nil {
+			return fail(err)
+		}
+		if i == 0 {
+			c.setPrompt(c.lastMatch)
+			break
+		}
+		if i >= 2 {
+			a := cc.answers[i-2]
+			question := strings.TrimSpace(string(cleanOutput([]byte(c.lastMatch))))
+			c.log().InfoContext(ctx, "Question detected, answering", "question", question, "answer", c.redact(a.text))
+			asked = append(asked, question)
+			if err := c.sendLine(ctx, a.text); err != nil {
+				return fail(err)
+			}
+			continue
+		}
+		if sentPassword {
+			return fail(fmt.Errorf("enable password rejected"))
+		}
+		c.log().InfoContext(ctx, "Enable password prompt detected, sending password")
+		if err := c.sendLine(ctx, c.enablePassword()); err != nil {
+			return fail(err)
+		}
+		sentPassword = true
+	}
+	out := render(c.outBuf.Bytes())
+	f = newFrame(cmd, out, c.promptPattern())
+	f.Questions = asked
+	if len(c.secrets) > 0 {
+		f.Command, f.Echo, f.Output = c.redact(f.Command), c.redact(f.Echo), c.redact(f.Output)
+	}
+	if cc.raw {
+		f.Raw = bytes.Clone(c.outBuf.Bytes())
 	}
-	out := ansiEscape.ReplaceAllString(c.outBuf.String(), "")
-	out = strings.ReplaceAll(out, "\r", "")
 	c.outBuf.Reset()
-	return out, nil
+	return f, checkRejected(f)
 }
 
-// Close terminates the SSH session and connection.
+// Close terminates the session and connection. It waits for a running
+// command to finish.
 func (c *Client) Close() {
-	if c.session != nil {
-		c.session.Close()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopIdleTimer()
+	c.close()
+}
+
+// close implements Close; the caller holds c.mu.
+func (c *Client) close() {
+	if c.done != nil {
+		close(c.done)
+		c.done = nil
 	}
-	if c.conn != nil {
-		c.conn.Close()
+	c.transport.close()
+	c.closeHops()
+}
+
+// readResult is a chunk of switch output or the error that ended the stream.
+type readResult struct {
+	data []byte
+	err  error
+}
+
+// startReader copies stdout into c.reads from a goroutine, so waiting for
+// output can be abandoned when a context is cancelled or a timeout expires.
+func (c *Client) startReader() {
+	reads := make(chan readResult, 16)
+	done := make(chan struct{})
+	lost := c.lost
+	c.reads = reads
+	c.done = done
+
+	go func(r io.Reader) {
+		defer close(reads)
+		send := func(res readResult) bool {
+			select {
+			case reads <- res:
+				return true
+			case <-done:
+				return false
+			}
+		}
+		for {
+			buf := getReadBuf()
+			n, err := r.Read(buf)
+			if n > 0 && !send(readResult{data: buf[:n]}) {
+				return
+			}
+			if n == 0 {
+				putReadBuf(buf)
+			}
+			if err != nil {
+				lost.Store(true)
+				send(readResult{err: err})
+				return
+			}
+		}
+	}(c.stdout)
+}
+
+// enablePassword returns the password used to enter privileged mode.
+func (c *Client) enablePassword() string {
+	if c.EnablePassword != "" {
+		return c.EnablePassword
+	}
+	return c.Password
+}
+
+// login answers in-band username and password prompts until the CLI prompt appears.
+func (c *Client) login(ctx context.Context) error {
+	sentPassword := false
+	for {
+		i, err := c.expect(ctx, userPromptRegex, passPromptRegex, c.promptPattern())
+		if err != nil {
+			return err
+		}
+		switch i {
+		case 0:
+			if sentPassword {
+				return fmt.Errorf("login failed for user %q", c.User)
+			}
+			c.log().DebugContext(ctx, "Username prompt detected", "user", c.User)
+			if err := c.sendLine(ctx, c.User); err != nil {
+				return err
+			}
+		case 1:
+			if sentPassword {
+				return fmt.Errorf("login failed for user %q", c.User)
+			}
+			c.log().DebugContext(ctx, "Password prompt detected, sending password")
+			if err := c.sendLine(ctx, c.Password); err != nil {
+				return err
+			}
+			sentPassword = true
+		default:
+			c.setPrompt(c.lastMatch)
+			return nil
+		}
 	}
 }
 
 // waitForPrompt waits for the switch CLI prompt after sending a command.
 func (c *Client) waitForPrompt(ctx context.Context) error {
-	buf := make([]byte, 4096)
-	tmp := make([]byte, 0)
-	timeout := time.After(5 * time.Second)
+	if _, err := c.expect(ctx, c.promptPattern()); err != nil {
+		return err
+	}
+	c.setPrompt(c.lastMatch)
+	return nil
+}
+
+// setPrompt records the prompt just matched, learning its pattern if enabled.
+func (c *Client) setPrompt(match string) {
+	c.prompt = strings.TrimSpace(match)
+	c.learnPromptFrom(c.prompt)
+}
+
+// expect reads switch output until one of patterns matches and returns its
+// index. It gives up when ctx is done or, if ctx has no deadline, after the
+// client's command timeout. Patterns are matched against the last
+// promptWindow bytes only, so they must be anchored to the end of the output.
+func (c *Client) expect(ctx context.Context, patterns ...*regexp.Regexp) (int, error) {
+	if c.reads == nil {
+		return -1, ErrNotConnected
+	}
+	var timeout <-chan time.Time
+	if _, ok := ctx.Deadline(); !ok {
+		timer := time.NewTimer(c.timeout())
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	c.window = c.window[:0]
 
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return -1, ctx.Err()
 		case <-timeout:
-			return fmt.Errorf("timeout waiting for prompt")
-		default:
-			n, err := c.stdout.Read(buf)
-			if err != nil && err != io.EOF {
-				return err
+			c.log().WarnContext(ctx, "Prompt wait timed out", "partial", c.redact(string(cleanOutput(c.window))))
+			return -1, ErrPromptTimeout
+		case res, ok := <-c.reads:
+			if !ok {
+				return -1, ErrConnectionClosed
+			}
+			if res.err != nil {
+				c.log().ErrorContext(ctx, "Read error", "error", res.err)
+				return -1, res.err
 			}
-			if n > 0 {
-				chunk := buf[:n]
-				tmp = append(tmp, chunk...)
-				c.outBuf.Write(chunk)
-				cleaned := ansiEscape.ReplaceAll(tmp, []byte(""))
-				if promptRegex.Match(cleaned) {
-					return nil
+			c.debugRaw(ctx, res.data)
+			c.outBuf.Write(res.data)
+			c.appendWindow(res.data)
+			putReadBuf(res.data)
+
+			cleaned := ansiEscape.ReplaceAll(c.window, nil)
+			for i, re := range patterns {
+				if m := re.Find(cleaned); m != nil {
+					c.lastMatch = string(m)
+					c.log().DebugContext(ctx, "Prompt detected", "match", c.redact(strings.TrimSpace(c.lastMatch)))
+					return i, nil
 				}
 			}
 		}
 	}
 }
+
+// appendWindow adds data to the prompt search window, keeping only its tail.
+func (c *Client) appendWindow(data []byte) {
+	c.window = append(c.window, data...)
+	if extra := len(c.window) - promptWindow; extra > 0 {
+		n := copy(c.window, c.window[extra:])
+		c.window = c.window[:n]
+	}
+}
+
+// timeout returns the prompt wait limit used when ctx has no deadline.
+func (c *Client) timeout() time.Duration {
+	if c.commandTimeout > 0 {
+		return c.commandTimeout
+	}
+	return defaultCommandTimeout
+}
@@ -0,0 +1,257 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/profile"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+func TestConnect(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.Banner = "Welcome to the lab switch"
+	sw.SetResponse("show version", "Firmware 1.0")
+	c := startSwitch(t, sw)
+
+	f, err := c.RunCommandFrame(context.Background(), "show version")
+	if err != nil {
+		t.Fatalf("RunCommandFrame: %v", err)
+	}
+	if f.Payload != "Firmware 1.0" {
+		t.Errorf("payload = %q, want %q", f.Payload, "Firmware 1.0")
+	}
+	if f.Prompt != "SG2210XMP-M2>" {
+		t.Errorf("prompt = %q, want the user mode prompt", f.Prompt)
+	}
+}
+
+func TestConnectBadPassword(t *testing.T) {
+	sw := testutil.NewSwitch()
+	if err := sw.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sw.Close() })
+	c := client.NewClient(sw.Addr(), sw.User, "wrong", client.WithHostKeyFingerprint(ssh.FingerprintSHA256(sw.HostKey())))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.Connect(ctx); err == nil {
+		c.Close()
+		t.Fatal("Connect succeeded with a wrong password")
+	}
+	if _, err := c.RunCommand(ctx, "show version"); !errors.Is(err, client.ErrNotConnected) {
+		t.Errorf("RunCommand error = %v, want ErrNotConnected", err)
+	}
+}
+
+func TestEnablePassword(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.EnablePassword = "s3cret"
+	c := startSwitch(t, sw, client.WithEnablePassword("s3cret"))
+
+	f, err := c.RunCommandFrame(context.Background(), "enable")
+	if err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	if f.Prompt != "SG2210XMP-M2#" {
+		t.Errorf("prompt = %q, want the privileged prompt", f.Prompt)
+	}
+	if strings.Contains(f.Output, "s3cret") {
+		t.Errorf("output %q shows the enable password", f.Output)
+	}
+}
+
+func TestEnablePasswordRejected(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.EnablePassword = "s3cret"
+	c := startSwitch(t, sw, client.WithEnablePassword("wrong"))
+
+	ctx := context.Background()
+	f, err := c.RunCommandFrame(ctx, "enable")
+	var rejected *client.CommandRejectedError
+	if !errors.As(err, &rejected) {
+		t.Fatalf("enable error = %v, want *CommandRejectedError", err)
+	}
+	if f.Prompt != "SG2210XMP-M2>" {
+		t.Errorf("prompt = %q, want to stay in user mode", f.Prompt)
+	}
+	// The session stays usable in user mode.
+	sw.SetResponse("show version", "Firmware 1.0")
+	out, err := c.RunCommand(ctx, "show version")
+	if err != nil {
+		t.Fatalf("RunCommand after rejected enable: %v", err)
+	}
+	if out != "Firmware 1.0" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+// pagedSwitch returns a switch paging every 10 lines and answering
+// "show running-config" with n numbered lines, also returned.
+func pagedSwitch(n int) (*testutil.Switch, string) {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	config := strings.Join(lines, "\n")
+	sw := testutil.NewSwitch()
+	sw.PageLines = 10
+	sw.SetResponse("show running-config", config)
+	return sw, config
+}
+
+func TestPagingDisabledBySetup(t *testing.T) {
+	sw, config := pagedSwitch(35)
+	c := startSwitch(t, sw)
+
+	p, ok := profile.Lookup("SG2210XMP")
+	if !ok {
+		t.Fatal("no SG2210XMP profile")
+	}
+	ctx := context.Background()
+	if err := p.Setup(ctx, c); err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	out, err := c.RunCommand(ctx, "show running-config")
+	if err != nil {
+		t.Fatalf("RunCommand: %v", err)
+	}
+	if out != config {
+		t.Errorf("output = %q, want all 35 lines", out)
+	}
+	if !slices.Contains(sw.Commands(), "no clipaging") {
+		t.Errorf("commands = %q, want no clipaging", sw.Commands())
+	}
+}
+
+func TestPagingTimesOutWithoutSetup(t *testing.T) {
+	sw, _ := pagedSwitch(35)
+	c := startSwitch(t, sw, client.WithCommandTimeout(200*time.Millisecond))
+
+	_, err := c.RunCommand(context.Background(), "show running-config")
+	if !errors.Is(err, client.ErrPromptTimeout) {
+		t.Errorf("error = %v, want ErrPromptTimeout at the pager prompt", err)
+	}
+}
+
+func TestPromptTimeout(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.Handler = func(_ testutil.Mode, cmd string) (string, bool) {
+		if cmd != "show slow" {
+			return "", false
+		}
+		time.Sleep(500 * time.Millisecond)
+		return "late output", true
+	}
+	c := startSwitch(t, sw, client.WithCommandTimeout(100*time.Millisecond))
+
+	start := time.Now()
+	_, err := c.RunCommand(context.Background(), "show slow")
+	if !errors.Is(err, client.ErrPromptTimeout) {
+		t.Fatalf("error = %v, want ErrPromptTimeout", err)
+	}
+	if d := time.Since(start); d > 400*time.Millisecond {
+		t.Errorf("RunCommand returned after %v, want about 100ms", d)
+	}
+}
+
+func TestPromptTimeoutContext(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.Handler = func(_ testutil.Mode, cmd string) (string, bool) {
+		time.Sleep(500 * time.Millisecond)
+		return "late output", cmd == "show slow"
+	}
+	c := startSwitch(t, sw)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := c.RunCommand(ctx, "show slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.EnablePassword = "s3cret"
+	sw.SetResponse("show version", "Firmware 1.0")
+	c := startSwitch(t, sw, client.WithEnablePassword("s3cret"), client.WithAutoReconnect())
+
+	ctx := context.Background()
+	if _, err := c.RunCommand(ctx, "enable"); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	sw.Drop()
+	time.Sleep(50 * time.Millisecond)
+
+	f, err := c.RunCommandFrame(ctx, "show version")
+	if err != nil {
+		t.Fatalf("RunCommandFrame after drop: %v", err)
+	}
+	if f.Payload != "Firmware 1.0" {
+		t.Errorf("payload = %q", f.Payload)
+	}
+	if f.Prompt != "SG2210XMP-M2#" {
+		t.Errorf("prompt = %q, want privileged mode re-entered", f.Prompt)
+	}
+	want := []string{"enable", "enable", "show version"}
+	if cmds := sw.Commands(); !slices.Equal(cmds, want) {
+		t.Errorf("commands = %q, want %q", cmds, want)
+	}
+}
+
+func TestNoReconnectByDefault(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.SetResponse("show version", "Firmware 1.0")
+	c := startSwitch(t, sw)
+
+	sw.Drop()
+	time.Sleep(50 * time.Millisecond)
+	if _, err := c.RunCommand(context.Background(), "show version"); err == nil {
+		t.Error("RunCommand succeeded on a dropped session without WithAutoReconnect")
+	}
+}
+
+func TestIdleClose(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.SetResponse("show version", "Firmware 1.0")
+	c := startSwitch(t, sw, client.WithIdleTimeout(100*time.Millisecond))
+
+	ctx := context.Background()
+	if _, err := c.RunCommand(ctx, "enable"); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	// Activity keeps the session open.
+	for range 3 {
+		time.Sleep(50 * time.Millisecond)
+		if _, err := c.RunCommand(ctx, "show version"); err != nil {
+			t.Fatalf("RunCommand: %v", err)
+		}
+	}
+	if n := sw.Sessions(); n != 1 {
+		t.Fatalf("%d sessions open while in use, want 1", n)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if n := sw.Sessions(); n != 0 {
+		t.Fatalf("%d sessions open after the idle timeout, want 0", n)
+	}
+	f, err := c.RunCommandFrame(ctx, "show version")
+	if err != nil {
+		t.Fatalf("RunCommandFrame after idle close: %v", err)
+	}
+	if f.Payload != "Firmware 1.0" || f.Prompt != "SG2210XMP-M2#" {
+		t.Errorf("frame = %+v, want the output in privileged mode", f)
+	}
+	if n := sw.Sessions(); n != 1 {
+		t.Errorf("%d sessions open after reconnecting, want 1", n)
+	}
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// JumpHost describes an intermediate SSH host used to reach the switch.
+type JumpHost struct {
+	Addr            string              // Address of the jump host (host:port)
+	User            string              // SSH username on the jump host
+	Password        string              // SSH password, used when Auth is empty
+	Auth            []ssh.AuthMethod    // Authentication methods, overrides Password
+	HostKeyCallback ssh.HostKeyCallback // Host key check, defaults to the client's known_hosts check
+}
+
+// errJumpHostKey is returned by Connect for a jump host without a host key
+// check when the client only pins the switch's fingerprint.
+var errJumpHostKey = errors.New("no host key check: set JumpHost.HostKeyCallback or use WithKnownHosts")
+
+// clientConfig returns the SSH configuration used to log in to the jump
+// host, checking its key with hostKeyCallback unless j has its own check.
+func (j JumpHost) clientConfig(hostKeyCallback ssh.HostKeyCallback) *ssh.ClientConfig {
+	auth := j.Auth
+	if len(auth) == 0 {
+		auth = []ssh.AuthMethod{ssh.Password(j.Password)}
+	}
+	if j.HostKeyCallback != nil {
+		hostKeyCallback = j.HostKeyCallback
+	}
+	return &ssh.ClientConfig{
+		User:            j.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         5 * time.Second,
+	}
+}
+
+// dial connects to the switch, tunnelling through any configured jump hosts.
+func (c *Client) dial(ctx context.Context, cfg *ssh.ClientConfig) (*ssh.Client, error) {
+	var via *ssh.Client
+	for _, j := range c.jumpHosts {
+		hostKeyCallback, err := c.jumpHostKeyCallback(j)
+		if err != nil {
+			c.closeHops()
+			return nil, fmt.Errorf("jump host %s: %w", j.Addr, err)
+		}
+		c.log().DebugContext(ctx, "Dialing jump host", "host", j.Addr)
+		hop, err := c.dialSSH(ctx, via, j.Addr, j.clientConfig(hostKeyCallback))
+		if err != nil {
+			c.closeHops()
+			return nil, fmt.Errorf("jump host %s: %w", j.Addr, err)
+		}
+		c.hops = append(c.hops, hop)
+		via = hop
+	}
+
+	conn, err := c.dialSSH(ctx, via, c.Addr, cfg)
+	if err != nil {
+		c.closeHops()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// jumpHostKeyCallback returns the host key check for j when it has none of
+// its own: the client's known_hosts check, which holds keys for every host.
+// Pinned fingerprints belong to the switch, so a client pinning one without
+// a known_hosts file has no check to offer and errJumpHostKey is returned.
+func (c *Client) jumpHostKeyCallback(j JumpHost) (ssh.HostKeyCallback, error) {
+	if j.HostKeyCallback != nil {
+		return nil, nil
+	}
+	if c.knownHostsPath == "" && len(c.fingerprints) > 0 {
+		return nil, errJumpHostKey
+	}
+	return c.knownHostsCallback()
+}
+
+// dialSSH opens an SSH connection to addr, through via when set and
+// otherwise using the configured Dialer.
+func (c *Client) dialSSH(ctx context.Context, via *ssh.Client, addr string, cfg *ssh.ClientConfig) (*ssh.Client, error) {
+	var conn net.Conn
+	var err error
+	if via != nil {
+		conn, err = via.DialContext(ctx, "tcp", addr)
+	} else {
+		d := c.dialer
+		if d == nil {
+			d = &net.Dialer{Timeout: cfg.Timeout}
+		}
+		conn, err = d.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sc, chans, reqs, err := handshake(ctx, conn, addr, cfg)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(sc, chans, reqs), nil
+}
+
+// handshake runs the SSH handshake on conn, bounded by the deadline of ctx
+// or else cfg.Timeout, and abandoned when ctx is done. A server that accepts
+// the connection but never speaks SSH would otherwise block it forever.
+func handshake(ctx context.Context, conn net.Conn, addr string, cfg *ssh.ClientConfig) (ssh.Conn, <-chan ssh.NewChannel, <-chan *ssh.Request, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok && cfg.Timeout > 0 {
+		deadline, ok = time.Now().Add(cfg.Timeout), true
+	}
+	if ok {
+		conn.SetDeadline(deadline) // not supported by jump host channels, which rely on ctx
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	sc, chans, reqs, err := ssh.NewClientConn(conn, addr, cfg)
+	if !stop() {
+		if err == nil {
+			sc.Close()
+		}
+		return nil, nil, nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return sc, chans, reqs, nil
+}
+
+// closeHops closes jump host connections, innermost first.
+func (c *Client) closeHops() {
+	for i := len(c.hops) - 1; i >= 0; i-- {
+		c.hops[i].Close()
+	}
+	c.hops = nil
+}
@@ -0,0 +1,141 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+// silentServer accepts connections and never speaks SSH.
+func silentServer(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestConnectHandshakeDeadline(t *testing.T) {
+	c := client.NewClient(silentServer(t), "admin", "admin", client.WithHostKeyFingerprint("SHA256:unused"))
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Connect(ctx)
+	if err == nil {
+		c.Close()
+		t.Fatal("Connect succeeded against a server that never speaks SSH")
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("Connect returned after %v, want about 200ms", d)
+	}
+}
+
+func TestConnectHandshakeCancel(t *testing.T) {
+	c := client.NewClient(silentServer(t), "admin", "admin", client.WithHostKeyFingerprint("SHA256:unused"))
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := c.Connect(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Connect error = %v, want context.Canceled", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("Connect returned after %v, want about 100ms", d)
+	}
+}
+
+func TestJumpHostNeedsHostKeyCheck(t *testing.T) {
+	c := client.NewClient("192.0.2.1:22", "admin", "admin",
+		client.WithHostKeyFingerprint("SHA256:pinned"),
+		client.WithJumpHost(client.JumpHost{Addr: silentServer(t), User: "jump", Password: "jump"}))
+
+	err := c.Connect(context.Background())
+	if err == nil {
+		c.Close()
+		t.Fatal("Connect succeeded through a jump host with no host key check")
+	}
+	if !strings.Contains(err.Error(), "HostKeyCallback") {
+		t.Errorf("error = %v, want one asking for a host key check", err)
+	}
+}
+
+func TestJumpHostUsesKnownHosts(t *testing.T) {
+	jump := testutil.NewSwitch()
+	if err := jump.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { jump.Close() })
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := client.NewClient("192.0.2.1:22", "admin", "admin",
+		client.WithKnownHosts(path),
+		client.WithJumpHost(client.JumpHost{Addr: jump.Addr(), User: jump.User, Password: jump.Password}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := c.Connect(ctx)
+	if err == nil {
+		c.Close()
+		t.Fatal("Connect accepted a jump host missing from known_hosts")
+	}
+	if !strings.Contains(err.Error(), "unknown host key") {
+		t.Errorf("error = %v, want the jump host's key rejected", err)
+	}
+}
+
+func TestJumpHostTrustOnFirstUse(t *testing.T) {
+	jump := testutil.NewSwitch()
+	if err := jump.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { jump.Close() })
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	c := client.NewClient("192.0.2.1:22", "admin", "admin",
+		client.WithKnownHosts(path), client.WithTrustOnFirstUse(),
+		client.WithJumpHost(client.JumpHost{Addr: jump.Addr(), User: jump.User, Password: jump.Password}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.Connect(ctx); err == nil {
+		c.Close() // the fake switch does not forward connections
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := strings.Fields(string(ssh.MarshalAuthorizedKey(jump.HostKey())))[1]
+	if !strings.Contains(string(data), key) {
+		t.Errorf("known_hosts = %q, want the jump host's key recorded", data)
+	}
+}
@@ -0,0 +1,22 @@
+package client
+
+import (
+	"context"
+)
+
+// dryRunFrame records cmd instead of sending it and returns an empty frame.
+// The caller holds c.mu.
+func (c *Client) dryRunFrame(ctx context.Context, cmd string) Frame {
+	cmd = c.redact(cmd)
+	c.log().InfoContext(ctx, "Dry run, not sending command", "command", cmd)
+	c.dryRunLog = append(c.dryRunLog, cmd)
+	return Frame{Command: cmd, Prompt: c.prompt}
+}
+
+// DryRunCommands returns the commands recorded instead of sent since the
+// client was created with WithDryRun, with credentials and Secrets redacted.
+func (c *Client) DryRunCommands() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.dryRunLog...)
+}
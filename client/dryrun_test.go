@@ -0,0 +1,54 @@
+package client_test
+
+import (
+	"context"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+func TestDryRunSendsNothing(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.SetResponse("show version", "Firmware 1.0")
+	c := startSwitch(t, sw, client.WithDryRun())
+
+	ctx := context.Background()
+	if out, err := c.RunCommand(ctx, "vlan 10"); err != nil || out != "" {
+		t.Errorf("RunCommand = %q, %v, want no output", out, err)
+	}
+	if f, err := c.RunCommandFrame(ctx, "name users"); err != nil || f.Payload != "" || f.Prompt != "SG2210XMP-M2>" {
+		t.Errorf("RunCommandFrame = %+v, %v, want an empty frame at the current prompt", f, err)
+	}
+	r, err := c.StreamCommand(ctx, "show version")
+	if err != nil {
+		t.Fatalf("StreamCommand: %v", err)
+	}
+	if b, err := io.ReadAll(r); err != nil || len(b) != 0 {
+		t.Errorf("stream = %q, %v, want it empty", b, err)
+	}
+	r.Close()
+	if out, err := c.Exec(ctx, "reboot"); err != nil || out != "" {
+		t.Errorf("Exec = %q, %v, want no output", out, err)
+	}
+
+	want := []string{"vlan 10", "name users", "show version", "reboot"}
+	if got := c.DryRunCommands(); !slices.Equal(got, want) {
+		t.Errorf("DryRunCommands() = %q, want %q", got, want)
+	}
+	if got := sw.Commands(); len(got) != 0 {
+		t.Errorf("switch received %q, want nothing", got)
+	}
+}
+
+func TestDryRunWithoutConnect(t *testing.T) {
+	c := client.NewClient("192.0.2.1:22", "admin", "admin", client.WithDryRun())
+	if _, err := c.RunCommand(context.Background(), "vlan 10"); err != nil {
+		t.Fatalf("RunCommand: %v", err)
+	}
+	if got := c.DryRunCommands(); !slices.Equal(got, []string{"vlan 10"}) {
+		t.Errorf("DryRunCommands() = %q", got)
+	}
+}
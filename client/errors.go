@@ -0,0 +1,48 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrCommandRejected is matched by errors.Is for every CommandRejectedError.
+	ErrCommandRejected = errors.New("command rejected")
+	// ErrPromptTimeout is returned when the prompt does not appear in time.
+	ErrPromptTimeout = errors.New("timeout waiting for prompt")
+	// ErrConnectionClosed is returned when the switch closes the session.
+	ErrConnectionClosed = errors.New("connection closed")
+	// ErrNotConnected is returned when a command is run before Connect.
+	ErrNotConnected = errors.New("not connected")
+)
+
+// CommandRejectedError is returned when the switch CLI answers a command with
+// an error banner such as "Invalid input detected" or "Incomplete command".
+type CommandRejectedError struct {
+	Command string // Command as sent
+	Message string // Error banner printed by the switch
+}
+
+func (e *CommandRejectedError) Error() string {
+	return fmt.Sprintf("command %q rejected: %s", e.Command, e.Message)
+}
+
+// Is reports whether target is ErrCommandRejected.
+func (e *CommandRejectedError) Is(target error) bool {
+	return target == ErrCommandRejected
+}
+
+// rejectionRegex matches the error banners printed by the TP-Link CLI.
+var rejectionRegex = regexp.MustCompile(`(?mi)^\s*%?\s*((invalid input detected|incomplete command|ambiguous command|unrecognized command|bad command|error:).*)$`)
+
+// checkRejected returns a CommandRejectedError if the payload of f contains
+// an error banner.
+func checkRejected(f Frame) error {
+	m := rejectionRegex.FindStringSubmatch(f.Payload)
+	if m == nil {
+		return nil
+	}
+	return &CommandRejectedError{Command: f.Command, Message: strings.TrimSpace(m[1])}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Exec runs cmd as an SSH exec request on the connection opened by Connect,
+// without a PTY or the interactive shell, and returns its output with line
+// endings normalised. There is no echo, prompt or paging to handle, so the
+// output is cleaner than RunCommand's, but the command always starts in
+// user mode and only firmware that accepts exec requests supports it.
+//
+// Like RunCommand, Exec waits for a running command to finish and
+// reconnects a session closed for being idle, or lost with
+// WithAutoReconnect, first. Without a context deadline the command timeout
+// bounds the whole command.
+func (c *Client) Exec(ctx context.Context, cmd string) (out string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dryRun {
+		c.dryRunFrame(ctx, cmd)
+		return "", nil
+	}
+	if err := c.ensureConnected(ctx); err != nil {
+		return "", err
+	}
+	t, ok := c.transport.(*sshTransport)
+	if !ok {
+		return "", errors.New("exec requires an SSH connection")
+	}
+	conn := t.client()
+	if conn == nil {
+		return "", ErrNotConnected
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout())
+		defer cancel()
+	}
+
+	sp := c.startCommand(ctx, cmd)
+	received := 0
+	defer func() {
+		c.touch()
+		sp.done(received, err)
+	}()
+	c.log().InfoContext(ctx, "Executing command", "command", c.redact(cmd))
+
+	sess, err := conn.NewSession()
+	if err != nil {
+		return "", fmt.Errorf("SSH session failed: %w", err)
+	}
+	defer sess.Close()
+
+	type result struct {
+		out []byte
+		err error
+	}
+	res := make(chan result, 1)
+	go func() {
+		b, err := sess.CombinedOutput(cmd)
+		res <- result{b, err}
+	}()
+	var r result
+	select {
+	case r = <-res:
+	case <-ctx.Done():
+		sess.Close()
+		return "", fmt.Errorf("exec %q: %w", c.redact(cmd), ctx.Err())
+	}
+	received = len(r.out)
+	c.debugRaw(ctx, r.out)
+	out = strings.TrimSpace(strings.ReplaceAll(string(r.out), "\r\n", "\n"))
+	if err := checkRejected(Frame{Command: c.redact(cmd), Payload: out}); err != nil {
+		return out, err
+	}
+	if r.err != nil {
+		return out, fmt.Errorf("exec %q: %w", c.redact(cmd), r.err)
+	}
+	return out, nil
+}
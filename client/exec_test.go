@@ -0,0 +1,97 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+func TestExec(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.SetResponse("show version", "Firmware 1.0\nHardware 2.0")
+	c := startSwitch(t, sw)
+
+	out, err := c.Exec(context.Background(), "show version")
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if out != "Firmware 1.0\nHardware 2.0" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestExecRejectedRedacted(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.EnablePassword = "s3cret"
+	sw.SetResponse("enable-secret s3cret", "Error: Bad command")
+	c := startSwitch(t, sw, client.WithEnablePassword("s3cret"))
+
+	_, err := c.Exec(context.Background(), "enable-secret s3cret")
+	var rejected *client.CommandRejectedError
+	if !errors.As(err, &rejected) {
+		t.Fatalf("Exec error = %v, want *CommandRejectedError", err)
+	}
+	if rejected.Command != "enable-secret [REDACTED]" || strings.Contains(err.Error(), "s3cret") {
+		t.Errorf("error %q shows the enable password", err)
+	}
+}
+
+func TestExecReconnectsIdleSession(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.SetResponse("show version", "Firmware 1.0")
+	c := startSwitch(t, sw, client.WithIdleTimeout(50*time.Millisecond))
+
+	time.Sleep(200 * time.Millisecond)
+	out, err := c.Exec(context.Background(), "show version")
+	if err != nil {
+		t.Fatalf("Exec after idle close: %v", err)
+	}
+	if out != "Firmware 1.0" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestExecWaitsForShellCommand(t *testing.T) {
+	var running atomic.Bool
+	sw := testutil.NewSwitch()
+	sw.Handler = func(_ testutil.Mode, cmd string) (string, bool) {
+		switch cmd {
+		case "show slow":
+			running.Store(true)
+			time.Sleep(200 * time.Millisecond)
+			running.Store(false)
+			return "slow done", true
+		case "show fast":
+			if running.Load() {
+				return "overlapped", true
+			}
+			return "fast done", true
+		}
+		return "", false
+	}
+	c := startSwitch(t, sw)
+
+	ctx := context.Background()
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.RunCommand(ctx, "show slow")
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	out, err := c.Exec(ctx, "show fast")
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if out != "fast done" {
+		t.Errorf("output = %q, want Exec to wait for the shell command", out)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("RunCommand: %v", err)
+	}
+}
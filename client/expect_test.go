@@ -0,0 +1,174 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pipePrompt is the prompt of the switch played by pipeTransport.
+const pipePrompt = "SG2210XMP-M2#"
+
+// pipeTransport is a Transport over in-memory pipes, playing a switch that
+// answers "show running-config" with output and other lines with the prompt.
+type pipeTransport struct {
+	output  []byte
+	closers []io.Closer
+}
+
+func (p *pipeTransport) Open(ctx context.Context) (io.Writer, io.Reader, error) {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	p.closers = []io.Closer{inR, inW, outR, outW}
+	go p.serve(inR, outW)
+	return inW, outR, nil
+}
+
+func (p *pipeTransport) serve(in io.Reader, out io.Writer) {
+	if _, err := io.WriteString(out, "\r\n"+pipePrompt); err != nil {
+		return
+	}
+	sc := bufio.NewScanner(in)
+	for sc.Scan() {
+		cmd := strings.TrimSpace(sc.Text())
+		if _, err := io.WriteString(out, cmd+"\r\n"); err != nil {
+			return
+		}
+		if cmd == "show running-config" {
+			if _, err := out.Write(p.output); err != nil {
+				return
+			}
+		}
+		if _, err := io.WriteString(out, "\r\n"+pipePrompt); err != nil {
+			return
+		}
+	}
+}
+
+func (p *pipeTransport) Close() error {
+	for _, c := range p.closers {
+		c.Close()
+	}
+	return nil
+}
+
+// runningConfig returns about size bytes of running-config output.
+func runningConfig(size int) []byte {
+	var sb strings.Builder
+	for i := 1; sb.Len() < size; i++ {
+		fmt.Fprintf(&sb, "interface gigabitEthernet 1/0/%d\r\n  description \"port %d\"\r\n  switchport general allowed vlan 10 untagged\r\n#\r\n", i%28+1, i)
+	}
+	return []byte(sb.String())
+}
+
+// pipeClient returns a client connected to a pipeTransport answering
+// "show running-config" with output.
+func pipeClient(tb testing.TB, output []byte) *Client {
+	tb.Helper()
+	c := NewClient("pipe", "admin", "admin", WithTransport(&pipeTransport{output: output}), WithCommandTimeout(time.Minute))
+	if err := c.Connect(context.Background()); err != nil {
+		tb.Fatalf("Connect: %v", err)
+	}
+	tb.Cleanup(c.Close)
+	return c
+}
+
+// expectFullBuffer is the prompt wait expect replaced, kept to compare
+// against: it strips escapes from and matches the whole output so far on
+// every read, which is quadratic in the output size.
+func expectFullBuffer(ctx context.Context, c *Client) error {
+	re := c.promptPattern()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res, ok := <-c.reads:
+			if !ok {
+				return ErrConnectionClosed
+			}
+			if res.err != nil {
+				return res.err
+			}
+			c.outBuf.Write(res.data)
+			putReadBuf(res.data)
+			if re.Match(ansiEscape.ReplaceAll(c.outBuf.Bytes(), nil)) {
+				return nil
+			}
+		}
+	}
+}
+
+func TestRunCommandLargeOutput(t *testing.T) {
+	config := runningConfig(2 << 20)
+	c := pipeClient(t, config)
+
+	out, err := c.RunCommand(context.Background(), "show running-config")
+	if err != nil {
+		t.Fatalf("RunCommand: %v", err)
+	}
+	want := strings.TrimSpace(strings.ReplaceAll(string(config), "\r\n", "\n"))
+	if out != want {
+		t.Errorf("output is %d bytes, want the %d bytes sent", len(out), len(want))
+	}
+	if c.prompt != pipePrompt {
+		t.Errorf("prompt = %q, want %q", c.prompt, pipePrompt)
+	}
+}
+
+func BenchmarkExpect(b *testing.B) {
+	for _, size := range []int{1 << 20, 2 << 20} {
+		for _, bm := range []struct {
+			name   string
+			expect func(context.Context, *Client) error
+		}{
+			{"window", func(ctx context.Context, c *Client) error {
+				_, err := c.expect(ctx, c.promptPattern())
+				return err
+			}},
+			{"fullbuffer", expectFullBuffer},
+		} {
+			b.Run(fmt.Sprintf("%s/%dMB", bm.name, size>>20), func(b *testing.B) {
+				c := pipeClient(b, runningConfig(size))
+				ctx := context.Background()
+				b.SetBytes(int64(size))
+				b.ResetTimer()
+				for range b.N {
+					if err := c.sendLine(ctx, "show running-config"); err != nil {
+						b.Fatal(err)
+					}
+					if err := bm.expect(ctx, c); err != nil {
+						b.Fatal(err)
+					}
+					if n := c.outBuf.Len(); n < size {
+						b.Fatalf("read %d bytes, want at least %d", n, size)
+					}
+					c.outBuf.Reset()
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkRunCommand(b *testing.B) {
+	for _, size := range []int{1 << 20, 4 << 20} {
+		b.Run(fmt.Sprintf("%dMB", size>>20), func(b *testing.B) {
+			c := pipeClient(b, runningConfig(size))
+			ctx := context.Background()
+			b.SetBytes(int64(size))
+			b.ResetTimer()
+			for range b.N {
+				out, err := c.RunCommand(ctx, "show running-config")
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(out) < size*9/10 {
+					b.Fatalf("output is %d bytes, want about %d", len(out), size)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Frame is the output of one command split into its parts.
+type Frame struct {
+	Command   string   // Command as sent
+	Echo      string   // Echoed command line, including the prompt before it
+	Payload   string   // Command output without echo and trailing prompt
+	Prompt    string   // Prompt that ended the output
+	Output    string   // Complete cleaned output as received
+	Raw       []byte   // Untouched output including ANSI and control data, with RawOutput
+	Questions []string // Questions answered by Confirm or Answer, as asked; kept on errors
+}
+
+// CommandOption configures a single RunCommandFrame call.
+type CommandOption func(*commandConfig)
+
+type commandConfig struct {
+	raw     bool
+	answers []answer // replies to questions the command asks, in matching order
+	secrets []string // redacted from logs, hooks, dry-run records, errors and the frame
+	noRetry bool     // make a single attempt whatever the retry policy
+}
+
+// RawOutput keeps the untouched byte stream of the command in Frame.Raw,
+// for output such as box-drawing tables that cleaning would damage.
+func RawOutput() CommandOption {
+	return func(cc *commandConfig) {
+		cc.raw = true
+	}
+}
+
+// answer is the reply to questions matching question.
+type answer struct {
+	question *regexp.Regexp
+	text     string
+}
+
+// Confirm answers each confirmation question the command asks, such as
+// "Continue? (Y/N):", with text, e.g. "y". Without it such a question
+// stalls the command until the prompt wait times out.
+func Confirm(text string) CommandOption {
+	return Answer(confirmRegex, text)
+}
+
+// Answer answers each question the command asks that matches question with
+// text. Like prompts, question is matched against the end of the output
+// and must be anchored with "$"; matching the whole line makes it show in
+// Frame.Questions. Questions are tried in the order the options are given,
+// so put narrower ones first.
+func Answer(question *regexp.Regexp, text string) CommandOption {
+	return func(cc *commandConfig) {
+		cc.answers = append(cc.answers, answer{question, text})
+	}
+}
+
+// NoRetry makes a single attempt at the command whatever the retry policy,
+// for commands that change the switch and must not be sent twice.
+func NoRetry() CommandOption {
+	return func(cc *commandConfig) {
+		cc.noRetry = true
+	}
+}
+
+// Secrets marks strings in the command, such as passwords, that must not be
+// disclosed: they are replaced by "[REDACTED]" wherever the password is, in
+// log records, hook infos, dry-run records, errors and the returned Frame
+// except Raw. The command is still sent as given.
+func Secrets(secrets ...string) CommandOption {
+	return func(cc *commandConfig) {
+		cc.secrets = append(cc.secrets, secrets...)
+	}
+}
+
+// newFrame splits the cleaned output of cmd into echo, payload and prompt.
+// Anything before the echo, such as a login banner, is dropped from the payload.
+func newFrame(cmd, out string, prompt *regexp.Regexp) Frame {
+	f := Frame{Command: cmd, Output: out}
+
+	body := out
+	if all := prompt.FindAllStringSubmatchIndex(out, -1); len(all) > 0 {
+		loc := all[len(all)-1]
+		body = out[:loc[0]]
+		if len(loc) >= 4 && loc[2] >= 0 {
+			f.Prompt = out[loc[2]:loc[3]]
+		} else {
+			f.Prompt = strings.TrimSpace(out[loc[0]:])
+		}
+	}
+
+	if want := strings.TrimSpace(cmd); want != "" {
+		offset := 0
+		for _, line := range strings.SplitAfter(body, "\n") {
+			if strings.HasSuffix(strings.TrimSpace(line), want) {
+				f.Echo = strings.TrimRight(line, "\n")
+				body = body[offset+len(line):]
+				break
+			}
+			offset += len(line)
+		}
+	}
+
+	f.Payload = strings.TrimRight(body, "\n")
+	return f
+}
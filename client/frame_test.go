@@ -0,0 +1,154 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+func TestSecretsRedacted(t *testing.T) {
+	const secret = "s3cr3t-pass"
+	sw := testutil.NewSwitch()
+	sw.Handler = func(mode testutil.Mode, cmd string) (string, bool) {
+		if strings.HasPrefix(cmd, "set-secret ") {
+			return "Error: Bad command", true
+		}
+		return "", false
+	}
+	var logs bytes.Buffer
+	var infos []string
+	hooks := client.Hooks{
+		OnCommandStart: func(ctx context.Context, info client.CommandStartInfo) context.Context {
+			infos = append(infos, info.Command)
+			return nil
+		},
+		OnCommandDone: func(ctx context.Context, info client.CommandDoneInfo) {
+			infos = append(infos, info.Command)
+		},
+	}
+	c := startSwitch(t, sw,
+		client.WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		client.WithHooks(hooks))
+
+	f, err := c.RunCommandFrame(context.Background(), "set-secret "+secret, client.Secrets(secret))
+	var rejected *client.CommandRejectedError
+	if !errors.As(err, &rejected) {
+		t.Fatalf("err = %v, want a CommandRejectedError", err)
+	}
+	for what, s := range map[string]string{
+		"error":         err.Error(),
+		"rejected cmd":  rejected.Command,
+		"frame command": f.Command,
+		"frame echo":    f.Echo,
+		"frame output":  f.Output,
+		"log":           logs.String(),
+		"hook infos":    strings.Join(infos, "\n"),
+	} {
+		if strings.Contains(s, secret) {
+			t.Errorf("%s discloses the secret: %q", what, s)
+		}
+	}
+	if !strings.Contains(rejected.Command, "[REDACTED]") {
+		t.Errorf("rejected command = %q, want it redacted", rejected.Command)
+	}
+
+	// The secret applies to its command only.
+	if _, err := c.RunCommand(context.Background(), "show "+secret); err == nil {
+		t.Fatal("show with unknown argument: want an error")
+	} else if !strings.Contains(err.Error(), secret) {
+		t.Errorf("later command redacted: %v", err)
+	}
+}
+
+func TestSecretsDryRun(t *testing.T) {
+	c := client.NewClient("192.0.2.1:22", "admin", "admin", client.WithDryRun())
+	if _, err := c.RunCommandFrame(context.Background(), "snmp-server user u cpwd hunter2", client.Secrets("hunter2")); err != nil {
+		t.Fatal(err)
+	}
+	got := c.DryRunCommands()
+	if len(got) != 1 || got[0] != "snmp-server user u cpwd [REDACTED]" {
+		t.Errorf("DryRunCommands() = %q", got)
+	}
+}
+
+func TestAnswer(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.SetQuestion("reset", "Reset to factory defaults? (Y/N):")
+	sw.SetResponse("reset", "Reset done")
+	c := startSwitch(t, sw)
+
+	f, err := c.RunCommandFrame(context.Background(), "reset",
+		client.Answer(regexp.MustCompile(`(?i)[^\r\n]*save[^\r\n]*\(y/n\):\s*$`), "n"),
+		client.Answer(regexp.MustCompile(`(?i)[^\r\n]*reset[^\r\n]*\(y/n\):\s*$`), "y"))
+	if err != nil {
+		t.Fatalf("RunCommandFrame: %v", err)
+	}
+	if want := []string{"Reset to factory defaults? (Y/N):"}; !slices.Equal(f.Questions, want) {
+		t.Errorf("Questions = %q, want %q", f.Questions, want)
+	}
+	if !strings.HasSuffix(f.Payload, "\nReset done") {
+		t.Errorf("Payload = %q, want it to end in the command output", f.Payload)
+	}
+}
+
+func TestAnswerRedacted(t *testing.T) {
+	const secret = "hunter2"
+	sw := testutil.NewSwitch()
+	sw.SetQuestion("snmp-server add", "Community string:")
+	sw.SetResponse("snmp-server add", "Community added")
+	var logs bytes.Buffer
+	c := startSwitch(t, sw, client.WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+
+	if _, err := c.RunCommandFrame(context.Background(), "snmp-server add",
+		client.Answer(regexp.MustCompile(`string:\s*$`), secret), client.Secrets(secret)); err != nil {
+		t.Fatalf("RunCommandFrame: %v", err)
+	}
+	if strings.Contains(logs.String(), secret) {
+		t.Errorf("logs show the answer:\n%s", logs.String())
+	}
+	if !strings.Contains(logs.String(), "answer=[REDACTED]") {
+		t.Errorf("logs do not record the redacted answer:\n%s", logs.String())
+	}
+}
+
+func TestUnansweredQuestionTimesOut(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.SetQuestion("reset", "Reset to factory defaults? (Y/N):")
+	c := startSwitch(t, sw, client.WithCommandTimeout(200*time.Millisecond))
+
+	f, err := c.RunCommandFrame(context.Background(), "reset",
+		client.Answer(regexp.MustCompile(`(?i)[^\r\n]*save[^\r\n]*\(y/n\):\s*$`), "y"))
+	if !errors.Is(err, client.ErrPromptTimeout) {
+		t.Fatalf("error = %v, want ErrPromptTimeout", err)
+	}
+	if f.Command != "reset" || len(f.Questions) != 0 {
+		t.Errorf("frame = %+v, want the command and no questions", f)
+	}
+}
+
+func TestPromptAutoDetect(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.Hostname = "core1.lab"
+	sw.SetResponse("show neighbors", "edge2#\nedge3>")
+	c := startSwitch(t, sw, client.WithPromptAutoDetect())
+
+	f, err := c.RunCommandFrame(context.Background(), "show neighbors")
+	if err != nil {
+		t.Fatalf("RunCommandFrame: %v", err)
+	}
+	if f.Prompt != "core1.lab>" {
+		t.Errorf("Prompt = %q, want core1.lab>", f.Prompt)
+	}
+	if f.Payload != "edge2#\nedge3>" {
+		t.Errorf("Payload = %q, want the look-alike prompts kept", f.Payload)
+	}
+}
@@ -0,0 +1,59 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+// startSwitch starts sw and returns a client for it, connected unless
+// opts make connecting fail. Both are closed when the test ends.
+func startSwitch(t *testing.T, sw *testutil.Switch, opts ...client.Option) *client.Client {
+	t.Helper()
+	if err := sw.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sw.Close() })
+	c := newClient(sw, opts...)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+// newClient returns an unconnected client for the started sw, pinned to its
+// host key and with a short command timeout.
+func newClient(sw *testutil.Switch, opts ...client.Option) *client.Client {
+	opts = append([]client.Option{
+		client.WithHostKeyFingerprint(ssh.FingerprintSHA256(sw.HostKey())),
+		client.WithCommandTimeout(2 * time.Second),
+	}, opts...)
+	return client.NewClient(sw.Addr(), sw.User, sw.Password, opts...)
+}
+
+// pipeSwitch returns a client connected to sw over its in-memory transport.
+// Both are closed when the test ends.
+func pipeSwitch(t *testing.T, sw *testutil.Switch, opts ...client.Option) *client.Client {
+	t.Helper()
+	t.Cleanup(func() { sw.Close() })
+	opts = append([]client.Option{
+		client.WithTransport(sw.Transport()),
+		client.WithCommandTimeout(2 * time.Second),
+	}, opts...)
+	c := client.NewClient("pipe", sw.User, sw.Password, opts...)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
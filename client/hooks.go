@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// CommandStartInfo describes a command about to be sent to a switch.
+type CommandStartInfo struct {
+	Addr    string // Switch address
+	Command string // Command, with credentials redacted
+}
+
+// CommandDoneInfo describes a finished command.
+type CommandDoneInfo struct {
+	Addr     string        // Switch address
+	Command  string        // Command, with credentials redacted
+	Duration time.Duration // Time from sending the command to the prompt
+	Bytes    int           // Raw bytes received in reply
+	Err      error         // Error returned to the caller, if any
+}
+
+// Hooks are called around every command sent to a switch, including retries
+// and streamed commands, e.g. to record metrics or tracing spans. Either
+// func may be nil. The context returned by OnCommandStart, if not nil, is
+// passed to the same Hooks' OnCommandDone, so a span started in one can be
+// ended in the other, and to the OnCommandStart of hooks registered later.
+type Hooks struct {
+	OnCommandStart func(ctx context.Context, info CommandStartInfo) context.Context
+	OnCommandDone  func(ctx context.Context, info CommandDoneInfo)
+}
+
+// commandSpan tracks one command between the start and done hooks.
+type commandSpan struct {
+	c     *Client
+	ctxs  []context.Context // context for each hook's OnCommandDone
+	cmd   string
+	start time.Time
+}
+
+// startCommand runs the OnCommandStart hooks for cmd.
+func (c *Client) startCommand(ctx context.Context, cmd string) *commandSpan {
+	sp := &commandSpan{c: c, cmd: c.redact(cmd), start: time.Now()}
+	for _, h := range c.hooks {
+		if h.OnCommandStart != nil {
+			if hctx := h.OnCommandStart(ctx, CommandStartInfo{Addr: c.Addr, Command: sp.cmd}); hctx != nil {
+				ctx = hctx
+			}
+		}
+		sp.ctxs = append(sp.ctxs, ctx)
+	}
+	return sp
+}
+
+// done runs the OnCommandDone hooks.
+func (sp *commandSpan) done(bytes int, err error) {
+	info := CommandDoneInfo{
+		Addr:     sp.c.Addr,
+		Command:  sp.cmd,
+		Duration: time.Since(sp.start),
+		Bytes:    bytes,
+		Err:      err,
+	}
+	for i, ctx := range sp.ctxs {
+		if h := sp.c.hooks[i]; h.OnCommandDone != nil {
+			h.OnCommandDone(ctx, info)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+type spanKey struct{}
+
+// hookRecorder records the hook calls of one WithHooks registration.
+type hookRecorder struct {
+	name  string
+	calls *[]string
+	done  []client.CommandDoneInfo
+}
+
+func (r *hookRecorder) hooks() client.Hooks {
+	return client.Hooks{
+		OnCommandStart: func(ctx context.Context, info client.CommandStartInfo) context.Context {
+			*r.calls = append(*r.calls, r.name+" start "+info.Command)
+			return context.WithValue(ctx, spanKey{}, r.name+" "+info.Command)
+		},
+		OnCommandDone: func(ctx context.Context, info client.CommandDoneInfo) {
+			*r.calls = append(*r.calls, r.name+" done "+info.Command)
+			if span, _ := ctx.Value(spanKey{}).(string); span != r.name+" "+info.Command {
+				*r.calls = append(*r.calls, r.name+" wrong context "+span)
+			}
+			r.done = append(r.done, info)
+		},
+	}
+}
+
+func TestHooks(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.SetResponse("show version", "Firmware 1.0")
+	var calls []string
+	first := &hookRecorder{name: "first", calls: &calls}
+	second := &hookRecorder{name: "second", calls: &calls}
+	c := pipeSwitch(t, sw, client.WithHooks(first.hooks()), client.WithHooks(second.hooks()))
+
+	ctx := context.Background()
+	if _, err := c.RunCommand(ctx, "show version"); err != nil {
+		t.Fatalf("RunCommand: %v", err)
+	}
+	if _, err := c.RunCommand(ctx, "show bogus"); err == nil {
+		t.Fatal("show bogus: want an error")
+	}
+	r, err := c.StreamCommand(ctx, "show version")
+	if err != nil {
+		t.Fatalf("StreamCommand: %v", err)
+	}
+	if _, err := io.ReadAll(r); err != nil {
+		t.Fatalf("reading the stream: %v", err)
+	}
+	r.Close()
+
+	want := []string{
+		"first start show version", "second start show version",
+		"first done show version", "second done show version",
+		"first start show bogus", "second start show bogus",
+		"first done show bogus", "second done show bogus",
+		"first start show version", "second start show version",
+		"first done show version", "second done show version",
+	}
+	if !slices.Equal(calls, want) {
+		t.Errorf("hook calls = %q, want %q", calls, want)
+	}
+	if len(first.done) != 3 {
+		t.Fatalf("%d done calls, want 3", len(first.done))
+	}
+	for i, info := range first.done {
+		if info.Addr != "pipe" || info.Bytes == 0 || info.Duration <= 0 {
+			t.Errorf("done info %d = %+v, want the address, bytes received and duration", i, info)
+		}
+	}
+	var rejected *client.CommandRejectedError
+	if first.done[0].Err != nil || !errors.As(first.done[1].Err, &rejected) || first.done[2].Err != nil {
+		t.Errorf("done errors = %v, %v, %v, want only the rejected command's", first.done[0].Err, first.done[1].Err, first.done[2].Err)
+	}
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// HostKeyChangedError is returned when the switch presents a host key that
+// differs from the one recorded in known_hosts, which may indicate a MITM attempt.
+type HostKeyChangedError struct {
+	Host   string                // Host as passed to the SSH dialer
+	Key    ssh.PublicKey         // Key presented by the remote host
+	Known  []knownhosts.KnownKey // Keys previously recorded for the host
+	Pinned []string              // Fingerprints set with WithHostKeyFingerprint
+}
+
+func (e *HostKeyChangedError) Error() string {
+	if len(e.Pinned) > 0 {
+		return fmt.Sprintf("host key for %s does not match pinned fingerprint (got %s)",
+			e.Host, ssh.FingerprintSHA256(e.Key))
+	}
+	if len(e.Known) == 0 {
+		return fmt.Sprintf("host key for %s has changed", e.Host)
+	}
+	k := e.Known[0]
+	return fmt.Sprintf("host key for %s has changed (%s %s, recorded in %s:%d)",
+		e.Host, e.Key.Type(), ssh.FingerprintSHA256(e.Key), k.Filename, k.Line)
+}
+
+// hostKeyCallback builds the host key verification callback for Connect.
+func (c *Client) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	cb, err := c.knownHostsCallback()
+	if err != nil || len(c.fingerprints) == 0 {
+		return cb, err
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		got := ssh.FingerprintSHA256(key)
+		if !slices.Contains(c.fingerprints, got) {
+			return &HostKeyChangedError{Host: hostname, Key: key, Pinned: c.fingerprints}
+		}
+		return cb(hostname, remote, key)
+	}, nil
+}
+
+// normalizeFingerprint returns fp in the "SHA256:<base64>" form produced by
+// ssh.FingerprintSHA256, accepting it with or without prefix and padding.
+func normalizeFingerprint(fp string) string {
+	fp = strings.TrimSpace(fp)
+	if len(fp) > 7 && strings.EqualFold(fp[:7], "SHA256:") {
+		fp = fp[7:]
+	}
+	return "SHA256:" + strings.TrimRight(fp, "=")
+}
+
+// knownHostsCallback verifies keys against known_hosts, or accepts any key
+// when no file is configured.
+func (c *Client) knownHostsCallback() (ssh.HostKeyCallback, error) {
+	if c.knownHostsPath == "" {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	if c.trustOnFirstUse {
+		if err := ensureFile(c.knownHostsPath); err != nil {
+			return nil, fmt.Errorf("known_hosts: %w", err)
+		}
+	}
+
+	verify, err := knownhosts.New(c.knownHostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("known_hosts: %w", err)
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := verify(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if !errors.As(err, &keyErr) {
+			return err
+		}
+		if len(keyErr.Want) > 0 {
+			return &HostKeyChangedError{Host: hostname, Key: key, Known: keyErr.Want}
+		}
+		if !c.trustOnFirstUse {
+			return fmt.Errorf("unknown host key for %s: %w", hostname, err)
+		}
+		c.log().Warn("Trusting new host key", "host", hostname, "fingerprint", ssh.FingerprintSHA256(key))
+		return appendKnownHost(c.knownHostsPath, hostname, remote, key)
+	}, nil
+}
+
+// appendKnownHost records key for hostname (and its remote address) in path.
+func appendKnownHost(path, hostname string, remote net.Addr, key ssh.PublicKey) error {
+	addrs := []string{knownhosts.Normalize(hostname)}
+	if remote != nil {
+		if ra := knownhosts.Normalize(remote.String()); ra != addrs[0] {
+			addrs = append(addrs, ra)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("known_hosts: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintln(f, knownhosts.Line(addrs, key)); err != nil {
+		return fmt.Errorf("known_hosts: %w", err)
+	}
+	return nil
+}
+
+// ensureFile creates path and its parent directories if they do not exist.
+func ensureFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
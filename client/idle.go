@@ -0,0 +1,44 @@
+package client
+
+import (
+	"time"
+)
+
+// touch records activity and (re)arms the idle timer. The caller holds c.mu.
+func (c *Client) touch() {
+	c.lastUsed = time.Now()
+	if c.idleTimeout <= 0 {
+		return
+	}
+	if c.idleTimer == nil {
+		c.idleTimer = time.AfterFunc(c.idleTimeout, c.closeIdle)
+	} else {
+		c.idleTimer.Reset(c.idleTimeout)
+	}
+}
+
+// closeIdle closes the session if it has not been used for the idle timeout;
+// the next command reconnects.
+func (c *Client) closeIdle() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.done == nil || c.idle {
+		return
+	}
+	if left := c.idleTimeout - time.Since(c.lastUsed); left > 0 {
+		c.idleTimer.Reset(left)
+		return
+	}
+	c.log().Info("Closing idle session", "host", c.Addr, "idle", c.idleTimeout)
+	c.close()
+	c.idle = true
+}
+
+// stopIdleTimer disarms the idle timer. The caller holds c.mu.
+func (c *Client) stopIdleTimer() {
+	if c.idleTimer != nil {
+		c.idleTimer.Stop()
+		c.idleTimer = nil
+	}
+	c.idle = false
+}
@@ -0,0 +1,54 @@
+package client_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+func TestIdleTimeoutRedials(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.EnablePassword = "s3cret"
+	sw.SetResponse("show version", "Firmware 1.0")
+	c := pipeSwitch(t, sw, client.WithEnablePassword("s3cret"), client.WithIdleTimeout(50*time.Millisecond))
+
+	ctx := context.Background()
+	if _, err := c.RunCommand(ctx, "enable"); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if n := sw.Sessions(); n != 0 {
+		t.Fatalf("%d sessions open after the idle timeout, want 0", n)
+	}
+
+	f, err := c.RunCommandFrame(ctx, "show version")
+	if err != nil {
+		t.Fatalf("RunCommandFrame after idle close: %v", err)
+	}
+	if f.Payload != "Firmware 1.0" || f.Prompt != "SG2210XMP-M2#" {
+		t.Errorf("frame = %+v, want the output in privileged mode", f)
+	}
+	if n := sw.Sessions(); n != 1 {
+		t.Errorf("%d sessions open after redialing, want 1", n)
+	}
+	want := []string{"enable", "enable", "show version"}
+	if cmds := sw.Commands(); !slices.Equal(cmds, want) {
+		t.Errorf("commands = %q, want %q", cmds, want)
+	}
+}
+
+func TestIdleTimeoutCloseWhileIdle(t *testing.T) {
+	sw := testutil.NewSwitch()
+	c := pipeSwitch(t, sw, client.WithIdleTimeout(20*time.Millisecond))
+
+	time.Sleep(60 * time.Millisecond)
+	c.Close()
+	time.Sleep(60 * time.Millisecond)
+	if n := sw.Sessions(); n != 0 {
+		t.Errorf("%d sessions open after Close, want 0", n)
+	}
+}
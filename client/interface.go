@@ -1,13 +1,15 @@
-// Package client provides the interface definition for mocking.
 package client
 
 import (
 	"context"
 )
 
-// Interface defines the minimal SSH interaction contract.
+// Interface defines the minimal switch CLI interaction contract, allowing
+// transports and callers to be mocked.
 type Interface interface {
 	Connect(ctx context.Context) error
 	RunCommand(ctx context.Context, command string) (string, error)
 	Close()
 }
+
+var _ Interface = (*Client)(nil)
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// keepAliver is implemented by transports that can probe an idle connection.
+type keepAliver interface {
+	// keepAliveFunc returns a probe bound to the currently open connection.
+	keepAliveFunc() func() error
+}
+
+// startKeepAlive probes the connection every c.keepAlive until Close, marking
+// the client as disconnected when a probe fails.
+func (c *Client) startKeepAlive() {
+	ka, ok := c.transport.(keepAliver)
+	if !ok || c.keepAlive <= 0 {
+		return
+	}
+	probe := ka.keepAliveFunc()
+	done := c.done
+	lost := c.lost
+
+	go func() {
+		ticker := time.NewTicker(c.keepAlive)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := probe(); err != nil {
+					c.log().Warn("Keepalive failed", "host", c.Addr, "error", err)
+					lost.Store(true)
+					return
+				}
+			}
+		}
+	}()
+}
+
+// reconnect re-establishes a lost session and restores privileged mode if
+// the previous session had entered it. The caller holds c.mu.
+func (c *Client) reconnect(ctx context.Context) error {
+	privileged := c.privileged()
+	c.log().InfoContext(ctx, "Reconnecting lost session", "host", c.Addr)
+	c.close()
+	c.outBuf.Reset()
+	if err := c.connect(ctx); err != nil {
+		return fmt.Errorf("reconnect failed: %w", err)
+	}
+	c.outBuf.Reset()
+	if privileged {
+		if _, err := c.runCommand(ctx, "enable", commandConfig{}); err != nil {
+			return fmt.Errorf("reconnect failed: enable: %w", err)
+		}
+	}
+	return nil
+}
+
+// privileged reports whether the last prompt seen was a privileged (#) prompt.
+func (c *Client) privileged() bool {
+	return strings.HasSuffix(c.prompt, "#")
+}
+
+// markLost marks the current session lost, so that WithAutoReconnect
+// replaces it before the next command.
+func (c *Client) markLost() {
+	if c.lost != nil {
+		c.lost.Store(true)
+	}
+}
@@ -0,0 +1,79 @@
+package client_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+func TestAutoReconnectReentersEnable(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.EnablePassword = "s3cret"
+	sw.SetResponse("show version", "Firmware 1.0")
+	c := pipeSwitch(t, sw, client.WithEnablePassword("s3cret"), client.WithAutoReconnect())
+
+	ctx := context.Background()
+	if _, err := c.RunCommand(ctx, "enable"); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	sw.Drop()
+	time.Sleep(20 * time.Millisecond)
+
+	f, err := c.RunCommandFrame(ctx, "show version")
+	if err != nil {
+		t.Fatalf("RunCommandFrame after drop: %v", err)
+	}
+	if f.Payload != "Firmware 1.0" || f.Prompt != "SG2210XMP-M2#" {
+		t.Errorf("frame = %+v, want the output in privileged mode", f)
+	}
+	want := []string{"enable", "enable", "show version"}
+	if cmds := sw.Commands(); !slices.Equal(cmds, want) {
+		t.Errorf("commands = %q, want %q", cmds, want)
+	}
+}
+
+func TestAutoReconnectStaysInUserMode(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.SetResponse("show version", "Firmware 1.0")
+	c := pipeSwitch(t, sw, client.WithAutoReconnect())
+
+	sw.Drop()
+	time.Sleep(20 * time.Millisecond)
+	f, err := c.RunCommandFrame(context.Background(), "show version")
+	if err != nil {
+		t.Fatalf("RunCommandFrame after drop: %v", err)
+	}
+	if f.Prompt != "SG2210XMP-M2>" {
+		t.Errorf("prompt = %q, want user mode kept", f.Prompt)
+	}
+	if cmds := sw.Commands(); !slices.Equal(cmds, []string{"show version"}) {
+		t.Errorf("commands = %q, want no enable", cmds)
+	}
+}
+
+func TestKeepAliveMarksDroppedSession(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.SetResponse("show version", "Firmware 1.0")
+	c := pipeSwitch(t, sw, client.WithKeepAlive(10*time.Millisecond), client.WithAutoReconnect())
+
+	ctx := context.Background()
+	if _, err := c.RunCommand(ctx, "show version"); err != nil {
+		t.Fatalf("RunCommand: %v", err)
+	}
+	sw.Drop()
+	time.Sleep(50 * time.Millisecond)
+	if n := sw.Sessions(); n != 0 {
+		t.Fatalf("%d sessions open after the drop, want 0", n)
+	}
+	out, err := c.RunCommand(ctx, "show version")
+	if err != nil {
+		t.Fatalf("RunCommand after drop: %v", err)
+	}
+	if out != "Firmware 1.0" || sw.Sessions() != 1 {
+		t.Errorf("output = %q with %d sessions, want a fresh session", out, sw.Sessions())
+	}
+}
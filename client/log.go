@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// redacted replaces credentials in logged text.
+const redacted = "[REDACTED]"
+
+// log returns the configured logger, discarding output when none is set.
+func (c *Client) log() *slog.Logger {
+	if c.logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return c.logger
+}
+
+// redact removes the configured credentials, and the secrets of the
+// running command, from s before it is logged.
+func (c *Client) redact(s string) string {
+	for _, secret := range append([]string{c.Password, c.EnablePassword}, c.secrets...) {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	return s
+}
+
+// debugRaw logs a chunk of raw switch output, skipping the conversion and
+// redaction work when debug logging is off.
+func (c *Client) debugRaw(ctx context.Context, data []byte) {
+	if l := c.log(); l.Enabled(ctx, slog.LevelDebug) {
+		l.DebugContext(ctx, "Received raw", "raw", c.redact(string(data)))
+	}
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// sendLine writes line and a CRLF to the session, waiting out the command
+// delay since the last command finished and pacing characters if so
+// configured. The caller holds c.mu.
+func (c *Client) sendLine(ctx context.Context, line string) error {
+	if c.stdin == nil {
+		return ErrNotConnected
+	}
+	if c.commandDelay > 0 && !c.lastUsed.IsZero() {
+		if err := sleep(ctx, c.commandDelay-time.Since(c.lastUsed)); err != nil {
+			return err
+		}
+	}
+	data := line + "\r\n"
+	if c.charDelay <= 0 {
+		_, err := io.WriteString(c.stdin, data)
+		return err
+	}
+	for i := range len(data) {
+		if i > 0 {
+			if err := sleep(ctx, c.charDelay); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(c.stdin, data[i:i+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,123 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+// timedWrite is one write to the switch and when it happened.
+type timedWrite struct {
+	data string
+	at   time.Time
+}
+
+// writeLog is a transport recording the writes made to it.
+type writeLog struct {
+	client.Transport
+
+	mu     sync.Mutex
+	writes []timedWrite
+}
+
+func (l *writeLog) Open(ctx context.Context) (io.Writer, io.Reader, error) {
+	w, r, err := l.Transport.Open(ctx)
+	return writerFunc(func(p []byte) (int, error) {
+		l.mu.Lock()
+		l.writes = append(l.writes, timedWrite{string(p), time.Now()})
+		l.mu.Unlock()
+		return w.Write(p)
+	}), r, err
+}
+
+// since returns the writes made from the i-th on.
+func (l *writeLog) since(i int) []timedWrite {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]timedWrite(nil), l.writes[i:]...)
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+// pacedSwitch returns a client connected to a switch answering "show
+// version" through a writeLog transport.
+func pacedSwitch(t *testing.T, opts ...client.Option) (*client.Client, *writeLog) {
+	t.Helper()
+	sw := testutil.NewSwitch()
+	sw.SetResponse("show version", "Firmware 1.0")
+	t.Cleanup(func() { sw.Close() })
+	l := &writeLog{Transport: sw.Transport()}
+	c := client.NewClient("pipe", sw.User, sw.Password, append([]client.Option{client.WithTransport(l)}, opts...)...)
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(c.Close)
+	return c, l
+}
+
+func TestCharDelay(t *testing.T) {
+	const d = 10 * time.Millisecond
+	c, l := pacedSwitch(t, client.WithCharDelay(d))
+
+	out, err := c.RunCommand(context.Background(), "show version")
+	if err != nil {
+		t.Fatalf("RunCommand: %v", err)
+	}
+	if out != "Firmware 1.0" {
+		t.Errorf("output = %q", out)
+	}
+	writes := l.since(0)
+	if len(writes) != len("show version\r\n") {
+		t.Fatalf("%d writes, want one per character", len(writes))
+	}
+	for i := 1; i < len(writes); i++ {
+		if gap := writes[i].at.Sub(writes[i-1].at); gap < d {
+			t.Errorf("write %d %q after %v, want at least %v", i, writes[i].data, gap, d)
+		}
+	}
+}
+
+func TestCommandDelay(t *testing.T) {
+	const d = 100 * time.Millisecond
+	c, l := pacedSwitch(t, client.WithCommandDelay(d))
+
+	ctx := context.Background()
+	if _, err := c.RunCommand(ctx, "show version"); err != nil {
+		t.Fatalf("RunCommand: %v", err)
+	}
+	done := time.Now()
+	if _, err := c.RunCommand(ctx, "show version"); err != nil {
+		t.Fatalf("second RunCommand: %v", err)
+	}
+	writes := l.since(1)
+	if len(writes) != 1 || writes[0].data != "show version\r\n" {
+		t.Fatalf("writes = %v, want the command line whole", writes)
+	}
+	if gap := writes[0].at.Sub(done); gap < d-5*time.Millisecond {
+		t.Errorf("second command sent %v after the first finished, want at least %v", gap, d)
+	}
+}
+
+func TestCommandDelayCancelled(t *testing.T) {
+	c, l := pacedSwitch(t, client.WithCommandDelay(time.Second))
+
+	if _, err := c.RunCommand(context.Background(), "show version"); err != nil {
+		t.Fatalf("RunCommand: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.RunCommand(ctx, "show version"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded while waiting out the delay", err)
+	}
+	if n := len(l.since(1)); n != 0 {
+		t.Errorf("%d writes, want the command not sent", n)
+	}
+}
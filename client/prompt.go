@@ -0,0 +1,40 @@
+package client
+
+import (
+	"regexp"
+)
+
+// genericPromptRegex matches any hostname-style CLI prompt, used until the
+// real prompt has been learned.
+var genericPromptRegex = regexp.MustCompile(`(?m)[\r\n]*(([A-Za-z0-9][\w.\-]*)(\([^)]*\))?[>#])\s*$`)
+
+// promptPattern returns the regular expression matching the CLI prompt.
+func (c *Client) promptPattern() *regexp.Regexp {
+	switch {
+	case c.promptRe != nil:
+		return c.promptRe
+	case c.learnPrompt:
+		return genericPromptRegex
+	default:
+		return promptRegex
+	}
+}
+
+// learnPromptFrom builds the prompt pattern from the hostname in a prompt
+// matched by genericPromptRegex, so later matches ignore look-alike output.
+func (c *Client) learnPromptFrom(prompt string) {
+	if !c.learnPrompt || c.promptRe != nil {
+		return
+	}
+	m := genericPromptRegex.FindStringSubmatch(prompt)
+	if m == nil {
+		return
+	}
+	c.promptRe = promptRegexFor(m[2])
+}
+
+// promptRegexFor returns a pattern matching the user, privileged and
+// configuration mode prompts of a switch named hostname.
+func promptRegexFor(hostname string) *regexp.Regexp {
+	return regexp.MustCompile(`(?m)[\r\n]*(` + regexp.QuoteMeta(hostname) + `(\([^)]*\))?[>#])\s*$`)
+}
@@ -0,0 +1,23 @@
+package client
+
+import "testing"
+
+func TestPromptRegexFor(t *testing.T) {
+	re := promptRegexFor("core1.lab")
+	for _, tc := range []struct {
+		out  string
+		want bool
+	}{
+		{"\r\ncore1.lab>", true},
+		{"\r\ncore1.lab#", true},
+		{"\r\ncore1.lab(config)# ", true},
+		{"\r\ncore1.lab(config-if)#", true},
+		{"\r\ncore1xlab#", false},
+		{"\r\nedge2#", false},
+		{"\r\ncore1.lab# show version", false},
+	} {
+		if got := re.MatchString(tc.out); got != tc.want {
+			t.Errorf("match %q = %v, want %v", tc.out, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// Dialer opens the network connection that carries the SSH transport.
+// *net.Dialer satisfies it, as do the SOCKS5 dialers from golang.org/x/net/proxy.
+type Dialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// ProxyDialer returns a Dialer that routes connections through the proxy at
+// rawURL. Supported schemes are socks5, socks5h, http and https (HTTP CONNECT,
+// over TLS to the proxy for https). Credentials may be embedded in the URL.
+// A nil forward dials the proxy directly.
+func ProxyDialer(rawURL string, forward Dialer) (Dialer, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("proxy URL: %w", err)
+	}
+	if forward == nil {
+		forward = &net.Dialer{}
+	}
+
+	switch u.Scheme {
+	case "socks5", "socks5h":
+		d, err := proxy.FromURL(u, contextForwarder{forward})
+		if err != nil {
+			return nil, fmt.Errorf("proxy: %w", err)
+		}
+		cd, ok := d.(proxy.ContextDialer)
+		if !ok {
+			return nil, fmt.Errorf("proxy: %s dialer does not support contexts", u.Scheme)
+		}
+		return cd, nil
+	case "http":
+		host := u.Host
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "8080")
+		}
+		return &httpConnectDialer{proxyAddr: host, user: u.User, forward: forward}, nil
+	case "https":
+		host := u.Host
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		}
+		tlsConfig := &tls.Config{ServerName: u.Hostname()}
+		return &httpConnectDialer{proxyAddr: host, user: u.User, forward: forward, tls: tlsConfig}, nil
+	default:
+		return nil, fmt.Errorf("proxy: unsupported scheme %q", u.Scheme)
+	}
+}
+
+// contextForwarder adapts a Dialer to proxy.Dialer while keeping context support.
+type contextForwarder struct {
+	Dialer
+}
+
+func (f contextForwarder) Dial(network, addr string) (net.Conn, error) {
+	return f.DialContext(context.Background(), network, addr)
+}
+
+// httpConnectDialer tunnels connections through an HTTP proxy using CONNECT.
+type httpConnectDialer struct {
+	proxyAddr string
+	user      *url.Userinfo
+	forward   Dialer
+	tls       *tls.Config // TLS to the proxy, nil for plain http
+}
+
+func (d *httpConnectDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := d.forward.DialContext(ctx, "tcp", d.proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("proxy dial failed: %w", err)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if d.user != nil {
+		pass, _ := d.user.Password()
+		cred := base64.StdEncoding.EncodeToString([]byte(d.user.Username() + ":" + pass))
+		req.Header.Set("Proxy-Authorization", "Basic "+cred)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+	if d.tls != nil {
+		// Credentials and the CONNECT request only travel encrypted.
+		tc := tls.Client(conn, d.tls)
+		if err := tc.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("proxy TLS handshake failed: %w", err)
+		}
+		conn = tc
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT failed: %w", err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT failed: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT to %s failed: %s", addr, resp.Status)
+	}
+
+	// The SSH server speaks first, so its banner may already be buffered.
+	return &bufferedConn{Conn: conn, r: br}, nil
+}
+
+// bufferedConn is a net.Conn whose reads drain a bufio.Reader first.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// connectProxy returns a handler tunnelling CONNECT requests, recording the
+// Proxy-Authorization header of each in auth.
+func connectProxy(t *testing.T, auth chan<- string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+			return
+		}
+		auth <- r.Header.Get("Proxy-Authorization")
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		go func() {
+			io.Copy(target, buf)
+			target.Close()
+		}()
+		io.Copy(conn, target)
+		conn.Close()
+	})
+}
+
+// echoServer returns the address of a listener writing back what it reads.
+func echoServer(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestProxyDialerHTTPS(t *testing.T) {
+	auth := make(chan string, 1)
+	srv := httptest.NewTLSServer(connectProxy(t, auth))
+	defer srv.Close()
+
+	d, err := ProxyDialer("https://user:pw@"+srv.Listener.Addr().String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hd := d.(*httpConnectDialer)
+	if hd.tls == nil {
+		t.Fatal("https proxy dialed without TLS")
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	hd.tls.RootCAs = roots
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := d.DialContext(ctx, "tcp", echoServer(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if got := <-auth; got != "Basic dXNlcjpwdw==" {
+		t.Errorf("Proxy-Authorization = %q", got)
+	}
+	if _, err := io.WriteString(conn, "ping"); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+		t.Errorf("tunnel read %q, %v", buf, err)
+	}
+}
+
+func TestProxyDialerHTTPSRejectsUntrustedProxy(t *testing.T) {
+	auth := make(chan string, 1)
+	srv := httptest.NewUnstartedServer(connectProxy(t, auth))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0) // the failed handshake
+	srv.StartTLS()
+	defer srv.Close()
+
+	d, err := ProxyDialer("https://user:pw@"+srv.Listener.Addr().String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.DialContext(context.Background(), "tcp", "192.0.2.1:22"); err == nil {
+		t.Fatal("dial through a proxy with an untrusted certificate succeeded")
+	}
+	select {
+	case <-auth:
+		t.Error("credentials sent to an unverified proxy")
+	default:
+	}
+}
+
+func TestProxyDialerDefaultPorts(t *testing.T) {
+	for _, tc := range []struct {
+		url, addr string
+		tls       bool
+	}{
+		{"http://proxy.example", "proxy.example:8080", false},
+		{"https://proxy.example", "proxy.example:443", true},
+		{"https://proxy.example:8443", "proxy.example:8443", true},
+	} {
+		d, err := ProxyDialer(tc.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hd := d.(*httpConnectDialer)
+		if hd.proxyAddr != tc.addr || (hd.tls != nil) != tc.tls {
+			t.Errorf("%s: addr %s, TLS %v; want %s, %v", tc.url, hd.proxyAddr, hd.tls != nil, tc.addr, tc.tls)
+		}
+		if tc.tls && hd.tls.ServerName != "proxy.example" {
+			t.Errorf("%s: SNI %q", tc.url, hd.tls.ServerName)
+		}
+	}
+}
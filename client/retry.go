@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// RetryPolicy controls how failed connects and commands are retried.
+// Retrying a command re-sends it, so configuration commands that are not
+// idempotent should use a Retryable func that excludes them.
+type RetryPolicy struct {
+	Attempts   int              // Total attempts; values below 2 disable retries
+	Backoff    time.Duration    // Delay before the first retry
+	MaxBackoff time.Duration    // Upper bound for the delay, 0 for none
+	Multiplier float64          // Delay growth per retry, defaults to 2
+	Jitter     float64          // Random fraction (0-1) of the delay added or removed
+	Retryable  func(error) bool // Decides whether err is retried, defaults to IsRetryable
+}
+
+// IsRetryable reports whether err looks transient: network errors, closed
+// sessions and prompt timeouts. Rejected commands, authentication and host
+// key failures, and context cancellation are not retried.
+func IsRetryable(err error) bool {
+	var keyErr *knownhosts.KeyError
+	var changed *HostKeyChangedError
+	var rejected *CommandRejectedError
+	switch {
+	case err == nil,
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &keyErr),
+		errors.As(err, &changed),
+		errors.As(err, &rejected),
+		errors.Is(err, ErrNotConnected):
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, ErrPromptTimeout) ||
+		errors.Is(err, ErrConnectionClosed) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// delay returns the wait before retry number n (starting at 1).
+func (p RetryPolicy) delay(n int) time.Duration {
+	mult := p.Multiplier
+	if mult <= 0 {
+		mult = 2
+	}
+	d := float64(p.Backoff) * math.Pow(mult, float64(n-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(d)
+}
+
+// retry runs op until it succeeds, the policy gives up or ctx is done.
+// op receives the attempt number, starting at 0.
+func (c *Client) retry(ctx context.Context, what string, op func(attempt int) error) error {
+	p := c.retryPolicy
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := op(attempt)
+		if err == nil || attempt+1 >= p.Attempts || !retryable(err) {
+			return err
+		}
+		d := p.delay(attempt + 1)
+		c.log().WarnContext(ctx, "Retrying after error", "operation", what, "attempt", attempt+1, "delay", d, "error", err)
+
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
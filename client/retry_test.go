@@ -0,0 +1,107 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+// slowSwitch returns a switch answering the first "show slow" only after
+// delay, and later ones at once. calls counts the "show slow" commands run.
+func slowSwitch(delay time.Duration, calls *atomic.Int32) *testutil.Switch {
+	sw := testutil.NewSwitch()
+	sw.Handler = func(_ testutil.Mode, cmd string) (string, bool) {
+		if cmd != "show slow" {
+			return "", false
+		}
+		if calls.Add(1) == 1 {
+			time.Sleep(delay)
+			return "late output", true
+		}
+		return "fresh output", true
+	}
+	return sw
+}
+
+func TestPromptTimeoutRetryReconnects(t *testing.T) {
+	var calls atomic.Int32
+	sw := slowSwitch(500*time.Millisecond, &calls)
+	c := startSwitch(t, sw,
+		client.WithCommandTimeout(150*time.Millisecond),
+		client.WithRetry(client.RetryPolicy{Attempts: 2, Backoff: 10 * time.Millisecond}))
+
+	out, err := c.RunCommand(context.Background(), "show slow")
+	if err != nil {
+		t.Fatalf("RunCommand: %v", err)
+	}
+	if out != "fresh output" {
+		t.Errorf("output = %q, want the retry's own output", out)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("show slow ran %d times, want 2", n)
+	}
+}
+
+func TestPromptTimeoutDropsStaleOutput(t *testing.T) {
+	var calls atomic.Int32
+	sw := slowSwitch(300*time.Millisecond, &calls)
+	sw.SetResponse("show version", "Firmware 1.0")
+	c := startSwitch(t, sw,
+		client.WithCommandTimeout(100*time.Millisecond),
+		client.WithAutoReconnect())
+
+	ctx := context.Background()
+	if _, err := c.RunCommand(ctx, "show slow"); !errors.Is(err, client.ErrPromptTimeout) {
+		t.Fatalf("RunCommand error = %v, want ErrPromptTimeout", err)
+	}
+	time.Sleep(400 * time.Millisecond) // let the late output arrive
+	out, err := c.RunCommand(ctx, "show version")
+	if err != nil {
+		t.Fatalf("RunCommand: %v", err)
+	}
+	if out != "Firmware 1.0" {
+		t.Errorf("output = %q, want only the output of show version", out)
+	}
+}
+
+func TestNoRetry(t *testing.T) {
+	var calls atomic.Int32
+	sw := slowSwitch(500*time.Millisecond, &calls)
+	c := startSwitch(t, sw,
+		client.WithCommandTimeout(150*time.Millisecond),
+		client.WithRetry(client.RetryPolicy{Attempts: 3, Backoff: 10 * time.Millisecond}))
+
+	_, err := c.RunCommandFrame(context.Background(), "show slow", client.NoRetry())
+	if !errors.Is(err, client.ErrPromptTimeout) {
+		t.Fatalf("error = %v, want ErrPromptTimeout", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if n := calls.Load(); n != 1 {
+		t.Errorf("show slow ran %d times, want 1", n)
+	}
+}
+
+func TestRejectedCommandLeavesNoOutput(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.SetResponse("show version", "Firmware 1.0")
+	c := startSwitch(t, sw)
+
+	ctx := context.Background()
+	var rejected *client.CommandRejectedError
+	if _, err := c.RunCommand(ctx, "show bogus"); !errors.As(err, &rejected) {
+		t.Fatalf("error = %v, want *CommandRejectedError", err)
+	}
+	out, err := c.RunCommand(ctx, "show version")
+	if err != nil {
+		t.Fatalf("RunCommand: %v", err)
+	}
+	if strings.Contains(out, "Bad command") || out != "Firmware 1.0" {
+		t.Errorf("output = %q, want only the output of show version", out)
+	}
+}
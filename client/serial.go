@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// defaultBaudRate is the console speed TP-Link switches ship with (38400 8N1).
+const defaultBaudRate = 38400
+
+// NewSerialClient returns a Client that talks to the switch console on a
+// local serial device such as /dev/ttyUSB0, for switches without an IP yet.
+// Username and password are entered if the console asks for them.
+func NewSerialClient(device, user, password string, opts ...Option) *Client {
+	return newClient(&serialTransport{}, device, user, password, opts)
+}
+
+// serialTransport runs the switch CLI over a serial console line.
+type serialTransport struct {
+	port *os.File
+}
+
+func (t *serialTransport) open(ctx context.Context, c *Client) (io.Writer, io.Reader, error) {
+	baud := c.baudRate
+	if baud == 0 {
+		baud = defaultBaudRate
+	}
+	port, err := openSerial(c.Addr, baud)
+	if err != nil {
+		return nil, nil, fmt.Errorf("serial open failed: %w", err)
+	}
+	t.port = port
+
+	// A console that is already logged in stays silent until it sees a newline.
+	if _, err := fmt.Fprint(port, "\r\n"); err != nil {
+		return nil, nil, err
+	}
+	return port, consoleReader{port}, nil
+}
+
+// consoleReader hides the empty reads a tty returns when its read timer
+// expires, which os.File reports as io.EOF.
+type consoleReader struct {
+	f *os.File
+}
+
+func (r consoleReader) Read(p []byte) (int, error) {
+	n, err := r.f.Read(p)
+	if n == 0 && err == io.EOF {
+		return 0, nil
+	}
+	return n, err
+}
+
+func (t *serialTransport) close() {
+	if t.port != nil {
+		t.port.Close()
+		t.port = nil
+	}
+}
+
+func (t *serialTransport) interactiveLogin() bool { return true }
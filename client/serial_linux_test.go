@@ -0,0 +1,115 @@
+package client_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/pascal71/tplink-go/client"
+)
+
+// openPTY returns the master of a new pseudo-terminal and the path of its
+// slave, which stands in for the serial device. The test is skipped when
+// the system has no pseudo-terminals.
+func openPTY(t *testing.T) (*os.File, string) {
+	t.Helper()
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminals: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		t.Skipf("unlockpt: %v", err)
+	}
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		t.Skipf("ptsname: %v", err)
+	}
+	return master, fmt.Sprintf("/dev/pts/%d", n)
+}
+
+// serveConsole plays a switch console on the pty master: it waits for the
+// client's newline, asks for credentials and answers "show version".
+func serveConsole(console io.ReadWriter, lines chan<- string) {
+	r := bufio.NewReader(console)
+	readLine := func() (string, bool) {
+		l, err := r.ReadString('\n')
+		if err != nil {
+			return "", false
+		}
+		l = strings.TrimRight(l, "\r\n")
+		lines <- l
+		return l, true
+	}
+	if _, ok := readLine(); !ok {
+		return
+	}
+	io.WriteString(console, "\r\nUser:")
+	if _, ok := readLine(); !ok {
+		return
+	}
+	io.WriteString(console, "\r\nPassword:")
+	if _, ok := readLine(); !ok {
+		return
+	}
+	io.WriteString(console, "\r\nSG2210XMP-M2>")
+	for {
+		cmd, ok := readLine()
+		if !ok {
+			return
+		}
+		out := cmd + "\r\n"
+		if cmd == "show version" {
+			out += "Firmware 1.0\r\n"
+		}
+		io.WriteString(console, out+"\r\nSG2210XMP-M2>")
+	}
+}
+
+func TestSerialConsole(t *testing.T) {
+	master, device := openPTY(t)
+	lines := make(chan string, 16)
+	go serveConsole(master, lines)
+
+	c := client.NewSerialClient(device, "admin", "s3cret", client.WithBaudRate(115200), client.WithCommandTimeout(2*time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer c.Close()
+
+	out, err := c.RunCommand(ctx, "show version")
+	if err != nil {
+		t.Fatalf("RunCommand: %v", err)
+	}
+	if out != "Firmware 1.0" {
+		t.Errorf("output = %q", out)
+	}
+	for _, want := range []string{"", "admin", "s3cret", "show version"} {
+		if got := <-lines; got != want {
+			t.Errorf("console read %q, want %q", got, want)
+		}
+	}
+}
+
+func TestSerialBaudRate(t *testing.T) {
+	_, device := openPTY(t)
+	c := client.NewSerialClient(device, "admin", "admin", client.WithBaudRate(12345))
+	err := c.Connect(context.Background())
+	if err == nil {
+		c.Close()
+		t.Fatal("Connect succeeded at an unsupported baud rate")
+	}
+	if !strings.Contains(err.Error(), "unsupported baud rate 12345") {
+		t.Errorf("error = %v, want unsupported baud rate", err)
+	}
+}
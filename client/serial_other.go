@@ -0,0 +1,13 @@
+//go:build !linux
+
+package client
+
+import (
+	"errors"
+	"os"
+)
+
+// openSerial is only implemented on Linux.
+func openSerial(device string, baud int) (*os.File, error) {
+	return nil, errors.New("serial consoles are only supported on linux")
+}
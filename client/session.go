@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// NewSession opens another PTY session on c's SSH connection and returns it
+// as a connected Client, so a long-running stream such as "show logging" and
+// regular polling don't block each other. The session has its own prompt
+// and starts in user mode, with the options c was given, including those
+// applied later with Apply. It shares the connection: closing c ends it,
+// while closing the session leaves c open. Firmware limits how many
+// sessions a connection may carry; NewSession fails once that is reached.
+//
+// NewSession does not wait for commands running on c.
+func (c *Client) NewSession(ctx context.Context) (*Client, error) {
+	t, ok := c.transport.(*sshTransport)
+	if !ok {
+		return nil, errors.New("sessions require an SSH connection")
+	}
+	s := newClient(&sshTransport{shared: t}, c.Addr, c.User, c.Password, c.opts)
+	s.EnablePassword = c.EnablePassword
+	if err := s.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("new session: %w", err)
+	}
+	return s, nil
+}
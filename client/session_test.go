@@ -0,0 +1,58 @@
+package client_test
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+func TestNewSessionKeepsOptions(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.Hostname = "core1"
+	sw.SetResponse("show version", "Firmware 1.0")
+	var started []string
+	c := startSwitch(t, sw,
+		client.WithPromptRegex(regexp.MustCompile(`core1(\([^)]*\))?[>#]`)),
+		client.WithHooks(client.Hooks{OnCommandStart: func(ctx context.Context, info client.CommandStartInfo) context.Context {
+			started = append(started, info.Command)
+			return nil
+		}}))
+
+	ctx := context.Background()
+	s, err := c.NewSession(ctx)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	f, err := s.RunCommandFrame(ctx, "show version")
+	if err != nil {
+		t.Fatalf("RunCommandFrame on the session: %v", err)
+	}
+	if f.Prompt != "core1>" {
+		t.Errorf("prompt = %q, want the prompt pattern kept", f.Prompt)
+	}
+	s.Close()
+
+	// Options applied later carry over too.
+	c.Apply(client.WithDryRun())
+	s, err = c.NewSession(ctx)
+	if err != nil {
+		t.Fatalf("NewSession after Apply: %v", err)
+	}
+	defer s.Close()
+	if _, err := s.RunCommand(ctx, "reboot"); err != nil {
+		t.Fatalf("RunCommand in dry-run mode: %v", err)
+	}
+	if got := s.DryRunCommands(); !slices.Equal(got, []string{"reboot"}) {
+		t.Errorf("DryRunCommands() = %q, want the command recorded", got)
+	}
+	if want := []string{"show version"}; !slices.Equal(sw.Commands(), want) {
+		t.Errorf("switch got %q, want %q", sw.Commands(), want)
+	}
+	if want := []string{"show version", "reboot"}; !slices.Equal(started, want) {
+		t.Errorf("hooks saw %q, want %q", started, want)
+	}
+}
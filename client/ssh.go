@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// sshTransport runs the switch CLI in an interactive SSH shell with a PTY.
+type sshTransport struct {
+	mu      sync.Mutex    // guards conn for NewSession, which runs without Client.mu
+	conn    *ssh.Client   // Underlying SSH connection
+	session *ssh.Session  // SSH session with PTY
+	shared  *sshTransport // owner of conn for sessions opened with NewSession
+}
+
+func (t *sshTransport) open(ctx context.Context, c *Client) (io.Writer, io.Reader, error) {
+	if t.shared != nil {
+		conn := t.client()
+		if conn == nil {
+			return nil, nil, fmt.Errorf("SSH session failed: parent %w", ErrNotConnected)
+		}
+		return t.shell(conn)
+	}
+
+	hostKeyCallback, err := c.hostKeyCallback()
+	if err != nil {
+		return nil, nil, err
+	}
+	auth, release, err := c.authMethods()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	cfg := &ssh.ClientConfig{
+		User:            c.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         5 * time.Second,
+	}
+	c.algorithms.apply(cfg)
+
+	conn, err := c.dial(ctx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("SSH dial failed: %w", err)
+	}
+	t.mu.Lock()
+	t.conn = conn
+	t.mu.Unlock()
+	return t.shell(conn)
+}
+
+// shell starts an interactive shell with a PTY on conn.
+func (t *sshTransport) shell(conn *ssh.Client) (io.Writer, io.Reader, error) {
+	sess, err := conn.NewSession()
+	if err != nil {
+		return nil, nil, fmt.Errorf("SSH session failed: %w", err)
+	}
+	t.session = sess
+
+	stdin, err := sess.StdinPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	stdout, err := sess.StdoutPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 14400,
+		ssh.TTY_OP_OSPEED: 14400,
+	}
+	if err := sess.RequestPty("xterm", 120, 40, modes); err != nil {
+		return nil, nil, err
+	}
+	if err := sess.Shell(); err != nil {
+		return nil, nil, err
+	}
+	return stdin, stdout, nil
+}
+
+func (t *sshTransport) close() {
+	if t.session != nil {
+		t.session.Close()
+		t.session = nil
+	}
+	if t.shared != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conn != nil {
+		t.conn.Close()
+		t.conn = nil
+	}
+}
+
+// client returns the open SSH connection, or nil.
+func (t *sshTransport) client() *ssh.Client {
+	if t.shared != nil {
+		return t.shared.client()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.conn
+}
+
+func (t *sshTransport) interactiveLogin() bool { return false }
+
+func (t *sshTransport) keepAliveFunc() func() error {
+	conn := t.client()
+	return func() error {
+		_, _, err := conn.SendRequest("keepalive@openssh.com", true, nil)
+		return err
+	}
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// maxPartialLine is how much of an unterminated line is held back while
+// waiting to see whether it is the prompt.
+const maxPartialLine = 64 * 1024
+
+// StreamCommand sends cmd and returns a reader yielding its cleaned output,
+// line by line, as it arrives. The echoed command line and the final prompt
+// are not included, and the reader returns io.EOF once the prompt is seen.
+// Without a context deadline, the command timeout applies to each wait for
+// more output rather than to the whole command.
+//
+// Other commands wait until the stream is closed; closing it early discards
+// the rest of the output.
+func (c *Client) StreamCommand(ctx context.Context, cmd string) (io.ReadCloser, error) {
+	c.mu.Lock()
+	if c.dryRun {
+		defer c.mu.Unlock()
+		c.dryRunFrame(ctx, cmd)
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+	if err := c.ensureConnected(ctx); err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	if c.reads == nil {
+		c.mu.Unlock()
+		return nil, ErrNotConnected
+	}
+	c.outBuf.Reset()
+	sp := c.startCommand(ctx, cmd)
+	c.log().InfoContext(ctx, "Streaming command", "command", c.redact(cmd))
+	if err := c.sendLine(ctx, cmd); err != nil {
+		sp.done(0, err)
+		c.mu.Unlock()
+		return nil, err
+	}
+	return &commandStream{
+		c:        c,
+		ctx:      ctx,
+		span:     sp,
+		cmd:      bytes.TrimSpace([]byte(cmd)),
+		skipEcho: true,
+	}, nil
+}
+
+// commandStream reads the output of one streamed command.
+type commandStream struct {
+	c        *Client
+	ctx      context.Context
+	cmd      []byte
+	skipEcho bool   // the next complete line may be the echoed command
+	partial  []byte // raw output after the last newline
+	pending  []byte // cleaned output not yet returned by Read
+	done     bool   // the prompt has been seen
+	closed   bool   // Close has released the client
+	err      error
+	span     *commandSpan
+	received int // raw bytes read so far
+}
+
+func (s *commandStream) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		if s.done {
+			return 0, io.EOF
+		}
+		if s.err != nil {
+			return 0, s.err
+		}
+		s.fill()
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Close discards any remaining output up to the prompt and releases the client.
+func (s *commandStream) Close() error {
+	if s.closed {
+		return s.err
+	}
+	for !s.done && s.err == nil {
+		s.pending = s.pending[:0]
+		s.fill()
+	}
+	s.pending = nil
+	s.closed = true
+	s.span.done(s.received, s.err)
+	s.c.touch()
+	s.c.mu.Unlock()
+	return s.err
+}
+
+// fill waits for the next chunk of output and moves its complete lines to
+// pending. A stream given up on before the prompt marks the session lost, as
+// runCommand does, so the rest of its output is not read as the next
+// command's.
+func (s *commandStream) fill() {
+	data, err := s.next()
+	if err != nil {
+		s.err = err
+		if errors.Is(err, ErrPromptTimeout) || s.ctx.Err() != nil {
+			s.c.markLost()
+		}
+		return
+	}
+	s.received += len(data)
+	s.partial = append(s.partial, data...)
+	putReadBuf(data)
+
+	for {
+		i := bytes.IndexByte(s.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := cleanOutput(s.partial[:i+1])
+		s.partial = s.partial[i+1:]
+		if s.skipEcho {
+			s.skipEcho = false
+			if len(s.cmd) > 0 && bytes.HasSuffix(bytes.TrimSpace(line), s.cmd) {
+				continue
+			}
+		}
+		s.pending = append(s.pending, line...)
+	}
+
+	tail := cleanOutput(s.partial)
+	switch {
+	case s.c.promptPattern().Match(tail):
+		s.c.setPrompt(string(tail))
+		s.partial = nil
+		s.done = true
+	case len(s.partial) > maxPartialLine:
+		s.pending = append(s.pending, tail...)
+		s.partial = s.partial[:0]
+	}
+}
+
+// next returns the next chunk read from the switch.
+func (s *commandStream) next() ([]byte, error) {
+	var timeout <-chan time.Time
+	if _, ok := s.ctx.Deadline(); !ok {
+		timer := time.NewTimer(s.c.timeout())
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	case <-timeout:
+		return nil, ErrPromptTimeout
+	case res, ok := <-s.c.reads:
+		if !ok {
+			return nil, ErrConnectionClosed
+		}
+		if res.data != nil {
+			s.c.debugRaw(s.ctx, res.data)
+		}
+		return res.data, res.err
+	}
+}
+
+// cleanOutput renders terminal control data in b to plain text.
+func cleanOutput(b []byte) []byte {
+	return []byte(render(b))
+}
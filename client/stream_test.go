@@ -0,0 +1,47 @@
+package client_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pascal71/tplink-go/client"
+)
+
+func TestStreamTimeoutMarksSessionLost(t *testing.T) {
+	// Without "no clipaging" the output stalls at the pager after 10 lines.
+	sw, _ := pagedSwitch(35)
+	sw.SetResponse("show version", "Firmware 1.0")
+	c := pipeSwitch(t, sw, client.WithCommandTimeout(200*time.Millisecond), client.WithAutoReconnect())
+
+	ctx := context.Background()
+	r, err := c.StreamCommand(ctx, "show running-config")
+	if err != nil {
+		t.Fatalf("StreamCommand: %v", err)
+	}
+	sc := bufio.NewScanner(r)
+	lines := 0
+	for sc.Scan() {
+		lines++
+	}
+	if !errors.Is(sc.Err(), client.ErrPromptTimeout) {
+		t.Fatalf("stream error = %v, want ErrPromptTimeout", sc.Err())
+	}
+	if lines == 0 {
+		t.Fatal("no output streamed before the timeout")
+	}
+	r.Close()
+
+	out, err := c.RunCommand(ctx, "show version")
+	if err != nil {
+		t.Fatalf("RunCommand after the timed out stream: %v", err)
+	}
+	if out != "Firmware 1.0" {
+		t.Errorf("output = %q, want only the next command's", out)
+	}
+	if n := sw.Sessions(); n != 1 {
+		t.Errorf("%d sessions open, want the lost one replaced", n)
+	}
+}
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// Telnet command and option codes (RFC 854, RFC 857, RFC 858).
+const (
+	telnetIAC  = 255
+	telnetDONT = 254
+	telnetDO   = 253
+	telnetWONT = 252
+	telnetWILL = 251
+	telnetSB   = 250
+	telnetSE   = 240
+	telnetNOP  = 241
+
+	telnetOptEcho = 1
+	telnetOptSGA  = 3
+)
+
+// NewTelnetClient returns a Client that talks to the switch over telnet, for
+// devices without SSH. Username and password are entered at the login prompt.
+// SSH-specific options such as WithKnownHosts or WithJumpHost have no effect.
+func NewTelnetClient(addr, user, password string, opts ...Option) *Client {
+	return newClient(&telnetTransport{}, addr, user, password, opts)
+}
+
+// telnetTransport runs the switch CLI over a raw telnet connection.
+type telnetTransport struct {
+	conn *telnetConn
+}
+
+func (t *telnetTransport) open(ctx context.Context, c *Client) (io.Writer, io.Reader, error) {
+	d := c.dialer
+	if d == nil {
+		d = &net.Dialer{Timeout: 5 * time.Second}
+	}
+	conn, err := d.DialContext(ctx, "tcp", c.Addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("telnet dial failed: %w", err)
+	}
+	t.conn = newTelnetConn(conn)
+	return t.conn, t.conn, nil
+}
+
+func (t *telnetTransport) close() {
+	if t.conn != nil {
+		t.conn.Close()
+		t.conn = nil
+	}
+}
+
+func (t *telnetTransport) interactiveLogin() bool { return true }
+
+func (t *telnetTransport) keepAliveFunc() func() error {
+	conn := t.conn
+	return func() error {
+		conn.mu.Lock()
+		defer conn.mu.Unlock()
+		_, err := conn.Conn.Write([]byte{telnetIAC, telnetNOP})
+		return err
+	}
+}
+
+// telnetConn strips telnet negotiation from the data stream, declining every
+// option except server echo and suppress-go-ahead, and escapes outgoing IAC bytes.
+type telnetConn struct {
+	net.Conn
+	r  *bufio.Reader
+	mu sync.Mutex // serializes writes of data and negotiation replies
+}
+
+func newTelnetConn(conn net.Conn) *telnetConn {
+	return &telnetConn{Conn: conn, r: bufio.NewReader(conn)}
+}
+
+// Read returns application data, answering any negotiation encountered on the way.
+func (t *telnetConn) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if n > 0 && t.r.Buffered() == 0 {
+			break
+		}
+		b, err := t.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		if b != telnetIAC {
+			p[n] = b
+			n++
+			continue
+		}
+
+		cmd, err := t.r.ReadByte()
+		if err != nil {
+			return n, err
+		}
+		switch cmd {
+		case telnetIAC:
+			p[n] = telnetIAC
+			n++
+		case telnetDO, telnetDONT, telnetWILL, telnetWONT:
+			opt, err := t.r.ReadByte()
+			if err != nil {
+				return n, err
+			}
+			if err := t.negotiate(cmd, opt); err != nil {
+				return n, err
+			}
+		case telnetSB:
+			if err := t.skipSubnegotiation(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// Write sends p to the switch, doubling any IAC bytes.
+func (t *telnetConn) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	escaped := bytes.ReplaceAll(p, []byte{telnetIAC}, []byte{telnetIAC, telnetIAC})
+	if _, err := t.Conn.Write(escaped); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// negotiate replies to an option request from the switch.
+func (t *telnetConn) negotiate(cmd, opt byte) error {
+	var reply byte
+	switch cmd {
+	case telnetDO:
+		reply = telnetWONT
+	case telnetWILL:
+		reply = telnetDONT
+		if opt == telnetOptEcho || opt == telnetOptSGA {
+			reply = telnetDO
+		}
+	default:
+		// DONT and WONT need no answer since every option starts disabled.
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, err := t.Conn.Write([]byte{telnetIAC, reply, opt})
+	return err
+}
+
+// skipSubnegotiation discards data up to and including IAC SE.
+func (t *telnetConn) skipSubnegotiation() error {
+	for {
+		b, err := t.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if b != telnetIAC {
+			continue
+		}
+		b, err = t.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if b == telnetSE {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package client_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pascal71/tplink-go/client"
+)
+
+// Telnet bytes used by telnetServer.
+const (
+	iac      = 255
+	will     = 251
+	wont     = 252
+	do       = 253
+	optEcho  = 1
+	optTType = 24
+)
+
+// telnetServer is a telnet-only switch asking for credentials at login.
+// It offers to echo, asks for the terminal type and records the option
+// replies it receives.
+type telnetServer struct {
+	addr string
+
+	mu      sync.Mutex
+	replies [][]byte // option replies received, e.g. IAC DO ECHO
+	lines   []string // lines received
+}
+
+func startTelnetServer(t *testing.T, user, password string) *telnetServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	s := &telnetServer{addr: ln.Addr().String()}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn, user, password)
+		}
+	}()
+	return s
+}
+
+func (s *telnetServer) serve(conn net.Conn, user, password string) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	conn.Write([]byte{iac, will, optEcho, iac, do, optTType})
+	io.WriteString(conn, "\r\nUser:")
+	u, err := s.readLine(r)
+	if err != nil {
+		return
+	}
+	io.WriteString(conn, "\r\nPassword:")
+	p, err := s.readLine(r)
+	if err != nil {
+		return
+	}
+	if u != user || p != password {
+		io.WriteString(conn, "\r\nLogin incorrect\r\nUser:")
+		return
+	}
+	io.WriteString(conn, "\r\nSG2210XMP-M2>")
+	for {
+		cmd, err := s.readLine(r)
+		if err != nil {
+			return
+		}
+		out := cmd + "\r\n"
+		if cmd == "show version" {
+			out += "Firmware 1.0\r\n"
+		}
+		io.WriteString(conn, out+"\r\nSG2210XMP-M2>")
+	}
+}
+
+// readLine reads one CR LF terminated line, recording option replies on
+// the way.
+func (s *telnetServer) readLine(r *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case b == iac:
+			cmd := make([]byte, 2)
+			if _, err := io.ReadFull(r, cmd); err != nil {
+				return "", err
+			}
+			s.mu.Lock()
+			s.replies = append(s.replies, append([]byte{iac}, cmd...))
+			s.mu.Unlock()
+		case b == '\n':
+			l := strings.TrimSuffix(string(line), "\r")
+			s.mu.Lock()
+			s.lines = append(s.lines, l)
+			s.mu.Unlock()
+			return l, nil
+		default:
+			line = append(line, b)
+		}
+	}
+}
+
+func TestTelnetLogin(t *testing.T) {
+	s := startTelnetServer(t, "admin", "s3cret")
+	c := client.NewTelnetClient(s.addr, "admin", "s3cret", client.WithCommandTimeout(2*time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer c.Close()
+
+	f, err := c.RunCommandFrame(ctx, "show version")
+	if err != nil {
+		t.Fatalf("RunCommandFrame: %v", err)
+	}
+	if f.Payload != "Firmware 1.0" || f.Prompt != "SG2210XMP-M2>" {
+		t.Errorf("frame = %+v, want the output and the user mode prompt", f)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	want := [][]byte{{iac, do, optEcho}, {iac, wont, optTType}}
+	if len(s.replies) != len(want) {
+		t.Fatalf("option replies = %v, want %v", s.replies, want)
+	}
+	for i := range want {
+		if !bytes.Equal(s.replies[i], want[i]) {
+			t.Errorf("option reply %d = %v, want %v", i, s.replies[i], want[i])
+		}
+	}
+	if len(s.lines) < 2 || s.lines[0] != "admin" || s.lines[1] != "s3cret" {
+		t.Errorf("lines = %q, want the username and password first", s.lines)
+	}
+}
+
+func TestTelnetLoginFailed(t *testing.T) {
+	s := startTelnetServer(t, "admin", "s3cret")
+	c := client.NewTelnetClient(s.addr, "admin", "wrong", client.WithCommandTimeout(2*time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := c.Connect(ctx)
+	if err == nil {
+		c.Close()
+		t.Fatal("Connect succeeded with a wrong password")
+	}
+	if !strings.Contains(err.Error(), "login failed") {
+		t.Errorf("error = %v, want login failed", err)
+	}
+}
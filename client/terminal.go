@@ -0,0 +1,218 @@
+package client
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// screenHeight is the PTY height requested by the SSH transport; absolute
+// cursor positions are relative to the last screenHeight lines.
+const screenHeight = 40
+
+// render interprets the terminal control data in b the way a VT100 would and
+// returns the resulting text, one line per row. Backspace and carriage-return
+// overwrites, cursor movement and erase sequences are applied; colours, OSC
+// titles and other sequences are dropped. Trailing spaces are trimmed.
+func render(b []byte) string {
+	var s screen
+	s.write(b)
+	return s.String()
+}
+
+// screen is a growing grid of runes with a cursor.
+type screen struct {
+	lines    [][]rune
+	row, col int
+}
+
+// top returns the index of the first line visible on the screen.
+func (s *screen) top() int {
+	if n := len(s.lines) - screenHeight; n > 0 {
+		return n
+	}
+	return 0
+}
+
+// line returns the row under the cursor, creating rows as needed.
+func (s *screen) line() []rune {
+	for len(s.lines) <= s.row {
+		s.lines = append(s.lines, nil)
+	}
+	return s.lines[s.row]
+}
+
+func (s *screen) put(r rune) {
+	line := s.line()
+	for len(line) < s.col {
+		line = append(line, ' ')
+	}
+	if s.col < len(line) {
+		line[s.col] = r
+	} else {
+		line = append(line, r)
+	}
+	s.lines[s.row] = line
+	s.col++
+}
+
+// write feeds b through the control sequence parser.
+func (s *screen) write(b []byte) {
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		i += size
+		switch r {
+		case '\x1b':
+			i = s.escape(b, i)
+		case '\b':
+			if s.col > 0 {
+				s.col--
+			}
+		case '\r':
+			s.col = 0
+		case '\n':
+			s.row++
+			s.col = 0
+			s.line()
+		case '\t':
+			s.col = (s.col/8 + 1) * 8
+		default:
+			if r >= ' ' && r != 0x7f {
+				s.put(r)
+			}
+		}
+	}
+}
+
+// escape handles the sequence following an ESC at b[i-1] and returns the
+// index just past it.
+func (s *screen) escape(b []byte, i int) int {
+	if i >= len(b) {
+		return i
+	}
+	switch b[i] {
+	case '[':
+		return s.csi(b, i+1)
+	case ']', 'P', '_', '^':
+		// OSC, DCS, APC and PM strings end with BEL or ST (ESC \).
+		for j := i + 1; j < len(b); j++ {
+			if b[j] == '\x07' {
+				return j + 1
+			}
+			if b[j] == '\x1b' && j+1 < len(b) && b[j+1] == '\\' {
+				return j + 2
+			}
+		}
+		return len(b)
+	case '(', ')', '*', '+', '#':
+		// Character set designation takes one more byte.
+		return min(i+2, len(b))
+	default:
+		return i + 1
+	}
+}
+
+// csi applies the control sequence whose parameters start at b[i] and
+// returns the index just past its final byte.
+func (s *screen) csi(b []byte, i int) int {
+	start := i
+	for i < len(b) && (b[i] < 0x40 || b[i] > 0x7e) {
+		i++
+	}
+	if i >= len(b) {
+		return i
+	}
+	params := strings.TrimLeft(string(b[start:i]), "?>=!")
+	final := b[i]
+
+	args := strings.Split(params, ";")
+	arg := func(n, def int) int {
+		if n < len(args) {
+			if v, err := strconv.Atoi(args[n]); err == nil && v > 0 {
+				return v
+			}
+		}
+		return def
+	}
+
+	switch final {
+	case 'A':
+		s.row = max(s.row-arg(0, 1), s.top())
+	case 'B':
+		s.row += arg(0, 1)
+	case 'C':
+		s.col += arg(0, 1)
+	case 'D':
+		s.col = max(s.col-arg(0, 1), 0)
+	case 'E':
+		s.row += arg(0, 1)
+		s.col = 0
+	case 'F':
+		s.row = max(s.row-arg(0, 1), s.top())
+		s.col = 0
+	case 'G':
+		s.col = arg(0, 1) - 1
+	case 'H', 'f':
+		s.row = s.top() + arg(0, 1) - 1
+		s.col = arg(1, 1) - 1
+	case 'J':
+		s.eraseDisplay(argOrZero(args))
+	case 'K':
+		s.eraseLine(argOrZero(args))
+	}
+	return i + 1
+}
+
+// argOrZero returns the first parameter, or 0 when absent.
+func argOrZero(args []string) int {
+	v, _ := strconv.Atoi(args[0])
+	return v
+}
+
+// eraseLine implements EL: 0 erases to the end of line, 1 to the cursor, 2 all of it.
+func (s *screen) eraseLine(mode int) {
+	line := s.line()
+	switch mode {
+	case 0:
+		if s.col < len(line) {
+			s.lines[s.row] = line[:s.col]
+		}
+	case 1:
+		for j := 0; j <= s.col && j < len(line); j++ {
+			line[j] = ' '
+		}
+	case 2:
+		s.lines[s.row] = nil
+	}
+}
+
+// eraseDisplay implements ED on the visible screen; scrollback is kept.
+func (s *screen) eraseDisplay(mode int) {
+	s.line()
+	switch mode {
+	case 0:
+		s.eraseLine(0)
+		s.lines = s.lines[:s.row+1]
+	case 1:
+		for j := s.top(); j < s.row; j++ {
+			s.lines[j] = nil
+		}
+		s.eraseLine(1)
+	case 2, 3:
+		for j := s.top(); j < len(s.lines); j++ {
+			s.lines[j] = nil
+		}
+	}
+}
+
+// String returns the screen contents with trailing spaces trimmed.
+func (s *screen) String() string {
+	var sb strings.Builder
+	for i, line := range s.lines {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(strings.TrimRight(string(line), " "))
+	}
+	return sb.String()
+}
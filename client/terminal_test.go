@@ -0,0 +1,58 @@
+package client
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "Port  Status\r\nGi1/0/1  up", "Port  Status\nGi1/0/1  up"},
+		{"backspace overwrite", "shwo\b\b\bhow", "show"},
+		{"carriage return overwrite", "Press any key to continue\r                         \rGi1/0/9  up", "Gi1/0/9  up"},
+		{"erase to end of line", "Press any key\r\x1b[KGi1/0/9", "Gi1/0/9"},
+		{"erase whole line", "Press any key\x1b[2K\rGi1/0/9", "Gi1/0/9"},
+		{"erase to cursor", "abcdef\x1b[3D\x1b[1K", "    ef"},
+		{"colours dropped", "\x1b[1;32mup\x1b[0m", "up"},
+		{"OSC title dropped", "\x1b]0;SG2210XMP-M2\x07prompt", "prompt"},
+		{"OSC with ST", "\x1b]2;title\x1b\\prompt", "prompt"},
+		{"charset designation", "\x1b(Bline", "line"},
+		{"cursor up and forward", "one\r\ntwo\x1b[A\r\x1b[2CE", "onE\ntwo"},
+		{"cursor back clamps", "ab\x1b[9DX", "Xb"},
+		{"column", "abcdef\x1b[3GX", "abXdef"},
+		{"absolute position", "one\r\ntwo\x1b[1;1HX", "Xne\ntwo"},
+		{"erase display below", "one\r\ntwo\r\nthree\x1b[2;1H\x1b[J", "one\n"},
+		{"erase display", "one\r\ntwo\x1b[2J", "\n"},
+		{"tab", "a\tb", "a       b"},
+		{"trailing spaces trimmed", "up   \r\ndown", "up\ndown"},
+		{"unicode", "Gi1/0/1 — uplink", "Gi1/0/1 — uplink"},
+		{"delete and controls dropped", "a\x7f\x00b", "ab"},
+		{"truncated sequence", "up\x1b[", "up"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render([]byte(tt.in)); got != tt.want {
+				t.Errorf("render(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderScreenRelativePosition(t *testing.T) {
+	// Absolute positions address the last screenHeight lines, not the scrollback.
+	var in []byte
+	for range screenHeight + 5 {
+		in = append(in, "line\r\n"...)
+	}
+	in = append(in, "\x1b[1;1HX"...)
+	var s screen
+	s.write(in)
+	top := len(s.lines) - screenHeight
+	if got := string(s.lines[top]); got != "Xine" {
+		t.Errorf("line %d = %q, want the first visible line overwritten", top, got)
+	}
+	if got := string(s.lines[0]); got != "line" {
+		t.Errorf("line 0 = %q, want the scrollback kept", got)
+	}
+}
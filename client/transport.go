@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"io"
+)
+
+// Transport carries the switch CLI as a pair of byte streams. The built-in
+// SSH, telnet and serial transports are selected by the constructors; other
+// implementations, such as in-memory pipes feeding canned output in tests,
+// are plugged in with WithTransport.
+//
+// A Transport may also implement InteractiveLogin() bool to have the client
+// answer in-band username and password prompts, and KeepAlive() error to
+// support WithKeepAlive.
+type Transport interface {
+	// Open starts an interactive CLI session and returns the stream written
+	// to the switch and the stream of its output.
+	Open(ctx context.Context) (stdin io.Writer, stdout io.Reader, err error)
+	// Close ends the session, unblocking any pending read on stdout.
+	Close() error
+}
+
+// customTransport adapts a user supplied Transport.
+type customTransport struct {
+	t Transport
+}
+
+func (ct customTransport) open(ctx context.Context, c *Client) (io.Writer, io.Reader, error) {
+	return ct.t.Open(ctx)
+}
+
+func (ct customTransport) close() {
+	ct.t.Close()
+}
+
+func (ct customTransport) interactiveLogin() bool {
+	l, ok := ct.t.(interface{ InteractiveLogin() bool })
+	return ok && l.InteractiveLogin()
+}
+
+func (ct customTransport) keepAliveFunc() func() error {
+	ka, ok := ct.t.(interface{ KeepAlive() error })
+	if !ok {
+		return func() error { return nil }
+	}
+	return ka.KeepAlive
+}
@@ -1,15 +1,20 @@
-// Command tplink-cli demonstrates usage of the TP-Link client and parser packages.
+// Command tplink-cli demonstrates usage of the TP-Link client and device packages.
 package main
 
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"os"
 	"time"
 
+	"golang.org/x/crypto/ssh"
+
 	"github.com/pascal71/tplink-go/client"
-	"github.com/pascal71/tplink-go/parser"
+	"github.com/pascal71/tplink-go/device"
 )
 
 func main() {
@@ -23,27 +28,44 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	c := client.NewClient(addr, user, pass)
+	var opts []client.Option
+	if os.Getenv("TPLINK_DEBUG") != "" {
+		h := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+		opts = append(opts, client.WithLogger(slog.New(h)))
+	}
+	if enable := os.Getenv("TPLINK_ENABLE_PASS"); enable != "" {
+		opts = append(opts, client.WithEnablePassword(enable))
+	}
+	if fp := os.Getenv("TPLINK_HOST_KEY_FINGERPRINT"); fp != "" {
+		opts = append(opts, client.WithHostKeyFingerprint(fp))
+	}
+	if proxyURL := os.Getenv("TPLINK_PROXY"); proxyURL != "" {
+		opts = append(opts, client.WithProxy(proxyURL))
+	}
+	if os.Getenv("TPLINK_LEGACY_ALGORITHMS") != "" {
+		opts = append(opts, client.WithAlgorithms(client.LegacyAlgorithms))
+	}
+	if jump := os.Getenv("TPLINK_JUMP_ADDR"); jump != "" {
+		j := client.JumpHost{
+			Addr:     jump,
+			User:     envOr("TPLINK_JUMP_USER", user),
+			Password: envOr("TPLINK_JUMP_PASS", pass),
+		}
+		if fp := os.Getenv("TPLINK_JUMP_HOST_KEY_FINGERPRINT"); fp != "" {
+			j.HostKeyCallback = pinnedHostKey(fp)
+		}
+		opts = append(opts, client.WithJumpHost(j))
+	}
+
+	c := client.NewClient(addr, user, pass, opts...)
 	if err := c.Connect(ctx); err != nil {
 		log.Fatalf("Connect error: %v", err)
 	}
 	defer c.Close()
 
-	// Setup for command sequence
-	commands := []string{"enable", "config", "no clipaging", "exit", "show power inline information interface"}
-	var output string
-	var err error
-
-	for _, cmd := range commands {
-		output, err = c.RunCommand(ctx, cmd)
-		if err != nil {
-			log.Fatalf("Command failed: %s: %v", cmd, err)
-		}
-	}
-
-	ports, err := parser.ParsePoETable(output)
+	ports, err := device.New(c).PoEPorts(ctx)
 	if err != nil {
-		log.Fatalf("Parse error: %v", err)
+		log.Fatalf("PoE ports: %v", err)
 	}
 
 	enc := json.NewEncoder(os.Stdout)
@@ -52,3 +74,21 @@ func main() {
 		log.Fatalf("Encoding JSON: %v", err)
 	}
 }
+
+// envOr returns the value of the environment variable key, or def if unset.
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// pinnedHostKey accepts only the host key with the SHA256 fingerprint fp.
+func pinnedHostKey(fp string) ssh.HostKeyCallback {
+	return func(hostname string, _ net.Addr, key ssh.PublicKey) error {
+		if got := ssh.FingerprintSHA256(key); got != fp {
+			return fmt.Errorf("host key for %s does not match pinned fingerprint (got %s)", hostname, got)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,106 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/pascal71/tplink-go/parser"
+	"github.com/pascal71/tplink-go/profile"
+)
+
+// modeKinds are the configuration blocks that enter a sub-mode even when
+// they have no settings, such as a VLAN left with its default name.
+var modeKinds = map[string]bool{
+	"interface":  true,
+	"vlan":       true,
+	"time-range": true,
+	"line":       true,
+}
+
+// RunningConfig returns the running configuration, parsed.
+func (d *Device) RunningConfig(ctx context.Context) (parser.Config, error) {
+	return show(ctx, d, profile.CmdRunningConfig, parser.ParseRunningConfig)
+}
+
+// BackupConfig returns the running configuration as the switch prints it,
+// with "\n" line endings, for RestoreConfig.
+func (d *Device) BackupConfig(ctx context.Context) (string, error) {
+	return d.backup(ctx, profile.CmdRunningConfig)
+}
+
+// BackupStartupConfig is like BackupConfig but returns the startup
+// configuration, the one the switch boots with.
+func (d *Device) BackupStartupConfig(ctx context.Context) (string, error) {
+	return d.backup(ctx, profile.CmdStartupConfig)
+}
+
+// backup returns the output of the configuration command with the logical
+// name, checking that it holds any settings.
+func (d *Device) backup(ctx context.Context, name string) (string, error) {
+	cmd, out, err := d.run(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	out = strings.TrimSpace(strings.ReplaceAll(out, "\r\n", "\n"))
+	if len(restoreCommands(out)) == 0 {
+		return "", errors.New(cmd + ": empty configuration")
+	}
+	return out + "\n", nil
+}
+
+// RestoreConfig applies cfg, as returned by BackupConfig, to the running
+// configuration in one configuration session, stopping at the first line
+// the switch rejects. Settings are merged: those missing from cfg are
+// kept, not removed. Call SaveConfig to keep the result across reboots.
+func (d *Device) RestoreConfig(ctx context.Context, cfg string) error {
+	cmds := restoreCommands(cfg)
+	if len(cmds) == 0 {
+		return errors.New("restore: empty configuration")
+	}
+	return d.Configure(ctx, cmds...)
+}
+
+// restoreCommands returns the configuration commands recreating cfg:
+// its settings without comments, separators and the final "end", with an
+// "exit" after each block.
+func restoreCommands(cfg string) []string {
+	var (
+		cmds    []string
+		inBlock bool // the last top-level command entered a sub-mode
+	)
+	lines := strings.Split(strings.ReplaceAll(cfg, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "end" || strings.HasPrefix(trimmed, "!") || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			cmds = append(cmds, trimmed)
+			continue
+		}
+		if inBlock {
+			cmds = append(cmds, "exit")
+		}
+		cmds = append(cmds, trimmed)
+		kind, _, _ := strings.Cut(trimmed, " ")
+		inBlock = modeKinds[kind] || nextIndented(lines[i+1:])
+	}
+	if inBlock {
+		cmds = append(cmds, "exit")
+	}
+	return cmds
+}
+
+// nextIndented reports whether the first setting in lines is indented,
+// that is belongs to a block.
+func nextIndented(lines []string) bool {
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "!") || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		return line[0] == ' ' || line[0] == '\t'
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package device_test
+
+import (
+	"context"
+	"testing"
+)
+
+// runningConfig is "show running-config" output with comments, an empty
+// interface block, a top-level setting after the blocks and the final "end".
+const runningConfig = `!SG2210XMP-M2
+#
+hostname "core1"
+#
+vlan 10
+ name "users"
+#
+interface gigabitEthernet 1/0/1
+  switchport general allowed vlan 10 untagged
+  description "uplink"
+#
+interface gigabitEthernet 1/0/2
+#
+ip http server
+end`
+
+func TestBackupConfigReplay(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("show running-config", runningConfig, privPrompt),
+	)
+
+	got, err := d.BackupConfig(context.Background())
+	if err != nil {
+		t.Fatalf("BackupConfig: %v", err)
+	}
+	if want := runningConfig + "\n"; got != want {
+		t.Errorf("BackupConfig = %q, want %q", got, want)
+	}
+	wantSent(t, tr, "show running-config")
+}
+
+func TestBackupStartupConfigReplay(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("show startup-config", runningConfig, privPrompt),
+	)
+
+	got, err := d.BackupStartupConfig(context.Background())
+	if err != nil {
+		t.Fatalf("BackupStartupConfig: %v", err)
+	}
+	if want := runningConfig + "\n"; got != want {
+		t.Errorf("BackupStartupConfig = %q, want %q", got, want)
+	}
+	wantSent(t, tr, "show startup-config")
+}
+
+func TestBackupConfigReplayEmpty(t *testing.T) {
+	d, _ := replayDevice(t,
+		reply("show running-config", "!SG2210XMP-M2\n#\nend", privPrompt),
+	)
+
+	_, err := d.BackupConfig(context.Background())
+	if err == nil || err.Error() != "show running-config: empty configuration" {
+		t.Fatalf("BackupConfig error = %v, want an empty configuration error", err)
+	}
+}
+
+func TestRestoreConfigReplay(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("configure", "", configPrompt),
+		reply(`hostname "core1"`, "", configPrompt),
+		reply("vlan 10", "", vlanPrompt),
+		reply(`name "users"`, "", vlanPrompt),
+		reply("exit", "", configPrompt),
+		reply("interface gigabitEthernet 1/0/1", "", ifPrompt),
+		reply("switchport general allowed vlan 10 untagged", "", ifPrompt),
+		reply(`description "uplink"`, "", ifPrompt),
+		reply("interface gigabitEthernet 1/0/2", "", ifPrompt),
+		reply("ip http server", "", configPrompt),
+		reply("end", "", privPrompt),
+	)
+
+	if err := d.RestoreConfig(context.Background(), runningConfig); err != nil {
+		t.Fatalf("RestoreConfig: %v", err)
+	}
+	// Comments and "end" are dropped, and each block, even an empty one,
+	// is left with "exit" before the next top-level command.
+	wantSent(t, tr,
+		"configure",
+		`hostname "core1"`,
+		"vlan 10",
+		`name "users"`,
+		"exit",
+		"interface gigabitEthernet 1/0/1",
+		"switchport general allowed vlan 10 untagged",
+		`description "uplink"`,
+		"exit",
+		"interface gigabitEthernet 1/0/2",
+		"exit",
+		"ip http server",
+		"end",
+	)
+}
+
+func TestRestoreConfigReplayTrailingBlock(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("configure", "", configPrompt),
+		reply("line vty 0 4", "", "SG2210XMP-M2(config-line)#"),
+		reply("exec-timeout 10", "", "SG2210XMP-M2(config-line)#"),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+	)
+
+	if err := d.RestoreConfig(context.Background(), "line vty 0 4\r\n exec-timeout 10\r\n"); err != nil {
+		t.Fatalf("RestoreConfig: %v", err)
+	}
+	wantSent(t, tr, "configure", "line vty 0 4", "exec-timeout 10", "exit", "end")
+}
+
+func TestRestoreConfigEmptySendsNothing(t *testing.T) {
+	d, tr := replayDevice(t)
+	if err := d.RestoreConfig(context.Background(), "!SG2210XMP-M2\n#\nend\n"); err == nil {
+		t.Error("RestoreConfig of an empty configuration: want an error")
+	}
+	if got := tr.commands(); got != nil {
+		t.Errorf("sent %q, want nothing", got)
+	}
+}
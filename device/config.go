@@ -0,0 +1,191 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/parser"
+)
+
+// VerifyError is returned when a setting written to the switch does not
+// read back as written.
+type VerifyError struct {
+	Port    parser.PortID // port configured, "" for a global setting
+	Setting string        // e.g. "poe priority"
+	Want    string
+	Got     string
+}
+
+func (e *VerifyError) Error() string {
+	if e.Port == "" {
+		return fmt.Sprintf("verify %s: want %q, got %q", e.Setting, e.Want, e.Got)
+	}
+	return fmt.Sprintf("verify %s of %s: want %q, got %q", e.Setting, e.Port, e.Want, e.Got)
+}
+
+// interfaceTypes maps PortID types to the interface keywords of the CLI.
+var interfaceTypes = map[string]string{
+	"Fa":  "fastEthernet",
+	"Gi":  "gigabitEthernet",
+	"Tw":  "two-gigabitEthernet",
+	"Te":  "ten-gigabitEthernet",
+	"Po":  "port-channel",
+	"LAG": "port-channel",
+}
+
+// interfaceName returns port as the CLI names it in "interface" commands,
+// e.g. "gigabitEthernet 1/0/3" for "Gi1/0/3".
+func interfaceName(port parser.PortID) (string, error) {
+	port = parser.NormalizePortID(string(port))
+	kw, ok := interfaceTypes[port.Type()]
+	if !ok {
+		return "", fmt.Errorf("port %q: unsupported interface type", port)
+	}
+	return kw + " " + strings.TrimPrefix(string(port), port.Type()), nil
+}
+
+// interfaceRanges returns the "interface range" commands selecting ports,
+// one per interface type, with consecutive ports joined as in
+// "interface range gigabitEthernet 1/0/1-4,1/0/6". A single port is
+// selected with a plain "interface" command.
+func interfaceRanges(ports []parser.PortID) ([]string, error) {
+	if len(ports) == 1 {
+		name, err := interfaceName(ports[0])
+		if err != nil {
+			return nil, err
+		}
+		return []string{"interface " + name}, nil
+	}
+	ids := normalizePorts(ports)
+	var (
+		cmds  []string
+		typ   string
+		items []string
+	)
+	flush := func() {
+		if len(items) > 0 {
+			cmds = append(cmds, "interface range "+interfaceTypes[typ]+" "+strings.Join(items, ","))
+		}
+		items = nil
+	}
+	for i := 0; i < len(ids); i++ {
+		p := ids[i]
+		if _, ok := interfaceTypes[p.Type()]; !ok || p.Numbers() == nil {
+			return nil, fmt.Errorf("port %q: unsupported interface type", p)
+		}
+		if p.Type() != typ {
+			flush()
+			typ = p.Type()
+		}
+		// Extend the run while the next port differs only by being one higher.
+		j := i
+		for j+1 < len(ids) && ids[j+1].Type() == typ && adjacent(ids[j], ids[j+1]) {
+			j++
+		}
+		item := strings.TrimPrefix(string(p), typ)
+		if j > i {
+			n := ids[j].Numbers()
+			item += "-" + strconv.Itoa(n[len(n)-1])
+		}
+		items = append(items, item)
+		i = j
+	}
+	flush()
+	return cmds, nil
+}
+
+// normalizePorts returns ports normalised, sorted and without duplicates.
+func normalizePorts(ports []parser.PortID) []parser.PortID {
+	ids := make([]parser.PortID, len(ports))
+	for i, p := range ports {
+		ids[i] = parser.NormalizePortID(string(p))
+	}
+	parser.SortPortIDs(ids)
+	return slices.Compact(ids)
+}
+
+// adjacent reports whether q is the port after p on the same unit and slot.
+func adjacent(p, q parser.PortID) bool {
+	a, b := p.Numbers(), q.Numbers()
+	if len(a) != len(b) || len(a) == 0 {
+		return false
+	}
+	last := len(a) - 1
+	return slices.Equal(a[:last], b[:last]) && b[last] == a[last]+1
+}
+
+// Configure runs cmds in global configuration mode, then returns to
+// privileged mode. It stops at the first command that fails. Commands are
+// sent once whatever the client's retry policy, as resending one the switch
+// may have applied is not safe.
+func (d *Device) Configure(ctx context.Context, cmds ...string) error {
+	return d.configure(ctx, nil, cmds...)
+}
+
+// configure is Configure for commands containing secrets, such as
+// passwords, which are kept out of logs and errors.
+func (d *Device) configure(ctx context.Context, secrets []string, cmds ...string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.setup(ctx); err != nil {
+		return err
+	}
+	if _, err := d.runWith(ctx, "configure", client.NoRetry()); err != nil {
+		return fmt.Errorf("configure: %w", err)
+	}
+	for _, cmd := range cmds {
+		if _, err := d.runWith(ctx, cmd, client.Secrets(secrets...), client.NoRetry()); err != nil {
+			d.runWith(ctx, "end", client.NoRetry()) // back to privileged mode; cmd's error is the one to report
+			return fmt.Errorf("%s: %w", redact(cmd, secrets), err)
+		}
+	}
+	if _, err := d.runWith(ctx, "end", client.NoRetry()); err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	return nil
+}
+
+// redact replaces secrets in s as the client does.
+func redact(s string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, "[REDACTED]")
+		}
+	}
+	return s
+}
+
+// configureInterface runs cmds in the interface configuration mode of port.
+func (d *Device) configureInterface(ctx context.Context, port parser.PortID, cmds ...string) error {
+	return d.configureInterfaces(ctx, []parser.PortID{port}, cmds...)
+}
+
+// configureInterfaces runs cmds in the interface configuration mode of
+// ports, selected as a range.
+func (d *Device) configureInterfaces(ctx context.Context, ports []parser.PortID, cmds ...string) error {
+	all, err := interfaceCommands(ports, cmds...)
+	if err != nil {
+		return err
+	}
+	return d.Configure(ctx, all...)
+}
+
+// interfaceCommands returns the configuration commands running cmds on
+// ports: each interface range, cmds, then "exit".
+func interfaceCommands(ports []parser.PortID, cmds ...string) ([]string, error) {
+	ranges, err := interfaceRanges(ports)
+	if err != nil {
+		return nil, err
+	}
+	var all []string
+	for _, r := range ranges {
+		all = append(all, r)
+		all = append(all, cmds...)
+		all = append(all, "exit")
+	}
+	return all, nil
+}
@@ -0,0 +1,36 @@
+package device_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+func TestConfigureDoesNotRetry(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.Handler = func(mode testutil.Mode, cmd string) (string, bool) {
+		if mode == testutil.ModeConfig && cmd == "hostname core1" {
+			time.Sleep(400 * time.Millisecond)
+			return "", true
+		}
+		return "", false
+	}
+	d, _ := startDevice(t, sw,
+		client.WithCommandTimeout(100*time.Millisecond),
+		client.WithRetry(client.RetryPolicy{Attempts: 3, Backoff: 10 * time.Millisecond}))
+
+	err := d.Configure(context.Background(), "hostname core1")
+	if !errors.Is(err, client.ErrPromptTimeout) {
+		t.Fatalf("Configure error = %v, want ErrPromptTimeout", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	cmds := sw.Commands()
+	if n := len(slices.DeleteFunc(cmds, func(c string) bool { return c != "hostname core1" })); n != 1 {
+		t.Errorf("hostname core1 sent %d times, want 1", n)
+	}
+}
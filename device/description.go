@@ -0,0 +1,83 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pascal71/tplink-go/parser"
+	"github.com/pascal71/tplink-go/profile"
+)
+
+// InterfaceDescriptions returns the description of each port.
+func (d *Device) InterfaceDescriptions(ctx context.Context) (map[parser.PortID]parser.InterfaceDescription, error) {
+	return show(ctx, d, profile.CmdInterfaceDescr, parser.ParseInterfaceDescriptions)
+}
+
+// maxDescriptionLen is the longest port description the CLI accepts.
+const maxDescriptionLen = 16
+
+// SetPortDescription sets the description of port, or removes it if desc
+// is empty, and verifies it.
+func (d *Device) SetPortDescription(ctx context.Context, port parser.PortID, desc string) error {
+	return d.SetPortDescriptions(ctx, map[parser.PortID]string{port: desc})
+}
+
+// SetPortDescriptions sets the description of each port in descs in one
+// configuration session, removing those that are empty, and verifies them
+// all. Descriptions may hold spaces but not double quotes or control
+// characters, and at most 16 characters; nothing is sent if one is invalid.
+func (d *Device) SetPortDescriptions(ctx context.Context, descs map[parser.PortID]string) error {
+	ports := parser.PortIDsOf(descs)
+	var cmds []string
+	for _, port := range ports {
+		desc := descs[port]
+		if err := checkDescription(desc); err != nil {
+			return fmt.Errorf("port %s: %w", port, err)
+		}
+		iface, err := interfaceName(port)
+		if err != nil {
+			return err
+		}
+		cmd := "no description"
+		if desc != "" {
+			cmd = `description "` + desc + `"`
+		}
+		cmds = append(cmds, "interface "+iface, cmd, "exit")
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+	if err := d.Configure(ctx, cmds...); err != nil {
+		return err
+	}
+
+	got, err := d.InterfaceDescriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	for _, port := range ports {
+		id := parser.NormalizePortID(string(port))
+		if g := got[id].Description; g != descs[port] {
+			return &VerifyError{Port: id, Setting: "description", Want: descs[port], Got: g}
+		}
+	}
+	return nil
+}
+
+// checkDescription returns an error for a description the CLI cannot take
+// quoted.
+func checkDescription(desc string) error {
+	if n := utf8.RuneCountInString(desc); n > maxDescriptionLen {
+		return fmt.Errorf("description %q is %d characters long, the limit is %d", desc, n, maxDescriptionLen)
+	}
+	if strings.ContainsRune(desc, '"') {
+		return fmt.Errorf("description %q contains a double quote", desc)
+	}
+	if strings.ContainsFunc(desc, unicode.IsControl) {
+		return fmt.Errorf("description %q contains a control character", desc)
+	}
+	return nil
+}
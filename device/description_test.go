@@ -0,0 +1,88 @@
+package device_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pascal71/tplink-go/parser"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+// descriptionSwitch returns a switch whose "show interface description"
+// reflects the description commands it has received for gigabit ports.
+func descriptionSwitch() *testutil.Switch {
+	sw := testutil.NewSwitch()
+	sw.Handler = func(_ testutil.Mode, cmd string) (string, bool) {
+		if cmd != "show interface description" {
+			return "", false
+		}
+		descs := map[string]string{"Gi1/0/1": "", "Gi1/0/2": ""}
+		var port string
+		for _, c := range sw.Commands() {
+			if name, ok := strings.CutPrefix(c, "interface gigabitEthernet "); ok {
+				port = "Gi" + name
+			} else if desc, ok := strings.CutPrefix(c, "description "); ok {
+				descs[port] = strings.Trim(desc, `"`)
+			} else if c == "no description" {
+				descs[port] = ""
+			}
+		}
+		out := "Interface     Status   Description\n-----------   ------   -----------\n"
+		for _, p := range []string{"Gi1/0/1", "Gi1/0/2"} {
+			out += fmt.Sprintf("%-14s%-9s%s\n", p, "Up", descs[p])
+		}
+		return out, true
+	}
+	return sw
+}
+
+func TestSetPortDescriptionQuotes(t *testing.T) {
+	sw := descriptionSwitch()
+	d, _ := startDevice(t, sw)
+
+	if err := d.SetPortDescription(context.Background(), "Gi1/0/1", "Uplink core"); err != nil {
+		t.Fatalf("SetPortDescription: %v", err)
+	}
+	if cmds := sw.Commands(); !slices.Contains(cmds, `description "Uplink core"`) {
+		t.Errorf("commands %q lack the quoted description", cmds)
+	}
+}
+
+func TestSetPortDescriptionRejectsInvalid(t *testing.T) {
+	for name, desc := range map[string]string{
+		"too long":      "seventeen chars!!",
+		"double quote":  `say "hi"`,
+		"line break":    "a\nb",
+		"tab":           "a\tb",
+		"escape":        "a\x1b[2Jb",
+		"delete":        "a\x7fb",
+		"C1 control":    "a\u0085b",
+		"long, unicode": strings.Repeat("é", 17),
+	} {
+		t.Run(name, func(t *testing.T) {
+			sw := descriptionSwitch()
+			d, _ := startDevice(t, sw)
+
+			err := d.SetPortDescriptions(context.Background(), map[parser.PortID]string{"Gi1/0/1": "ok", "Gi1/0/2": desc})
+			if err == nil {
+				t.Fatalf("description %q accepted", desc)
+			}
+			if cmds := sw.Commands(); slices.Contains(cmds, "configure") {
+				t.Errorf("commands %q sent for an invalid description", cmds)
+			}
+		})
+	}
+}
+
+func TestSetPortDescriptionLimit(t *testing.T) {
+	sw := descriptionSwitch()
+	d, _ := startDevice(t, sw)
+
+	desc := strings.Repeat("x", 16)
+	if err := d.SetPortDescription(context.Background(), "Gi1/0/2", desc); err != nil {
+		t.Fatalf("SetPortDescription(%q): %v", desc, err)
+	}
+}
@@ -0,0 +1,188 @@
+// Package device provides typed access to a TP-Link switch: each method
+// runs the right show command for the model and returns the parsed result.
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/parser"
+	"github.com/pascal71/tplink-go/profile"
+)
+
+// ErrUnsupported is returned for data the switch model has no command for,
+// such as PoE on a non-PoE model.
+var ErrUnsupported = errors.New("not supported by this model")
+
+// Device is a switch reached through a connected client.Interface. The
+// first call detects the model, unless WithProfile is given, switches the
+// client to the family's prompt pattern and enters privileged mode with
+// paging disabled; later calls reuse that session.
+// A Device is safe for concurrent use if its client is.
+type Device struct {
+	c         client.Interface
+	mu        sync.Mutex // guards the fields below
+	profile   profile.Profile
+	detected  bool // profile is set
+	ready     bool // Setup has run
+	parseOpts []parser.Option
+}
+
+// Option configures a Device.
+type Option func(*Device)
+
+// WithProfile uses p instead of detecting the model from "show
+// system-info".
+func WithProfile(p profile.Profile) Option {
+	return func(d *Device) {
+		d.profile = p
+		d.detected = true
+	}
+}
+
+// WithParseOptions passes opts, such as parser.Lenient, to every parser.
+func WithParseOptions(opts ...parser.Option) Option {
+	return func(d *Device) {
+		d.parseOpts = append(d.parseOpts, opts...)
+	}
+}
+
+// New returns a Device using c, which must be connected.
+func New(c client.Interface, opts ...Option) *Device {
+	d := &Device{c: c}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Client returns the client the device uses.
+func (d *Device) Client() client.Interface {
+	return d.c
+}
+
+// Profile returns the profile of the switch, detecting it if needed.
+func (d *Device) Profile(ctx context.Context) (profile.Profile, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.detect(ctx); err != nil {
+		return profile.Profile{}, err
+	}
+	return d.profile, nil
+}
+
+// detect selects the profile from "show system-info" if none is set yet.
+// The caller holds d.mu.
+func (d *Device) detect(ctx context.Context) error {
+	if d.detected {
+		return nil
+	}
+	p, _, err := profile.Detect(ctx, d.c)
+	if err != nil {
+		return fmt.Errorf("detect model: %w", err)
+	}
+	d.profile, d.detected = p, true
+	return nil
+}
+
+// setup detects the profile and prepares the session once. The caller
+// holds d.mu.
+func (d *Device) setup(ctx context.Context) error {
+	if err := d.detect(ctx); err != nil {
+		return err
+	}
+	if d.ready {
+		return nil
+	}
+	if a, ok := d.c.(applier); ok && d.profile.Prompt != nil {
+		a.Apply(client.WithPromptRegex(d.profile.Prompt))
+	}
+	if err := d.profile.Setup(ctx, d.c); err != nil {
+		return fmt.Errorf("setup: %w", err)
+	}
+	d.ready = true
+	return nil
+}
+
+// run prepares the session and runs the command with the logical name,
+// returning the command and its output.
+func (d *Device) run(ctx context.Context, name string) (string, string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.setup(ctx); err != nil {
+		return "", "", err
+	}
+	cmd, ok := d.profile.Command(name)
+	if !ok {
+		return "", "", fmt.Errorf("%s on %s: %w", name, d.profile.Family, ErrUnsupported)
+	}
+	out, err := d.c.RunCommand(ctx, cmd)
+	if err != nil {
+		return cmd, "", fmt.Errorf("%s: %w", cmd, err)
+	}
+	return cmd, out, nil
+}
+
+// framer is implemented by clients that take command options, such as
+// *client.Client.
+type framer interface {
+	RunCommandFrame(ctx context.Context, cmd string, opts ...client.CommandOption) (client.Frame, error)
+}
+
+// applier is implemented by clients that take options after creation, such
+// as *client.Client.
+type applier interface {
+	Apply(opts ...client.Option)
+}
+
+// runWith runs cmd with opts if the client takes command options, and
+// plainly otherwise. The caller holds d.mu and has run setup.
+func (d *Device) runWith(ctx context.Context, cmd string, opts ...client.CommandOption) (client.Frame, error) {
+	if f, ok := d.c.(framer); ok {
+		return f.RunCommandFrame(ctx, cmd, opts...)
+	}
+	out, err := d.c.RunCommand(ctx, cmd)
+	return client.Frame{Command: cmd, Payload: out}, err
+}
+
+// show runs the command with the logical name and parses its output.
+// A *parser.ParseError gets the command set.
+func show[T any](ctx context.Context, d *Device, name string, parse func(string, ...parser.Option) (T, error)) (T, error) {
+	var zero T
+	cmd, out, err := d.run(ctx, name)
+	if err != nil {
+		return zero, err
+	}
+	v, err := parse(out, d.parseOpts...)
+	if err != nil {
+		var pe *parser.ParseError
+		if errors.As(err, &pe) && pe.Command == "" {
+			pe.Command = cmd
+		}
+		return zero, err
+	}
+	return v, nil
+}
+
+// PoEPorts returns the PoE state of each port.
+func (d *Device) PoEPorts(ctx context.Context) (map[parser.PortID]parser.PoEPort, error) {
+	return show(ctx, d, profile.CmdPoEInterfaces, parser.ParsePoETable)
+}
+
+// MACTable returns the MAC address table.
+func (d *Device) MACTable(ctx context.Context) ([]parser.MACEntry, error) {
+	return show(ctx, d, profile.CmdMACTable, parser.ParseMACTable)
+}
+
+// InterfaceStatus returns the link state of each port.
+func (d *Device) InterfaceStatus(ctx context.Context) (map[parser.PortID]parser.InterfaceStatus, error) {
+	return show(ctx, d, profile.CmdInterfaceStatus, parser.ParseInterfaceStatus)
+}
+
+// CPU returns the CPU load of each stack unit.
+func (d *Device) CPU(ctx context.Context) ([]parser.CPUUtilization, error) {
+	return show(ctx, d, profile.CmdCPU, parser.ParseCPUUtilization)
+}
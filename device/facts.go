@@ -0,0 +1,110 @@
+package device
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/parser"
+	"github.com/pascal71/tplink-go/profile"
+)
+
+// Facts is the inventory of one switch.
+type Facts struct {
+	Hostname     string             `json:"hostname"`
+	Model        string             `json:"model"`            // e.g. "SG3428XMP"
+	Hardware     string             `json:"hardware_version"` // as printed, e.g. "SG3428XMP 2.0"
+	Serial       string             `json:"serial,omitempty"`
+	Firmware     string             `json:"firmware"`
+	MAC          string             `json:"mac"`
+	Uptime       time.Duration      `json:"-"`                       // serialised as whole seconds, "uptime_seconds"
+	ManagementIP string             `json:"management_ip,omitempty"` // first layer 3 interface that is up
+	Family       string             `json:"family"`                  // profile the device is driven with
+	Stack        []parser.StackUnit `json:"stack,omitempty"`         // units, if stacked
+	PortCount    int                `json:"port_count"`              // physical ports
+}
+
+// facts is Facts without its JSON methods.
+type facts Facts
+
+// factsJSON is the JSON form of Facts.
+type factsJSON struct {
+	*facts
+	UptimeSeconds int64 `json:"uptime_seconds"`
+}
+
+// MarshalJSON encodes f with the uptime in whole seconds.
+func (f Facts) MarshalJSON() ([]byte, error) {
+	return json.Marshal(factsJSON{(*facts)(&f), int64(f.Uptime / time.Second)})
+}
+
+// UnmarshalJSON decodes f as encoded by MarshalJSON.
+func (f *Facts) UnmarshalJSON(data []byte) error {
+	v := factsJSON{facts: (*facts)(f)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	f.Uptime = time.Duration(v.UptimeSeconds) * time.Second
+	return nil
+}
+
+// Facts gathers the switch inventory from "show system-info", the IP
+// interfaces, the stack and the interface status. Models without stacking,
+// which reject the stack command, report no stack units.
+func (d *Device) Facts(ctx context.Context) (Facts, error) {
+	info, err := show(ctx, d, profile.CmdSystemInfo, parser.ParseSystemInfo)
+	if err != nil {
+		return Facts{}, err
+	}
+	p, err := d.Profile(ctx)
+	if err != nil {
+		return Facts{}, err
+	}
+	f := Facts{
+		Hostname: info.Name,
+		Model:    info.Model(),
+		Hardware: info.HardwareVersion,
+		Serial:   info.SerialNumber,
+		Firmware: info.SoftwareVersion,
+		MAC:      info.MACAddress,
+		Family:   p.Family,
+	}
+	if f.Uptime, err = info.Uptime(); err != nil {
+		return Facts{}, fmt.Errorf("%s: %w", profile.CmdSystemInfo, err)
+	}
+
+	ifaces, err := show(ctx, d, profile.CmdIPInterfaces, parser.ParseIPInterfaces)
+	if err != nil {
+		return Facts{}, err
+	}
+	for _, i := range ifaces {
+		if i.IP != "" && i.AdminUp && i.LinkUp {
+			f.ManagementIP = i.IP
+			break
+		}
+	}
+
+	stack, err := show(ctx, d, profile.CmdStack, parser.ParseStackInfo)
+	switch {
+	case err == nil:
+		if len(stack.Units) > 1 {
+			f.Stack = stack.Units
+		}
+	case !errors.Is(err, client.ErrCommandRejected) && !errors.Is(err, ErrUnsupported):
+		return Facts{}, err
+	}
+
+	ports, err := d.InterfaceStatus(ctx)
+	if err != nil {
+		return Facts{}, err
+	}
+	for port := range ports {
+		if port.Physical() {
+			f.PortCount++
+		}
+	}
+	return f, nil
+}
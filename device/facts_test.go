@@ -0,0 +1,50 @@
+package device_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pascal71/tplink-go/device"
+	"github.com/pascal71/tplink-go/parser"
+)
+
+func TestFactsJSON(t *testing.T) {
+	f := device.Facts{
+		Hostname:  "core1",
+		Model:     "SG3428XMP",
+		Firmware:  "1.0.0",
+		Uptime:    3*24*time.Hour + 4*time.Minute + 5*time.Second + 600*time.Millisecond,
+		Family:    "SG3428XMP",
+		Stack:     []parser.StackUnit{{Unit: 1, Role: "Master"}},
+		PortCount: 28,
+	}
+	b, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fields["uptime_seconds"], float64(259445); got != want {
+		t.Errorf("uptime_seconds = %v, want %v in %s", got, want, b)
+	}
+	if _, ok := fields["uptime"]; ok {
+		t.Errorf("JSON %s still has uptime", b)
+	}
+	if !strings.Contains(string(b), `"hostname":"core1"`) || !strings.Contains(string(b), `"port_count":28`) {
+		t.Errorf("JSON %s lacks the other fields", b)
+	}
+
+	var back device.Facts
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	f.Uptime = f.Uptime.Truncate(time.Second)
+	if !reflect.DeepEqual(back, f) {
+		t.Errorf("round trip = %+v, want %+v", back, f)
+	}
+}
@@ -0,0 +1,34 @@
+package device_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/device"
+	"github.com/pascal71/tplink-go/profile"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+// startDevice starts sw and returns a Device driving it with the default
+// profile through a connected client, which is closed when the test ends.
+func startDevice(t *testing.T, sw *testutil.Switch, opts ...client.Option) (*device.Device, *client.Client) {
+	t.Helper()
+	if err := sw.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sw.Close() })
+	opts = append([]client.Option{
+		client.WithHostKeyFingerprint(ssh.FingerprintSHA256(sw.HostKey())),
+		client.WithCommandTimeout(2 * time.Second),
+	}, opts...)
+	c := client.NewClient(sw.Addr(), sw.User, sw.Password, opts...)
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(c.Close)
+	return device.New(c, device.WithProfile(profile.Default)), c
+}
@@ -0,0 +1,156 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pascal71/tplink-go/parser"
+	"github.com/pascal71/tplink-go/profile"
+)
+
+// LAGMode is how the member ports of a LAG negotiate the bundle.
+type LAGMode string
+
+// LAG modes of the TP-Link CLI "channel-group" command.
+const (
+	LAGStatic   LAGMode = "on"      // static aggregation, no LACP
+	LACPActive  LAGMode = "active"  // LACP, sending LACPDUs
+	LACPPassive LAGMode = "passive" // LACP, answering LACPDUs only
+)
+
+// LAGs returns the port-channels of the switch and their member ports.
+func (d *Device) LAGs(ctx context.Context) ([]parser.LAG, error) {
+	return show(ctx, d, profile.CmdLAG, parser.ParseLAGs)
+}
+
+// lag reads back the port-channel with the ID; ok is false if it has no
+// members, which is how the switch shows a LAG that does not exist.
+func (d *Device) lag(ctx context.Context, id int) (parser.LAG, bool, error) {
+	lags, err := d.LAGs(ctx)
+	if err != nil {
+		return parser.LAG{}, false, err
+	}
+	for _, l := range lags {
+		if l.ID == id {
+			return l, len(l.Members) > 0, nil
+		}
+	}
+	return parser.LAG{}, false, nil
+}
+
+// AddLAGMembers adds ports to the port-channel with the ID in mode,
+// creating the port-channel if needed, and verifies the membership and
+// protocol. Ports in another port-channel are moved.
+func (d *Device) AddLAGMembers(ctx context.Context, id int, mode LAGMode, ports ...parser.PortID) error {
+	if id < 1 {
+		return fmt.Errorf("invalid port-channel %d", id)
+	}
+	if err := checkLAGMode(mode); err != nil {
+		return err
+	}
+	ports = normalizePorts(ports)
+	if len(ports) == 0 {
+		return nil
+	}
+	err := d.configureInterfaces(ctx, ports, "no channel-group", fmt.Sprintf("channel-group %d mode %s", id, mode))
+	if err != nil {
+		return err
+	}
+	return d.verifyLAG(ctx, id, mode, ports)
+}
+
+// RemoveLAGMembers removes ports from the port-channel with the ID and
+// verifies they are no longer members. The port-channel is gone once its
+// last member is removed.
+func (d *Device) RemoveLAGMembers(ctx context.Context, id int, ports ...parser.PortID) error {
+	ports = normalizePorts(ports)
+	if len(ports) == 0 {
+		return nil
+	}
+	if err := d.configureInterfaces(ctx, ports, "no channel-group"); err != nil {
+		return err
+	}
+	l, _, err := d.lag(ctx, id)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	for _, m := range l.Members {
+		if slices.Contains(ports, m.Port) {
+			return &VerifyError{Port: m.Port, Setting: "port-channel", Want: "none", Got: l.Name}
+		}
+	}
+	return nil
+}
+
+// DeleteLAG removes all member ports of the port-channel with the ID and
+// verifies it is gone. Deleting a port-channel that does not exist is not
+// an error.
+func (d *Device) DeleteLAG(ctx context.Context, id int) error {
+	l, ok, err := d.lag(ctx, id)
+	if err != nil || !ok {
+		return err
+	}
+	return d.RemoveLAGMembers(ctx, id, lagPorts(l)...)
+}
+
+// SetLACPMode changes the mode of all member ports of the existing
+// port-channel with the ID and verifies the protocol.
+func (d *Device) SetLACPMode(ctx context.Context, id int, mode LAGMode) error {
+	l, ok, err := d.lag(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("port-channel %d does not exist", id)
+	}
+	return d.AddLAGMembers(ctx, id, mode, lagPorts(l)...)
+}
+
+// verifyLAG checks that ports are members of the port-channel with the ID
+// and that it runs LACP or not as mode asks. Active and passive LACP look
+// the same in the summary, so they are not told apart.
+func (d *Device) verifyLAG(ctx context.Context, id int, mode LAGMode, ports []parser.PortID) error {
+	l, ok, err := d.lag(ctx, id)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	name := "Po" + strconv.Itoa(id)
+	if !ok {
+		return &VerifyError{Setting: "port-channel " + strconv.Itoa(id), Want: "present", Got: "absent"}
+	}
+	have := lagPorts(l)
+	for _, p := range ports {
+		if !slices.Contains(have, p) {
+			return &VerifyError{Port: p, Setting: "port-channel", Want: name, Got: "none"}
+		}
+	}
+	if lacp := strings.EqualFold(l.Protocol, "LACP"); lacp != (mode != LAGStatic) {
+		want := "LACP"
+		if mode == LAGStatic {
+			want = "static"
+		}
+		return &VerifyError{Port: parser.PortID(name), Setting: "lag protocol", Want: want, Got: l.Protocol}
+	}
+	return nil
+}
+
+// checkLAGMode returns an error unless mode is one of the LAG modes.
+func checkLAGMode(mode LAGMode) error {
+	switch mode {
+	case LAGStatic, LACPActive, LACPPassive:
+		return nil
+	}
+	return fmt.Errorf("invalid LAG mode %q", mode)
+}
+
+// lagPorts returns the member ports of l.
+func lagPorts(l parser.LAG) []parser.PortID {
+	ports := make([]parser.PortID, len(l.Members))
+	for i, m := range l.Members {
+		ports[i] = m.Port
+	}
+	return ports
+}
@@ -0,0 +1,164 @@
+package device_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pascal71/tplink-go/device"
+)
+
+// lagSummary returns "show etherchannel summary" output listing rows, such
+// as "2      Po2(SU)       LACP        Gi1/0/1(P)".
+func lagSummary(rows ...string) string {
+	out := `Flags:  D - down        P - bundled in port-channel
+        I - stand-alone s - suspended
+        U - in use      N - not in use, no aggregation
+
+Group  Port-channel  Protocol    Ports
+------ ------------  --------    ---------------------------
+1      Po1(SU)       LACP        Gi1/0/23(P) Gi1/0/24(P)`
+	for _, row := range rows {
+		out += "\n" + row
+	}
+	return out
+}
+
+func TestAddLAGMembersReplay(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("configure", "", configPrompt),
+		reply("interface range gigabitEthernet 1/0/1-3", "", rangePrompt),
+		reply("no channel-group", "", rangePrompt),
+		reply("channel-group 2 mode active", "", rangePrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+		reply("show etherchannel summary", lagSummary("2      Po2(SU)       LACP        Gi1/0/1(P) Gi1/0/2(P) Gi1/0/3(P)"), privPrompt),
+	)
+
+	if err := d.AddLAGMembers(context.Background(), 2, device.LACPActive, "Gi1/0/3", "gi1/0/1", "Gi1/0/2"); err != nil {
+		t.Fatalf("AddLAGMembers: %v", err)
+	}
+	wantSent(t, tr, "configure",
+		"interface range gigabitEthernet 1/0/1-3", "no channel-group", "channel-group 2 mode active", "exit",
+		"end", "show etherchannel summary")
+}
+
+func TestAddLAGMembersReplayVerifyError(t *testing.T) {
+	// A static LAG was asked for, but the switch still runs LACP on it.
+	d, _ := replayDevice(t,
+		reply("configure", "", configPrompt),
+		reply("interface gigabitEthernet 1/0/5", "", ifPrompt),
+		reply("no channel-group", "", ifPrompt),
+		reply("channel-group 3 mode on", "", ifPrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+		reply("show etherchannel summary", lagSummary("3      Po3(SU)       LACP        Gi1/0/5(P)"), privPrompt),
+	)
+
+	err := d.AddLAGMembers(context.Background(), 3, device.LAGStatic, "Gi1/0/5")
+	var ve *device.VerifyError
+	if !errors.As(err, &ve) {
+		t.Fatalf("AddLAGMembers error = %v, want *VerifyError", err)
+	}
+	if ve.Port != "Po3" || ve.Setting != "lag protocol" || ve.Want != "static" || ve.Got != "LACP" {
+		t.Errorf("VerifyError = %+v, want Po3 lag protocol static, got LACP", ve)
+	}
+}
+
+func TestRemoveLAGMembersReplay(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("configure", "", configPrompt),
+		reply("interface range gigabitEthernet 1/0/1,1/0/3", "", rangePrompt),
+		reply("no channel-group", "", rangePrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+		reply("show etherchannel summary", lagSummary("2      Po2(SU)       LACP        Gi1/0/2(P)"), privPrompt),
+	)
+
+	if err := d.RemoveLAGMembers(context.Background(), 2, "Gi1/0/3", "Gi1/0/1"); err != nil {
+		t.Fatalf("RemoveLAGMembers: %v", err)
+	}
+	wantSent(t, tr, "configure",
+		"interface range gigabitEthernet 1/0/1,1/0/3", "no channel-group", "exit",
+		"end", "show etherchannel summary")
+}
+
+func TestDeleteLAGReplay(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("show etherchannel summary", lagSummary("2      Po2(SD)       -           Gi1/0/21(D) Gi1/0/22(D)"), privPrompt),
+		reply("configure", "", configPrompt),
+		reply("interface range gigabitEthernet 1/0/21-22", "", rangePrompt),
+		reply("no channel-group", "", rangePrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+		reply("show etherchannel summary", lagSummary(), privPrompt),
+	)
+
+	if err := d.DeleteLAG(context.Background(), 2); err != nil {
+		t.Fatalf("DeleteLAG: %v", err)
+	}
+	wantSent(t, tr, "show etherchannel summary", "configure",
+		"interface range gigabitEthernet 1/0/21-22", "no channel-group", "exit",
+		"end", "show etherchannel summary")
+}
+
+func TestDeleteLAGReplayAbsent(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("show etherchannel summary", lagSummary(), privPrompt),
+	)
+
+	if err := d.DeleteLAG(context.Background(), 2); err != nil {
+		t.Fatalf("DeleteLAG of a missing port-channel: %v", err)
+	}
+	wantSent(t, tr, "show etherchannel summary")
+}
+
+func TestSetLACPModeReplay(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("show etherchannel summary", lagSummary(), privPrompt),
+		reply("configure", "", configPrompt),
+		reply("interface range gigabitEthernet 1/0/23-24", "", rangePrompt),
+		reply("no channel-group", "", rangePrompt),
+		reply("channel-group 1 mode passive", "", rangePrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+	)
+
+	if err := d.SetLACPMode(context.Background(), 1, device.LACPPassive); err != nil {
+		t.Fatalf("SetLACPMode: %v", err)
+	}
+	wantSent(t, tr, "show etherchannel summary", "configure",
+		"interface range gigabitEthernet 1/0/23-24", "no channel-group", "channel-group 1 mode passive", "exit",
+		"end", "show etherchannel summary")
+}
+
+func TestSetLACPModeReplayAbsent(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("show etherchannel summary", lagSummary(), privPrompt),
+	)
+
+	if err := d.SetLACPMode(context.Background(), 2, device.LACPActive); err == nil {
+		t.Fatal("SetLACPMode of a missing port-channel: want an error")
+	}
+	wantSent(t, tr, "show etherchannel summary")
+}
+
+func TestAddLAGMembersInvalidSendsNothing(t *testing.T) {
+	d, tr := replayDevice(t)
+	ctx := context.Background()
+	if err := d.AddLAGMembers(ctx, 0, device.LACPActive, "Gi1/0/1"); err == nil {
+		t.Error("AddLAGMembers to port-channel 0: want an error")
+	}
+	if err := d.AddLAGMembers(ctx, 1, device.LAGMode("lacp"), "Gi1/0/1"); err == nil {
+		t.Error("AddLAGMembers in mode lacp: want an error")
+	}
+	if err := d.AddLAGMembers(ctx, 1, device.LACPActive); err != nil {
+		t.Errorf("AddLAGMembers with no ports: %v", err)
+	}
+	if err := d.RemoveLAGMembers(ctx, 1); err != nil {
+		t.Errorf("RemoveLAGMembers with no ports: %v", err)
+	}
+	if got := tr.commands(); got != nil {
+		t.Errorf("sent %q, want nothing", got)
+	}
+}
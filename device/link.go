@@ -0,0 +1,142 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pascal71/tplink-go/parser"
+	"github.com/pascal71/tplink-go/profile"
+)
+
+// Speed is a port speed setting.
+type Speed string
+
+// Port speeds of the TP-Link CLI.
+const (
+	SpeedAuto Speed = "auto"
+	Speed10M  Speed = "10"
+	Speed100M Speed = "100"
+	Speed1G   Speed = "1000"
+	Speed2G5  Speed = "2500"
+	Speed10G  Speed = "10000"
+)
+
+// Duplex is a port duplex setting.
+type Duplex string
+
+// Duplex modes of the TP-Link CLI.
+const (
+	DuplexAuto Duplex = "auto"
+	DuplexFull Duplex = "full"
+	DuplexHalf Duplex = "half"
+)
+
+// LinkSettings are the link settings to apply to ports. Zero fields are
+// left as they are.
+type LinkSettings struct {
+	Speed       Speed
+	Duplex      Duplex
+	FlowControl *bool
+}
+
+// commands returns the interface commands applying s.
+func (s LinkSettings) commands() ([]string, error) {
+	var cmds []string
+	switch s.Speed {
+	case "":
+	case SpeedAuto, Speed10M, Speed100M, Speed1G, Speed2G5, Speed10G:
+		cmds = append(cmds, "speed "+string(s.Speed))
+	default:
+		return nil, fmt.Errorf("invalid speed %q", s.Speed)
+	}
+	switch s.Duplex {
+	case "":
+	case DuplexAuto, DuplexFull, DuplexHalf:
+		cmds = append(cmds, "duplex "+string(s.Duplex))
+	default:
+		return nil, fmt.Errorf("invalid duplex %q", s.Duplex)
+	}
+	if s.FlowControl != nil {
+		if *s.FlowControl {
+			cmds = append(cmds, "flow-control")
+		} else {
+			cmds = append(cmds, "no flow-control")
+		}
+	}
+	return cmds, nil
+}
+
+// InterfaceConfig returns the configured settings of each port.
+func (d *Device) InterfaceConfig(ctx context.Context) (map[parser.PortID]parser.InterfaceConfig, error) {
+	return show(ctx, d, profile.CmdInterfaceConfig, parser.ParseInterfaceConfig)
+}
+
+// SetLink applies s to ports, configured together as an interface range,
+// and verifies the configured settings of each port.
+func (d *Device) SetLink(ctx context.Context, s LinkSettings, ports ...parser.PortID) error {
+	cmds, err := s.commands()
+	if err != nil {
+		return err
+	}
+	if len(cmds) == 0 || len(ports) == 0 {
+		return nil
+	}
+	if err := d.configureInterfaces(ctx, ports, cmds...); err != nil {
+		return err
+	}
+
+	cfg, err := d.InterfaceConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	for _, port := range ports {
+		port = parser.NormalizePortID(string(port))
+		c, ok := cfg[port]
+		if !ok {
+			return fmt.Errorf("verify: port %s not in interface configuration", port)
+		}
+		if s.Speed != "" && speedOf(c.Speed) != s.Speed {
+			return &VerifyError{Port: port, Setting: "speed", Want: string(s.Speed), Got: c.Speed}
+		}
+		if s.Duplex != "" && !strings.EqualFold(c.Duplex, string(s.Duplex)) {
+			return &VerifyError{Port: port, Setting: "duplex", Want: string(s.Duplex), Got: c.Duplex}
+		}
+		if s.FlowControl != nil && c.FlowControl != *s.FlowControl {
+			return &VerifyError{Port: port, Setting: "flow control", Want: strconv.FormatBool(*s.FlowControl), Got: strconv.FormatBool(c.FlowControl)}
+		}
+	}
+	return nil
+}
+
+// speedOf returns the setting a printed speed such as "100M", "10G" or
+// "Auto" stands for.
+func speedOf(printed string) Speed {
+	s := strings.ToLower(strings.TrimSpace(printed))
+	switch {
+	case s == "auto":
+		return SpeedAuto
+	case strings.HasSuffix(s, "g"):
+		if g, err := strconv.ParseFloat(strings.TrimSuffix(s, "g"), 64); err == nil {
+			return Speed(strconv.Itoa(int(g * 1000)))
+		}
+	}
+	return Speed(strings.TrimSuffix(strings.TrimSuffix(s, "mbps"), "m"))
+}
+
+// SetSpeed sets the speed of ports and verifies it.
+func (d *Device) SetSpeed(ctx context.Context, speed Speed, ports ...parser.PortID) error {
+	return d.SetLink(ctx, LinkSettings{Speed: speed}, ports...)
+}
+
+// SetDuplex sets the duplex mode of ports and verifies it.
+func (d *Device) SetDuplex(ctx context.Context, duplex Duplex, ports ...parser.PortID) error {
+	return d.SetLink(ctx, LinkSettings{Duplex: duplex}, ports...)
+}
+
+// SetFlowControl enables or disables flow control on ports and verifies
+// it.
+func (d *Device) SetFlowControl(ctx context.Context, on bool, ports ...parser.PortID) error {
+	return d.SetLink(ctx, LinkSettings{FlowControl: &on}, ports...)
+}
@@ -0,0 +1,107 @@
+package device_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pascal71/tplink-go/device"
+	"github.com/pascal71/tplink-go/parser"
+)
+
+// interfaceConfigTable returns "show interface configuration" output for
+// Gi1/0/1-5 and Te1/0/25, with the speed, duplex and flow control of
+// Gi1/0/4 left at Auto, Auto and Disable.
+func interfaceConfigTable(speed, duplex, flow string) string {
+	out := `Port       State    Speed   Duplex  FlowCtrl  Active-Medium
+--------   -------  ------  ------  --------  -------------`
+	for _, port := range []string{"Gi1/0/1", "Gi1/0/2", "Gi1/0/3", "Gi1/0/4", "Gi1/0/5", "Te1/0/25"} {
+		s, d, f := speed, duplex, flow
+		if port == "Gi1/0/4" {
+			s, d, f = "Auto", "Auto", "Disable"
+		}
+		out += fmt.Sprintf("\n%-9s  Enable   %-6s  %-6s  %-8s  Copper", port, s, d, f)
+	}
+	return out
+}
+
+func TestSetLinkReplay(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("configure", "", configPrompt),
+		reply("interface range gigabitEthernet 1/0/1-3,1/0/5", "", rangePrompt),
+		reply("interface range ten-gigabitEthernet 1/0/25", "", rangePrompt),
+		reply("speed 1000", "", rangePrompt),
+		reply("duplex full", "", rangePrompt),
+		reply("no flow-control", "", rangePrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+		reply("show interface configuration", interfaceConfigTable("1000M", "Full", "Disable"), privPrompt),
+	)
+
+	off := false
+	s := device.LinkSettings{Speed: device.Speed1G, Duplex: device.DuplexFull, FlowControl: &off}
+	ports := []parser.PortID{"Te1/0/25", "gi1/0/3", "Gi1/0/1", "Gi1/0/2", "Gi1/0/5", "Gi1/0/1"}
+	if err := d.SetLink(context.Background(), s, ports...); err != nil {
+		t.Fatalf("SetLink: %v", err)
+	}
+	wantSent(t, tr, "configure",
+		"interface range gigabitEthernet 1/0/1-3,1/0/5", "speed 1000", "duplex full", "no flow-control", "exit",
+		"interface range ten-gigabitEthernet 1/0/25", "speed 1000", "duplex full", "no flow-control", "exit",
+		"end", "show interface configuration")
+}
+
+func TestSetLinkReplaySinglePort(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("configure", "", configPrompt),
+		reply("interface gigabitEthernet 1/0/2", "", ifPrompt),
+		reply("flow-control", "", ifPrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+		reply("show interface configuration", interfaceConfigTable("Auto", "Auto", "Enable"), privPrompt),
+	)
+
+	if err := d.SetFlowControl(context.Background(), true, "Gi1/0/2"); err != nil {
+		t.Fatalf("SetFlowControl: %v", err)
+	}
+	wantSent(t, tr, "configure", "interface gigabitEthernet 1/0/2", "flow-control", "exit", "end",
+		"show interface configuration")
+}
+
+func TestSetLinkReplayVerifyError(t *testing.T) {
+	// Gi1/0/4 keeps auto-negotiation.
+	d, _ := replayDevice(t,
+		reply("configure", "", configPrompt),
+		reply("interface range gigabitEthernet 1/0/3-4", "", rangePrompt),
+		reply("speed 100", "", rangePrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+		reply("show interface configuration", interfaceConfigTable("100M", "Full", "Disable"), privPrompt),
+	)
+
+	err := d.SetSpeed(context.Background(), device.Speed100M, "Gi1/0/3", "Gi1/0/4")
+	var ve *device.VerifyError
+	if !errors.As(err, &ve) {
+		t.Fatalf("SetSpeed error = %v, want *VerifyError", err)
+	}
+	if ve.Port != "Gi1/0/4" || ve.Setting != "speed" || ve.Want != "100" || ve.Got != "Auto" {
+		t.Errorf("VerifyError = %+v, want speed of Gi1/0/4 100, got Auto", ve)
+	}
+}
+
+func TestSetLinkInvalidSendsNothing(t *testing.T) {
+	d, tr := replayDevice(t)
+	ctx := context.Background()
+	if err := d.SetSpeed(ctx, "40000", "Gi1/0/1"); err == nil {
+		t.Error("SetSpeed 40000: want an error")
+	}
+	if err := d.SetDuplex(ctx, "simplex", "Gi1/0/1"); err == nil {
+		t.Error("SetDuplex simplex: want an error")
+	}
+	if err := d.SetLink(ctx, device.LinkSettings{}, "Gi1/0/1"); err != nil {
+		t.Errorf("SetLink with no settings: %v", err)
+	}
+	if got := tr.commands(); got != nil {
+		t.Errorf("sent %q, want nothing", got)
+	}
+}
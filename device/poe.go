@@ -0,0 +1,135 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pascal71/tplink-go/parser"
+	"github.com/pascal71/tplink-go/profile"
+)
+
+// PoEPriority is the priority of a PoE port when the power budget runs
+// short: lower-priority ports are shut down first.
+type PoEPriority string
+
+// PoE priorities of the TP-Link CLI. High is the highest level, what other
+// vendors call critical.
+const (
+	PoEPriorityLow    PoEPriority = "low"
+	PoEPriorityMiddle PoEPriority = "middle"
+	PoEPriorityHigh   PoEPriority = "high"
+)
+
+// PoEConfig returns the PoE configuration of each port.
+func (d *Device) PoEConfig(ctx context.Context) (map[parser.PortID]parser.PoEPortConfig, error) {
+	return show(ctx, d, profile.CmdPoEConfig, parser.ParsePoEConfig)
+}
+
+// poeConfigOf reads back the PoE configuration of port, which is
+// normalised.
+func (d *Device) poeConfigOf(ctx context.Context, port parser.PortID) (parser.PoEPortConfig, error) {
+	cfg, err := d.PoEConfig(ctx)
+	if err != nil {
+		return parser.PoEPortConfig{}, fmt.Errorf("verify: %w", err)
+	}
+	c, ok := cfg[port]
+	if !ok {
+		return parser.PoEPortConfig{}, fmt.Errorf("verify: port %s not in PoE configuration", port)
+	}
+	return c, nil
+}
+
+// SetPoEPriority sets the PoE priority of port and verifies it.
+func (d *Device) SetPoEPriority(ctx context.Context, port parser.PortID, prio PoEPriority) error {
+	port = parser.NormalizePortID(string(port))
+	switch prio {
+	case PoEPriorityLow, PoEPriorityMiddle, PoEPriorityHigh:
+	default:
+		return fmt.Errorf("invalid PoE priority %q", prio)
+	}
+	if err := d.configureInterface(ctx, port, "power inline priority "+string(prio)); err != nil {
+		return err
+	}
+	c, err := d.poeConfigOf(ctx, port)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(c.Priority, string(prio)) {
+		return &VerifyError{Port: port, Setting: "poe priority", Want: string(prio), Got: c.Priority}
+	}
+	return nil
+}
+
+// SetPoEPowerLimit limits the power port may deliver to watts, in steps of
+// 0.1 W, and verifies it.
+func (d *Device) SetPoEPowerLimit(ctx context.Context, port parser.PortID, watts float64) error {
+	port = parser.NormalizePortID(string(port))
+	tenths := int(math.Round(watts * 10))
+	if tenths <= 0 {
+		return fmt.Errorf("invalid PoE power limit %g W", watts)
+	}
+	if err := d.configureInterface(ctx, port, fmt.Sprintf("power inline consumption %d", tenths)); err != nil {
+		return err
+	}
+	c, err := d.poeConfigOf(ctx, port)
+	if err != nil {
+		return err
+	}
+	if math.Abs(c.PowerLimitWatts-float64(tenths)/10) >= 0.05 {
+		return &VerifyError{Port: port, Setting: "poe power limit", Want: strconv.FormatFloat(float64(tenths)/10, 'f', 1, 64), Got: c.PowerLimit}
+	}
+	return nil
+}
+
+// SetPoEPowerClass limits the power of port to that of an 802.3 PD class,
+// 1 to 8, or lets the PD's classification decide with class 0, and
+// verifies it.
+func (d *Device) SetPoEPowerClass(ctx context.Context, port parser.PortID, class int) error {
+	port = parser.NormalizePortID(string(port))
+	if class < 0 || class > 8 {
+		return fmt.Errorf("invalid PoE class %d", class)
+	}
+	want := "auto"
+	if class > 0 {
+		want = fmt.Sprintf("class%d", class)
+	}
+	if err := d.configureInterface(ctx, port, "power inline consumption "+want); err != nil {
+		return err
+	}
+	c, err := d.poeConfigOf(ctx, port)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(strings.ReplaceAll(c.PowerLimit, " ", ""), want) {
+		return &VerifyError{Port: port, Setting: "poe power limit", Want: want, Got: c.PowerLimit}
+	}
+	return nil
+}
+
+// SetPoEProfile binds port to the PoE profile name, which must exist, or
+// unbinds it if name is empty, and verifies it.
+func (d *Device) SetPoEProfile(ctx context.Context, port parser.PortID, name string) error {
+	port = parser.NormalizePortID(string(port))
+	cmd := "no power inline profile"
+	if name != "" {
+		cmd = "power inline profile " + name
+	}
+	if err := d.configureInterface(ctx, port, cmd); err != nil {
+		return err
+	}
+	c, err := d.poeConfigOf(ctx, port)
+	if err != nil {
+		return err
+	}
+	got := c.Profile
+	if strings.EqualFold(got, "none") || strings.EqualFold(got, "no profile") || strings.EqualFold(got, "n/a") || got == "-" {
+		got = ""
+	}
+	if got != name {
+		return &VerifyError{Port: port, Setting: "poe profile", Want: name, Got: c.Profile}
+	}
+	return nil
+}
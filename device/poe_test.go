@@ -0,0 +1,121 @@
+package device_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pascal71/tplink-go/device"
+	"github.com/pascal71/tplink-go/profile"
+)
+
+// poeConfigTable returns "show power inline configuration interface"
+// output for Gi1/0/1 and a Gi1/0/2 with the given priority, power limit,
+// time-range and profile.
+func poeConfigTable(prio, limit, timeRange, prof string) string {
+	return `Interface   PoE-Status  PoE-Prio  Power-Limit(W)  Time-Range  PoE-Profile
+---------   ----------  --------  --------------  ----------  -----------
+Gi1/0/1     Enable      Low       Class4          No Limit    No Profile
+` + fmt.Sprintf("Gi1/0/2     Enable      %-8s  %-14s  %-10s  %s", prio, limit, timeRange, prof)
+}
+
+// replayPoE returns a device of a PoE family configuring Gi1/0/2 with cmd,
+// then reading back config.
+func replayPoE(t *testing.T, cmd, config string) (*device.Device, *sentTransport) {
+	t.Helper()
+	p, ok := profile.Lookup("SG2210XMP")
+	if !ok {
+		t.Fatal("no SG2210XMP profile")
+	}
+	return replayProfileDevice(t, p,
+		reply("configure", "", configPrompt),
+		reply("interface gigabitEthernet 1/0/2", "", ifPrompt),
+		reply(cmd, "", ifPrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+		reply("show power inline configuration interface", config, privPrompt),
+	)
+}
+
+func TestPoEReplay(t *testing.T) {
+	tests := []struct {
+		name   string
+		cmd    string // sent in interface mode
+		config string // read back
+		set    func(*device.Device) error
+	}{
+		{
+			"priority high", "power inline priority high", poeConfigTable("High", "Class4", "No Limit", "No Profile"),
+			func(d *device.Device) error {
+				return d.SetPoEPriority(context.Background(), "gi1/0/2", device.PoEPriorityHigh)
+			},
+		},
+		{
+			"priority middle", "power inline priority middle", poeConfigTable("Middle", "Class4", "No Limit", "No Profile"),
+			func(d *device.Device) error {
+				return d.SetPoEPriority(context.Background(), "Gi1/0/2", device.PoEPriorityMiddle)
+			},
+		},
+		{
+			"power limit in tenths of a watt", "power inline consumption 154", poeConfigTable("Low", "15.4", "No Limit", "No Profile"),
+			func(d *device.Device) error { return d.SetPoEPowerLimit(context.Background(), "Gi1/0/2", 15.42) },
+		},
+		{
+			"power class", "power inline consumption class3", poeConfigTable("Low", "Class3", "No Limit", "No Profile"),
+			func(d *device.Device) error { return d.SetPoEPowerClass(context.Background(), "Gi1/0/2", 3) },
+		},
+		{
+			"power class auto", "power inline consumption auto", poeConfigTable("Low", "Auto", "No Limit", "No Profile"),
+			func(d *device.Device) error { return d.SetPoEPowerClass(context.Background(), "Gi1/0/2", 0) },
+		},
+		{
+			"profile", "power inline profile cameras", poeConfigTable("Low", "Class4", "No Limit", "cameras"),
+			func(d *device.Device) error { return d.SetPoEProfile(context.Background(), "Gi1/0/2", "cameras") },
+		},
+		{
+			"no profile", "no power inline profile", poeConfigTable("Low", "Class4", "No Limit", "No Profile"),
+			func(d *device.Device) error { return d.SetPoEProfile(context.Background(), "Gi1/0/2", "") },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, tr := replayPoE(t, tt.cmd, tt.config)
+			if err := tt.set(d); err != nil {
+				t.Fatalf("set: %v", err)
+			}
+			wantSent(t, tr, "configure", "interface gigabitEthernet 1/0/2", tt.cmd, "exit", "end",
+				"show power inline configuration interface")
+		})
+	}
+}
+
+func TestPoEReplayVerifyError(t *testing.T) {
+	// The switch keeps the old priority.
+	d, _ := replayPoE(t, "power inline priority high", poeConfigTable("Low", "Class4", "No Limit", "No Profile"))
+	err := d.SetPoEPriority(context.Background(), "Gi1/0/2", device.PoEPriorityHigh)
+	var ve *device.VerifyError
+	if !errors.As(err, &ve) {
+		t.Fatalf("SetPoEPriority error = %v, want *VerifyError", err)
+	}
+	if ve.Port != "Gi1/0/2" || ve.Setting != "poe priority" || ve.Want != "high" || ve.Got != "Low" {
+		t.Errorf("VerifyError = %+v, want poe priority of Gi1/0/2 high, got Low", ve)
+	}
+}
+
+func TestPoEInvalidSendsNothing(t *testing.T) {
+	d, tr := replayDevice(t)
+	ctx := context.Background()
+	if err := d.SetPoEPriority(ctx, "Gi1/0/2", "critical"); err == nil {
+		t.Error("SetPoEPriority critical: want an error")
+	}
+	if err := d.SetPoEPowerLimit(ctx, "Gi1/0/2", 0.04); err == nil {
+		t.Error("SetPoEPowerLimit 0.04 W: want an error")
+	}
+	if err := d.SetPoEPowerClass(ctx, "Gi1/0/2", 9); err == nil {
+		t.Error("SetPoEPowerClass 9: want an error")
+	}
+	if got := tr.commands(); got != nil {
+		t.Errorf("sent %q, want nothing", got)
+	}
+}
@@ -0,0 +1,58 @@
+package device_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/device"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+func TestDetectedPromptFollowsRename(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.SetResponse("show system-info", " System Name            - SG2210XMP-M2\n Hardware Version       - SG2210XMP-M2 1.0\n")
+	sw.Handler = func(mode testutil.Mode, cmd string) (string, bool) {
+		if mode == testutil.ModeConfig && cmd == "hostname core1" {
+			sw.Hostname = "core1" // the shell goroutine reads it for the next prompt
+			return "", true
+		}
+		return "", false
+	}
+	if err := sw.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sw.Close() })
+	c := client.NewClient(sw.Addr(), sw.User, sw.Password,
+		client.WithHostKeyFingerprint(ssh.FingerprintSHA256(sw.HostKey())),
+		client.WithCommandTimeout(time.Second))
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(c.Close)
+	d := device.New(c)
+
+	ctx := context.Background()
+	p, err := d.Profile(ctx)
+	if err != nil {
+		t.Fatalf("Profile: %v", err)
+	}
+	if p.Family != "SG2210XMP" {
+		t.Fatalf("family = %q, want SG2210XMP", p.Family)
+	}
+	// The client's default pattern only knows the factory hostname; the
+	// family's prompt keeps working once the switch is renamed.
+	if err := d.Configure(ctx, "hostname core1"); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	f, err := c.RunCommandFrame(ctx, "show system-info")
+	if err != nil {
+		t.Fatalf("RunCommandFrame after rename: %v", err)
+	}
+	if f.Prompt != "core1#" {
+		t.Errorf("prompt = %q, want core1#", f.Prompt)
+	}
+}
@@ -0,0 +1,86 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/pascal71/tplink-go/client"
+)
+
+// Reconnect pacing while waiting for a rebooting switch.
+var (
+	rebootPollInterval   = 5 * time.Second  // between reconnect attempts, also before the first
+	rebootAttemptTimeout = 20 * time.Second // limit of one reconnect attempt
+)
+
+// Questions the reboot command asks: whether to save the configuration
+// first, which is declined, and whether to go ahead. The save question is
+// tried first as it may mention the reboot too.
+var (
+	rebootSaveQuestion = regexp.MustCompile(`(?i)[^\r\n]*sav[^\r\n]*[(\[]\s*(y/n|yes/no)\s*[)\]]\s*[?:]?\s*$`)
+	rebootQuestion     = regexp.MustCompile(`(?i)[^\r\n]*(reboot|restart)[^\r\n]*[(\[]\s*(y/n|yes/no)\s*[)\]]\s*[?:]?\s*$`)
+)
+
+// Reboot restarts the switch, confirming the switch's reboot question, and
+// closes the session. Unsaved configuration is lost: an offer to save it
+// is declined, see SaveConfig. Any other question fails the reboot, as
+// does an error other than the session closing once it is confirmed.
+//
+// With waitForReturn, Reboot then reconnects every few seconds until the
+// switch accepts the session again and its prompt returns, or ctx is done,
+// and reports the downtime from the reboot until then. The Device can be
+// used again afterwards. Without it the downtime is zero and the client
+// must be connected again before the Device is used.
+func (d *Device) Reboot(ctx context.Context, waitForReturn bool) (time.Duration, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.setup(ctx); err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	f, err := d.runWith(ctx, "reboot",
+		client.Answer(rebootSaveQuestion, "n"),
+		client.Answer(rebootQuestion, "y"),
+		client.NoRetry())
+	confirmed := slices.ContainsFunc(f.Questions, func(q string) bool {
+		return rebootQuestion.MatchString(q) && !rebootSaveQuestion.MatchString(q)
+	})
+	switch {
+	case ctx.Err() != nil:
+		return 0, ctx.Err()
+	case err != nil && (!confirmed || !errors.Is(err, client.ErrConnectionClosed) && !errors.Is(err, io.EOF)):
+		return 0, fmt.Errorf("reboot: %w", err)
+	case !confirmed:
+		return 0, errors.New("reboot: the switch did not ask for confirmation")
+	}
+	// The switch is restarting, dropping the session if it has not yet.
+	d.c.Close()
+	d.ready = false
+	if !waitForReturn {
+		return 0, nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return time.Since(start), fmt.Errorf("wait for reboot: %w", ctx.Err())
+		case <-time.After(rebootPollInterval):
+		}
+		actx, cancel := context.WithTimeout(ctx, rebootAttemptTimeout)
+		err := d.c.Connect(actx)
+		cancel()
+		if err != nil {
+			d.c.Close()
+			continue
+		}
+		if err := d.setup(ctx); err != nil {
+			return time.Since(start), err
+		}
+		return time.Since(start), nil
+	}
+}
@@ -0,0 +1,72 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/profile"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+// downDialer refuses connections until its until time.
+type downDialer struct{ until atomic.Int64 }
+
+func (dd *downDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if time.Now().UnixNano() < dd.until.Load() {
+		return nil, errors.New("connection refused")
+	}
+	var d net.Dialer
+	return d.DialContext(ctx, network, addr)
+}
+
+func TestRebootWaitForReturn(t *testing.T) {
+	defer func(d time.Duration) { rebootPollInterval = d }(rebootPollInterval)
+	rebootPollInterval = 50 * time.Millisecond
+
+	const down = 300 * time.Millisecond
+	sw := testutil.NewSwitch()
+	sw.SetQuestion("reboot", "Start to reboot system? (Y/N):")
+	sw.SetResponse(profile.Default.Commands[profile.CmdSystemInfo], "Firmware 1.0")
+	dd := &downDialer{}
+	sw.Handler = func(_ testutil.Mode, cmd string) (string, bool) {
+		if cmd != "reboot" {
+			return "", false
+		}
+		dd.until.Store(time.Now().Add(down).UnixNano())
+		sw.Drop()
+		return "", true
+	}
+	if err := sw.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer sw.Close()
+	c := client.NewClient(sw.Addr(), sw.User, sw.Password,
+		client.WithHostKeyFingerprint(ssh.FingerprintSHA256(sw.HostKey())),
+		client.WithCommandTimeout(2*time.Second),
+		client.WithDialer(dd))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	d := New(c, WithProfile(profile.Default))
+
+	downtime, err := d.Reboot(ctx, true)
+	if err != nil {
+		t.Fatalf("Reboot: %v", err)
+	}
+	if downtime < down {
+		t.Errorf("downtime = %v, want at least %v", downtime, down)
+	}
+	if _, _, err := d.run(ctx, profile.CmdSystemInfo); err != nil {
+		t.Errorf("Device unusable after the reboot: %v", err)
+	}
+}
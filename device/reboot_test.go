@@ -0,0 +1,95 @@
+package device_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+// rebootSwitch returns a switch that drops its sessions when reboot runs,
+// after asking question if it is not empty. rebooted counts the reboots.
+func rebootSwitch(question string, rebooted *atomic.Int32) *testutil.Switch {
+	sw := testutil.NewSwitch()
+	if question != "" {
+		sw.SetQuestion("reboot", question)
+	}
+	sw.Handler = func(_ testutil.Mode, cmd string) (string, bool) {
+		if cmd != "reboot" {
+			return "", false
+		}
+		rebooted.Add(1)
+		sw.Drop()
+		return "", true
+	}
+	return sw
+}
+
+func TestReboot(t *testing.T) {
+	var rebooted atomic.Int32
+	sw := rebootSwitch("Start to reboot? (Y/N):", &rebooted)
+	d, _ := startDevice(t, sw)
+
+	downtime, err := d.Reboot(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Reboot: %v", err)
+	}
+	if downtime != 0 {
+		t.Errorf("downtime = %v, want 0 without waiting", downtime)
+	}
+	if n := rebooted.Load(); n != 1 {
+		t.Errorf("rebooted %d times, want 1", n)
+	}
+}
+
+func TestRebootDeclinesSave(t *testing.T) {
+	var rebooted atomic.Int32
+	// The fake switch cancels the command on any answer but "y".
+	sw := rebootSwitch("Save current configuration before reboot? (Y/N):", &rebooted)
+	d, _ := startDevice(t, sw)
+
+	if _, err := d.Reboot(context.Background(), false); err == nil {
+		t.Fatal("Reboot succeeded without a reboot confirmation")
+	}
+	if n := rebooted.Load(); n != 0 {
+		t.Errorf("rebooted %d times after declining to save, want 0", n)
+	}
+	if cmds := sw.Commands(); slices.Contains(cmds, "copy running-config startup-config") {
+		t.Errorf("commands %q include a save", cmds)
+	}
+}
+
+func TestRebootUnknownQuestion(t *testing.T) {
+	var rebooted atomic.Int32
+	sw := rebootSwitch("Erase the flash? (Y/N):", &rebooted)
+	d, _ := startDevice(t, sw, client.WithCommandTimeout(200*time.Millisecond))
+
+	_, err := d.Reboot(context.Background(), false)
+	if !errors.Is(err, client.ErrPromptTimeout) {
+		t.Fatalf("Reboot error = %v, want ErrPromptTimeout", err)
+	}
+	if n := rebooted.Load(); n != 0 {
+		t.Errorf("rebooted %d times, want 0", n)
+	}
+}
+
+func TestRebootClosedBeforeConfirmation(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.Handler = func(_ testutil.Mode, cmd string) (string, bool) {
+		if cmd == "reboot" {
+			sw.Drop()
+			return "", true
+		}
+		return "", false
+	}
+	d, _ := startDevice(t, sw)
+
+	if _, err := d.Reboot(context.Background(), false); err == nil {
+		t.Fatal("Reboot succeeded although the session closed before any confirmation")
+	}
+}
@@ -0,0 +1,196 @@
+package device_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/device"
+	"github.com/pascal71/tplink-go/profile"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+// Prompts of the replayed switch.
+const (
+	userPrompt   = "SG2210XMP-M2>"
+	privPrompt   = "SG2210XMP-M2#"
+	configPrompt = "SG2210XMP-M2(config)#"
+	vlanPrompt   = "SG2210XMP-M2(config-vlan)#"
+	ifPrompt     = "SG2210XMP-M2(config-if)#"
+	rangePrompt  = "SG2210XMP-M2(config-if-range)#"
+)
+
+// reply returns the recorded reply of a switch echoing cmd, printing output
+// and ending with prompt, as Recorder captures it.
+func reply(cmd, output, prompt string) testutil.Interaction {
+	raw := cmd + "\r\n"
+	if output != "" {
+		raw += strings.ReplaceAll(output, "\n", "\r\n") + "\r\n"
+	}
+	return testutil.Interaction{Command: cmd, Output: raw + "\r\n" + prompt, Prompt: prompt}
+}
+
+// setupReplies are the replies to the default profile's session setup.
+var setupReplies = []testutil.Interaction{
+	reply("enable", "", privPrompt),
+	reply("config", "", configPrompt),
+	reply("no clipaging", "", configPrompt),
+	reply("exit", "", privPrompt),
+}
+
+// sentTransport is a replay transport remembering the lines sent to it.
+type sentTransport struct {
+	*testutil.ReplayTransport
+
+	mu   sync.Mutex
+	sent bytes.Buffer
+}
+
+func (t *sentTransport) Open(ctx context.Context) (io.Writer, io.Reader, error) {
+	w, r, err := t.ReplayTransport.Open(ctx)
+	return writerFunc(func(p []byte) (int, error) {
+		t.mu.Lock()
+		t.sent.Write(p)
+		t.mu.Unlock()
+		return w.Write(p)
+	}), r, err
+}
+
+// commands returns the command lines sent so far.
+func (t *sentTransport) commands() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sent.Len() == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(t.sent.String(), "\r\n"), "\r\n")
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+// replayDevice returns a Device driving a client connected to a replay of
+// the setup replies followed by replies.
+func replayDevice(t *testing.T, replies ...testutil.Interaction) (*device.Device, *sentTransport) {
+	t.Helper()
+	return replayProfileDevice(t, profile.Default, replies...)
+}
+
+// replayProfileDevice is replayDevice with the device driven by profile p.
+func replayProfileDevice(t *testing.T, p profile.Profile, replies ...testutil.Interaction) (*device.Device, *sentTransport) {
+	t.Helper()
+	f := &testutil.Fixture{
+		Device:       "SG2210XMP-M2",
+		Greeting:     "\r\n" + userPrompt,
+		Interactions: append(append([]testutil.Interaction(nil), setupReplies...), replies...),
+	}
+	tr := &sentTransport{ReplayTransport: testutil.NewReplayTransport(f)}
+	c := client.NewClient("replay", "admin", "admin", client.WithTransport(tr), client.WithCommandTimeout(2*time.Second))
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(c.Close)
+	return device.New(c, device.WithProfile(p)), tr
+}
+
+func TestConfigureReplay(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("configure", "", configPrompt),
+		reply("hostname core1", "", configPrompt),
+		reply("end", "", privPrompt),
+	)
+
+	if err := d.Configure(context.Background(), "hostname core1"); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	want := []string{"enable", "config", "no clipaging", "exit", "configure", "hostname core1", "end"}
+	if got := tr.commands(); !slices.Equal(got, want) {
+		t.Errorf("sent %q, want %q", got, want)
+	}
+}
+
+func TestConfigureReplayRejected(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("configure", "", configPrompt),
+		reply("hostname core1", "", configPrompt),
+		reply("ip http server bogus", "Error: Bad command", configPrompt),
+		reply("end", "", privPrompt),
+	)
+
+	err := d.Configure(context.Background(), "hostname core1", "ip http server bogus", "snmp-server")
+	var rejected *client.CommandRejectedError
+	if !errors.As(err, &rejected) {
+		t.Fatalf("Configure error = %v, want *CommandRejectedError", err)
+	}
+	if !strings.HasPrefix(err.Error(), "ip http server bogus: ") {
+		t.Errorf("error %q does not name the failing command", err)
+	}
+	want := []string{"enable", "config", "no clipaging", "exit", "configure", "hostname core1", "ip http server bogus", "end"}
+	if got := tr.commands(); !slices.Equal(got, want) {
+		t.Errorf("sent %q, want %q: stop at the rejected command and return to privileged mode", got, want)
+	}
+}
+
+// wantSent fails t unless tr was sent the setup commands followed by cmds.
+func wantSent(t *testing.T, tr *sentTransport, cmds ...string) {
+	t.Helper()
+	want := []string{"enable", "config", "no clipaging", "exit"}
+	want = append(want, cmds...)
+	if got := tr.commands(); !slices.Equal(got, want) {
+		t.Errorf("sent %q, want %q", got, want)
+	}
+}
+
+// vlanTable returns "show vlan" output listing VLAN 1 and VLAN 10 named name.
+func vlanTable(name string) string {
+	return `VLAN  Name         Status   Ports
+----  -----------  -------  -----------------
+1     System-VLAN  active   Gi1/0/1-8
+` + fmt.Sprintf("10    %-11s  active   Gi1/0/9(u),Gi1/0/10(t)", name)
+}
+
+func TestSetVLANNameReplay(t *testing.T) {
+	d, _ := replayDevice(t,
+		reply("show vlan", vlanTable("old"), privPrompt),
+		reply("show vlan", vlanTable("new"), privPrompt),
+		reply("configure", "", configPrompt),
+		reply("vlan 10", "", vlanPrompt),
+		reply("name new", "", vlanPrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+	)
+
+	if err := d.SetVLANName(context.Background(), 10, "new"); err != nil {
+		t.Fatalf("SetVLANName: %v", err)
+	}
+}
+
+func TestSetVLANNameReplayVerifyError(t *testing.T) {
+	// The switch accepts the rename but still lists the old name.
+	d, _ := replayDevice(t,
+		reply("show vlan", vlanTable("old"), privPrompt),
+		reply("configure", "", configPrompt),
+		reply("vlan 10", "", vlanPrompt),
+		reply("name new", "", vlanPrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+	)
+
+	err := d.SetVLANName(context.Background(), 10, "new")
+	var ve *device.VerifyError
+	if !errors.As(err, &ve) {
+		t.Fatalf("SetVLANName error = %v, want *VerifyError", err)
+	}
+	if ve.Setting != "vlan 10 name" || ve.Want != "new" || ve.Got != "old" || ve.Port != "" {
+		t.Errorf("VerifyError = %+v, want vlan 10 name new, got old", ve)
+	}
+}
@@ -0,0 +1,39 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pascal71/tplink-go/client"
+)
+
+// ErrSaveFailed is returned when the switch reports that saving the
+// configuration failed.
+var ErrSaveFailed = errors.New("saving configuration failed")
+
+// saveCommand copies the running configuration to the startup
+// configuration.
+const saveCommand = "copy running-config startup-config"
+
+// SaveConfig saves the running configuration, so it survives a reboot,
+// confirming the switch's question if it asks one. Changes made with the
+// other methods take effect at once but are lost on reboot until saved.
+func (d *Device) SaveConfig(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.setup(ctx); err != nil {
+		return err
+	}
+	f, err := d.runWith(ctx, saveCommand, client.Confirm("y"), client.NoRetry())
+	if err != nil {
+		return fmt.Errorf("%s: %w", saveCommand, err)
+	}
+	for line := range strings.Lines(f.Payload) {
+		if l := strings.ToLower(line); strings.Contains(l, "fail") || strings.Contains(l, "error") {
+			return fmt.Errorf("%w: %s", ErrSaveFailed, strings.TrimSpace(line))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package device_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pascal71/tplink-go/device"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+// saveQuestion is the switch asking to confirm "copy running-config
+// startup-config", as Recorder captures it: the question ends the output in
+// place of a prompt.
+var saveQuestion = testutil.Interaction{
+	Command: "copy running-config startup-config",
+	Output:  "copy running-config startup-config\r\nSave current configuration? (Y/N):",
+}
+
+func TestSaveConfigReplay(t *testing.T) {
+	d, tr := replayDevice(t,
+		saveQuestion,
+		reply("y", "Saving user config OK!", privPrompt),
+	)
+
+	if err := d.SaveConfig(context.Background()); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	wantSent(t, tr, "copy running-config startup-config", "y")
+}
+
+func TestSaveConfigReplayNoQuestion(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("copy running-config startup-config", "Saving user config OK!", privPrompt),
+	)
+
+	if err := d.SaveConfig(context.Background()); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	wantSent(t, tr, "copy running-config startup-config")
+}
+
+func TestSaveConfigReplayFailed(t *testing.T) {
+	d, tr := replayDevice(t,
+		saveQuestion,
+		reply("y", "Saving user config failed: flash busy", privPrompt),
+	)
+
+	err := d.SaveConfig(context.Background())
+	if !errors.Is(err, device.ErrSaveFailed) {
+		t.Fatalf("SaveConfig error = %v, want ErrSaveFailed", err)
+	}
+	if want := "saving configuration failed: Saving user config failed: flash busy"; err.Error() != want {
+		t.Errorf("error %q, want %q", err, want)
+	}
+	// The save must not be retried.
+	wantSent(t, tr, "copy running-config startup-config", "y")
+}
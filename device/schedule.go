@@ -0,0 +1,113 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pascal71/tplink-go/parser"
+)
+
+// PoESchedule is when a port supplies PoE, as a named time-range of the
+// switch. Outside its periods the port is powered down.
+type PoESchedule struct {
+	Name    string           // time-range name
+	Periods []SchedulePeriod // weekly periods; the range is active during any of them
+}
+
+// SchedulePeriod is a daily period on some days of the week.
+type SchedulePeriod struct {
+	Start string         // "hh:mm", e.g. "07:00"
+	End   string         // "hh:mm", e.g. "19:00"
+	Days  []time.Weekday // days the period applies to; none for every day
+}
+
+// command returns the time-range entry for p, e.g. "periodic start 07:00
+// end 19:00 day-of-week 1-5". The CLI numbers days from Monday, 1, to
+// Sunday, 7.
+func (p SchedulePeriod) command() (string, error) {
+	for _, t := range []string{p.Start, p.End} {
+		if _, err := time.Parse("15:04", t); err != nil {
+			return "", fmt.Errorf("invalid time of day %q", t)
+		}
+	}
+	days := "1-7"
+	if len(p.Days) > 0 {
+		var nums []int
+		for _, d := range p.Days {
+			if d < time.Sunday || d > time.Saturday {
+				return "", fmt.Errorf("invalid weekday %d", d)
+			}
+			n := int(d)
+			if d == time.Sunday {
+				n = 7
+			}
+			nums = append(nums, n)
+		}
+		slices.Sort(nums)
+		nums = slices.Compact(nums)
+		parts := make([]string, len(nums))
+		for i, n := range nums {
+			parts[i] = strconv.Itoa(n)
+		}
+		days = strings.Join(parts, ",")
+	}
+	return fmt.Sprintf("periodic start %s end %s day-of-week %s", p.Start, p.End, days), nil
+}
+
+// SetPoESchedule creates the time-range of s, adding its periods to any
+// the range already has, binds it to port and verifies the binding.
+func (d *Device) SetPoESchedule(ctx context.Context, port parser.PortID, s PoESchedule) error {
+	port = parser.NormalizePortID(string(port))
+	if s.Name == "" || strings.ContainsAny(s.Name, " \t") {
+		return fmt.Errorf("invalid time-range name %q", s.Name)
+	}
+	if len(s.Periods) == 0 {
+		return fmt.Errorf("time-range %s: no periods", s.Name)
+	}
+	iface, err := interfaceName(port)
+	if err != nil {
+		return err
+	}
+	cmds := []string{"time-range " + s.Name}
+	for _, p := range s.Periods {
+		cmd, err := p.command()
+		if err != nil {
+			return fmt.Errorf("time-range %s: %w", s.Name, err)
+		}
+		cmds = append(cmds, cmd)
+	}
+	cmds = append(cmds, "exit", "interface "+iface, "power inline time-range "+s.Name)
+	if err := d.Configure(ctx, cmds...); err != nil {
+		return err
+	}
+	c, err := d.poeConfigOf(ctx, port)
+	if err != nil {
+		return err
+	}
+	if c.TimeRange != s.Name {
+		return &VerifyError{Port: port, Setting: "poe time-range", Want: s.Name, Got: c.TimeRange}
+	}
+	return nil
+}
+
+// ClearPoESchedule unbinds the time-range of port, so it is powered at
+// all times, and verifies it. The time-range itself is kept.
+func (d *Device) ClearPoESchedule(ctx context.Context, port parser.PortID) error {
+	port = parser.NormalizePortID(string(port))
+	if err := d.configureInterface(ctx, port, "no power inline time-range"); err != nil {
+		return err
+	}
+	c, err := d.poeConfigOf(ctx, port)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(c.TimeRange) {
+	case "", "no limit", "none", "n/a", "-":
+		return nil
+	}
+	return &VerifyError{Port: port, Setting: "poe time-range", Want: "", Got: c.TimeRange}
+}
@@ -0,0 +1,99 @@
+package device_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pascal71/tplink-go/device"
+	"github.com/pascal71/tplink-go/profile"
+)
+
+const timeRangePrompt = "SG2210XMP-M2(config-time-range)#"
+
+func TestSetPoEScheduleReplay(t *testing.T) {
+	p, ok := profile.Lookup("SG2210XMP")
+	if !ok {
+		t.Fatal("no SG2210XMP profile")
+	}
+	d, tr := replayProfileDevice(t, p,
+		reply("configure", "", configPrompt),
+		reply("time-range office", "", timeRangePrompt),
+		reply("periodic start 07:00 end 19:00 day-of-week 1,2,3,4,5", "", timeRangePrompt),
+		reply("periodic start 09:00 end 13:00 day-of-week 6,7", "", timeRangePrompt),
+		reply("periodic start 00:00 end 06:00 day-of-week 1-7", "", timeRangePrompt),
+		reply("exit", "", configPrompt),
+		reply("interface gigabitEthernet 1/0/2", "", ifPrompt),
+		reply("power inline time-range office", "", ifPrompt),
+		reply("end", "", privPrompt),
+		reply("show power inline configuration interface", poeConfigTable("Low", "Class4", "office", "No Profile"), privPrompt),
+	)
+
+	err := d.SetPoESchedule(context.Background(), "gi1/0/2", device.PoESchedule{
+		Name: "office",
+		Periods: []device.SchedulePeriod{
+			{Start: "07:00", End: "19:00", Days: []time.Weekday{time.Friday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Monday}},
+			{Start: "09:00", End: "13:00", Days: []time.Weekday{time.Sunday, time.Saturday}},
+			{Start: "00:00", End: "06:00"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SetPoESchedule: %v", err)
+	}
+	wantSent(t, tr, "configure", "time-range office",
+		"periodic start 07:00 end 19:00 day-of-week 1,2,3,4,5",
+		"periodic start 09:00 end 13:00 day-of-week 6,7",
+		"periodic start 00:00 end 06:00 day-of-week 1-7",
+		"exit", "interface gigabitEthernet 1/0/2", "power inline time-range office", "end",
+		"show power inline configuration interface")
+}
+
+func TestSetPoEScheduleInvalidSendsNothing(t *testing.T) {
+	d, tr := replayDevice(t)
+	ctx := context.Background()
+	for _, s := range []device.PoESchedule{
+		{Name: "", Periods: []device.SchedulePeriod{{Start: "07:00", End: "19:00"}}},
+		{Name: "after hours", Periods: []device.SchedulePeriod{{Start: "07:00", End: "19:00"}}},
+		{Name: "office"},
+		{Name: "office", Periods: []device.SchedulePeriod{{Start: "7am", End: "19:00"}}},
+		{Name: "office", Periods: []device.SchedulePeriod{{Start: "07:00", End: "19:00", Days: []time.Weekday{7}}}},
+	} {
+		if err := d.SetPoESchedule(ctx, "Gi1/0/2", s); err == nil {
+			t.Errorf("SetPoESchedule %+v: want an error", s)
+		}
+	}
+	if got := tr.commands(); got != nil {
+		t.Errorf("sent %q, want nothing", got)
+	}
+}
+
+func TestClearPoEScheduleReplay(t *testing.T) {
+	p, ok := profile.Lookup("SG2210XMP")
+	if !ok {
+		t.Fatal("no SG2210XMP profile")
+	}
+	replies := func(timeRange string) (*device.Device, *sentTransport) {
+		return replayProfileDevice(t, p,
+			reply("configure", "", configPrompt),
+			reply("interface gigabitEthernet 1/0/2", "", ifPrompt),
+			reply("no power inline time-range", "", ifPrompt),
+			reply("exit", "", configPrompt),
+			reply("end", "", privPrompt),
+			reply("show power inline configuration interface", poeConfigTable("Low", "Class4", timeRange, "No Profile"), privPrompt),
+		)
+	}
+
+	d, tr := replies("No Limit")
+	if err := d.ClearPoESchedule(context.Background(), "Gi1/0/2"); err != nil {
+		t.Fatalf("ClearPoESchedule: %v", err)
+	}
+	wantSent(t, tr, "configure", "interface gigabitEthernet 1/0/2", "no power inline time-range", "exit", "end",
+		"show power inline configuration interface")
+
+	d, _ = replies("office")
+	var ve *device.VerifyError
+	if err := d.ClearPoESchedule(context.Background(), "Gi1/0/2"); !errors.As(err, &ve) || ve.Got != "office" {
+		t.Errorf("ClearPoESchedule with the range still bound = %v, want a VerifyError", err)
+	}
+}
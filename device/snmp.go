@@ -0,0 +1,315 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/parser"
+	"github.com/pascal71/tplink-go/profile"
+)
+
+// SNMPv3User is an SNMPv3 user to create, with the group it belongs to.
+type SNMPv3User struct {
+	Name            string
+	Group           string // created with read and notify access to View
+	View            string // MIB view, "viewDefault" if empty
+	AuthMode        string // "MD5" or "SHA", none if empty
+	AuthPassword    string
+	PrivacyMode     string // "DES", none if empty; needs AuthMode
+	PrivacyPassword string
+}
+
+// level returns the security level of u as the CLI names it.
+func (u SNMPv3User) level() string {
+	switch {
+	case u.PrivacyMode != "":
+		return "authPriv"
+	case u.AuthMode != "":
+		return "authNoPriv"
+	}
+	return "noAuthNoPriv"
+}
+
+// SNMPConfig returns the SNMP agent configuration: its state and the
+// communities, users and notification receivers. Lists the model has no
+// command for are left empty.
+func (d *Device) SNMPConfig(ctx context.Context) (parser.SNMPConfig, error) {
+	cfg, err := show(ctx, d, profile.CmdSNMP, parser.ParseSNMPConfig)
+	if err != nil {
+		return parser.SNMPConfig{}, err
+	}
+	for _, name := range []string{profile.CmdSNMPCommunity, profile.CmdSNMPUser, profile.CmdSNMPHost} {
+		part, err := show(ctx, d, name, parser.ParseSNMPConfig)
+		switch {
+		case errors.Is(err, client.ErrCommandRejected) || errors.Is(err, ErrUnsupported):
+			continue
+		case err != nil:
+			return parser.SNMPConfig{}, err
+		}
+		// Lists "show snmp-server" already printed are not repeated.
+		if len(cfg.Communities) == 0 {
+			cfg.Communities = part.Communities
+		}
+		if len(cfg.Users) == 0 {
+			cfg.Users = part.Users
+		}
+		if len(cfg.TrapHosts) == 0 {
+			cfg.TrapHosts = part.TrapHosts
+		}
+	}
+	return cfg, nil
+}
+
+// EnableSNMP turns the SNMP agent on or off and verifies it.
+func (d *Device) EnableSNMP(ctx context.Context, on bool) error {
+	cmd := "no snmp-server"
+	if on {
+		cmd = "snmp-server"
+	}
+	if err := d.Configure(ctx, cmd); err != nil {
+		return err
+	}
+	cfg, err := show(ctx, d, profile.CmdSNMP, parser.ParseSNMPConfig)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	if cfg.Enabled != on {
+		return &VerifyError{Setting: "snmp agent", Want: strconv.FormatBool(on), Got: strconv.FormatBool(cfg.Enabled)}
+	}
+	return nil
+}
+
+// SetSNMPCommunity creates or changes the SNMPv1/v2c community c, with
+// Access "read-only" or "read-write" and an optional View, and verifies it.
+func (d *Device) SetSNMPCommunity(ctx context.Context, c parser.SNMPCommunity) error {
+	if err := checkSNMPWord("community", c.Name); err != nil {
+		return err
+	}
+	if c.Access != "read-only" && c.Access != "read-write" {
+		return fmt.Errorf("invalid SNMP community access %q", c.Access)
+	}
+	cmd := "snmp-server community " + c.Name + " " + c.Access
+	if c.View != "" {
+		cmd += " " + c.View
+	}
+	if err := d.Configure(ctx, cmd); err != nil {
+		return err
+	}
+	cfg, err := d.SNMPConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	i := slices.IndexFunc(cfg.Communities, func(g parser.SNMPCommunity) bool { return g.Name == c.Name })
+	if i < 0 {
+		return &VerifyError{Setting: "snmp community " + c.Name, Want: c.Access, Got: "absent"}
+	}
+	if got := normalizeAccess(cfg.Communities[i].Access); got != c.Access {
+		return &VerifyError{Setting: "snmp community " + c.Name, Want: c.Access, Got: cfg.Communities[i].Access}
+	}
+	return nil
+}
+
+// RemoveSNMPCommunity deletes the community name and verifies it is gone.
+func (d *Device) RemoveSNMPCommunity(ctx context.Context, name string) error {
+	if err := checkSNMPWord("community", name); err != nil {
+		return err
+	}
+	if err := d.Configure(ctx, "no snmp-server community "+name); err != nil {
+		return err
+	}
+	cfg, err := d.SNMPConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	if slices.ContainsFunc(cfg.Communities, func(g parser.SNMPCommunity) bool { return g.Name == name }) {
+		return &VerifyError{Setting: "snmp community " + name, Want: "absent", Got: "present"}
+	}
+	return nil
+}
+
+// SetSNMPUser creates the SNMPv3 user u and its group and verifies the
+// user belongs to the group. The passwords are kept out of logs and errors.
+func (d *Device) SetSNMPUser(ctx context.Context, u SNMPv3User) error {
+	if err := checkSNMPWord("user", u.Name); err != nil {
+		return err
+	}
+	if err := checkSNMPWord("group", u.Group); err != nil {
+		return err
+	}
+	if u.View == "" {
+		u.View = "viewDefault"
+	}
+	switch {
+	case u.AuthMode != "" && u.AuthMode != "MD5" && u.AuthMode != "SHA":
+		return fmt.Errorf("invalid SNMPv3 auth mode %q", u.AuthMode)
+	case u.PrivacyMode != "" && u.PrivacyMode != "DES":
+		return fmt.Errorf("invalid SNMPv3 privacy mode %q", u.PrivacyMode)
+	case u.PrivacyMode != "" && u.AuthMode == "":
+		return errors.New("SNMPv3 privacy needs authentication")
+	case u.AuthMode != "" && u.AuthPassword == "" || u.PrivacyMode != "" && u.PrivacyPassword == "":
+		return errors.New("SNMPv3 password missing")
+	}
+	group := fmt.Sprintf("snmp-server group %s smode v3 slev %s read %s notify %s", u.Group, u.level(), u.View, u.View)
+	user := fmt.Sprintf("snmp-server user %s local %s smode v3 slev %s", u.Name, u.Group, u.level())
+	if u.AuthMode != "" {
+		user += " cmode " + u.AuthMode + " cpwd " + u.AuthPassword
+	}
+	if u.PrivacyMode != "" {
+		user += " emode " + u.PrivacyMode + " epwd " + u.PrivacyPassword
+	}
+	if err := d.configure(ctx, []string{u.AuthPassword, u.PrivacyPassword}, group, user); err != nil {
+		return err
+	}
+	cfg, err := d.SNMPConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	i := slices.IndexFunc(cfg.Users, func(g parser.SNMPUser) bool { return g.Name == u.Name })
+	if i < 0 {
+		return &VerifyError{Setting: "snmp user " + u.Name, Want: u.Group, Got: "absent"}
+	}
+	if got := cfg.Users[i].Group; got != u.Group {
+		return &VerifyError{Setting: "snmp user " + u.Name + " group", Want: u.Group, Got: got}
+	}
+	return nil
+}
+
+// RemoveSNMPUser deletes the SNMPv3 user name, keeping its group, and
+// verifies it is gone.
+func (d *Device) RemoveSNMPUser(ctx context.Context, name string) error {
+	if err := checkSNMPWord("user", name); err != nil {
+		return err
+	}
+	if err := d.Configure(ctx, "no snmp-server user "+name); err != nil {
+		return err
+	}
+	cfg, err := d.SNMPConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	if slices.ContainsFunc(cfg.Users, func(g parser.SNMPUser) bool { return g.Name == name }) {
+		return &VerifyError{Setting: "snmp user " + name, Want: "absent", Got: "present"}
+	}
+	return nil
+}
+
+// AddSNMPHost adds the notification receiver h and verifies it. Port
+// defaults to 162, SecurityModel to "v2c" and Type to "trap"; Community
+// is the community, or for v3 the user, notifications are sent as, and
+// SecurityLevel the v3 security level, "noAuthNoPriv" if empty.
+func (d *Device) AddSNMPHost(ctx context.Context, h parser.SNMPHost) error {
+	if h.Address == "" || strings.ContainsAny(h.Address, " \t\r\n") {
+		return fmt.Errorf("invalid SNMP host address %q", h.Address)
+	}
+	if err := checkSNMPWord("community", h.Community); err != nil {
+		return err
+	}
+	if h.Port == 0 {
+		h.Port = 162
+	}
+	if h.SecurityModel == "" {
+		h.SecurityModel = "v2c"
+	}
+	if h.Type == "" {
+		h.Type = "trap"
+	}
+	switch {
+	case h.SecurityModel != "v1" && h.SecurityModel != "v2c" && h.SecurityModel != "v3":
+		return fmt.Errorf("invalid SNMP security model %q", h.SecurityModel)
+	case h.Type != "trap" && h.Type != "inform":
+		return fmt.Errorf("invalid SNMP notification type %q", h.Type)
+	}
+	cmd := fmt.Sprintf("snmp-server host %s %d %s smode %s", h.Address, h.Port, h.Community, h.SecurityModel)
+	if h.SecurityModel == "v3" {
+		if h.SecurityLevel == "" {
+			h.SecurityLevel = "noAuthNoPriv"
+		}
+		cmd += " slev " + h.SecurityLevel
+	}
+	cmd += " type " + h.Type
+	if err := d.Configure(ctx, cmd); err != nil {
+		return err
+	}
+	cfg, err := d.SNMPConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	if !slices.ContainsFunc(cfg.TrapHosts, func(g parser.SNMPHost) bool {
+		return g.Address == h.Address && g.Community == h.Community
+	}) {
+		return &VerifyError{Setting: "snmp host " + h.Address, Want: "present", Got: "absent"}
+	}
+	return nil
+}
+
+// RemoveSNMPHost deletes the notification receiver at address sending as
+// community and verifies it is gone.
+func (d *Device) RemoveSNMPHost(ctx context.Context, address, community string) error {
+	if err := checkSNMPWord("community", community); err != nil {
+		return err
+	}
+	if err := d.Configure(ctx, "no snmp-server host "+address+" "+community); err != nil {
+		return err
+	}
+	cfg, err := d.SNMPConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	if slices.ContainsFunc(cfg.TrapHosts, func(g parser.SNMPHost) bool {
+		return g.Address == address && g.Community == community
+	}) {
+		return &VerifyError{Setting: "snmp host " + address, Want: "absent", Got: "present"}
+	}
+	return nil
+}
+
+// SetSystemLocation sets the location reported as sysLocation and
+// verifies it.
+func (d *Device) SetSystemLocation(ctx context.Context, location string) error {
+	return d.setSystemInfo(ctx, "location", location, func(s parser.SystemInfo) string { return s.Location })
+}
+
+// SetSystemContact sets the contact reported as sysContact and verifies
+// it.
+func (d *Device) SetSystemContact(ctx context.Context, contact string) error {
+	return d.setSystemInfo(ctx, "contact-info", contact, func(s parser.SystemInfo) string { return s.Contact })
+}
+
+// setSystemInfo runs the global command keyword with val and verifies the
+// system-info field got returns.
+func (d *Device) setSystemInfo(ctx context.Context, keyword, val string, got func(parser.SystemInfo) string) error {
+	if val == "" || strings.ContainsAny(val, "\r\n") {
+		return fmt.Errorf("invalid %s %q", keyword, val)
+	}
+	if err := d.Configure(ctx, keyword+" "+val); err != nil {
+		return err
+	}
+	info, err := show(ctx, d, profile.CmdSystemInfo, parser.ParseSystemInfo)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	if g := got(info); g != val {
+		return &VerifyError{Setting: keyword, Want: val, Got: g}
+	}
+	return nil
+}
+
+// checkSNMPWord returns an error unless name, of the kind given, is one
+// non-empty word.
+func checkSNMPWord(kind, name string) error {
+	if name == "" || strings.ContainsAny(name, " \t\r\n") {
+		return fmt.Errorf("invalid SNMP %s name %q", kind, name)
+	}
+	return nil
+}
+
+// normalizeAccess returns community access as printed, e.g. "Read-Only"
+// or "read_only", in the form the CLI takes.
+func normalizeAccess(access string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(access)), "_", "-")
+}
@@ -0,0 +1,52 @@
+package device_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/device"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+func TestSetSNMPUserKeepsPasswordsSecret(t *testing.T) {
+	sw := testutil.NewSwitch()
+	sw.Handler = func(mode testutil.Mode, cmd string) (string, bool) {
+		if strings.HasPrefix(cmd, "snmp-server user ") {
+			return "Error: Bad command", true
+		}
+		return "", false
+	}
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	d, _ := startDevice(t, sw, client.WithLogger(logger))
+
+	err := d.SetSNMPUser(context.Background(), device.SNMPv3User{
+		Name: "nms", Group: "nmsgroup",
+		AuthMode: "SHA", AuthPassword: "authpass1",
+		PrivacyMode: "DES", PrivacyPassword: "privpass1",
+	})
+	var rejected *client.CommandRejectedError
+	if !errors.As(err, &rejected) {
+		t.Fatalf("err = %v, want a CommandRejectedError", err)
+	}
+	for _, s := range []string{err.Error(), rejected.Command, logs.String()} {
+		for _, pw := range []string{"authpass1", "privpass1"} {
+			if strings.Contains(s, pw) {
+				t.Errorf("password %s disclosed in %q", pw, s)
+			}
+		}
+	}
+	// The switch still got the real command.
+	var sent bool
+	for _, cmd := range sw.Commands() {
+		sent = sent || strings.Contains(cmd, "cpwd authpass1 emode DES epwd privpass1")
+	}
+	if !sent {
+		t.Errorf("user command with passwords not sent: %q", sw.Commands())
+	}
+}
@@ -0,0 +1,139 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pascal71/tplink-go/parser"
+)
+
+// PortMode is the 802.1Q mode of a port.
+type PortMode string
+
+// Port modes of the TP-Link CLI.
+const (
+	PortModeAccess  PortMode = "access"  // untagged member of one VLAN
+	PortModeTrunk   PortMode = "trunk"   // tagged member of the allowed VLANs
+	PortModeGeneral PortMode = "general" // untagged in the PVID, tagged in the other allowed VLANs
+)
+
+// ConfigurePort sets the mode, PVID and allowed VLANs of port and verifies
+// them:
+//
+//   - access: the port is an untagged member of pvid only; allowed must be
+//     empty or just pvid.
+//   - trunk: the port is a tagged member of allowed; a non-zero pvid is set
+//     as its PVID.
+//   - general: the port is an untagged member of pvid, which is required,
+//     and a tagged member of the other allowed VLANs.
+//
+// The VLANs must exist.
+func (d *Device) ConfigurePort(ctx context.Context, port parser.PortID, mode PortMode, pvid int, allowed []int) error {
+	port = parser.NormalizePortID(string(port))
+	allowed = slices.Clone(allowed)
+	slices.Sort(allowed)
+	allowed = slices.Compact(allowed)
+	for _, id := range allowed {
+		if err := checkVLAN(id); err != nil {
+			return err
+		}
+	}
+	if pvid != 0 {
+		if err := checkVLAN(pvid); err != nil {
+			return err
+		}
+	}
+
+	cmds := []string{"switchport mode " + string(mode)}
+	switch mode {
+	case PortModeAccess:
+		if pvid == 0 {
+			return fmt.Errorf("port %s: access mode needs a VLAN", port)
+		}
+		if len(allowed) > 1 || len(allowed) == 1 && allowed[0] != pvid {
+			return fmt.Errorf("port %s: access mode allows only VLAN %d", port, pvid)
+		}
+		cmds = append(cmds, "switchport access vlan "+strconv.Itoa(pvid))
+	case PortModeTrunk:
+		if len(allowed) == 0 {
+			return fmt.Errorf("port %s: trunk mode needs allowed VLANs", port)
+		}
+		cmds = append(cmds, "switchport trunk allowed vlan "+vlanList(allowed))
+		if pvid != 0 {
+			cmds = append(cmds, "switchport pvid "+strconv.Itoa(pvid))
+		}
+	case PortModeGeneral:
+		if pvid == 0 {
+			return fmt.Errorf("port %s: general mode needs a PVID", port)
+		}
+		if tagged := slices.DeleteFunc(slices.Clone(allowed), func(id int) bool { return id == pvid }); len(tagged) > 0 {
+			cmds = append(cmds, "switchport general allowed vlan "+vlanList(tagged)+" tagged")
+		}
+		cmds = append(cmds,
+			"switchport general allowed vlan "+strconv.Itoa(pvid)+" untagged",
+			"switchport pvid "+strconv.Itoa(pvid))
+	default:
+		return fmt.Errorf("invalid port mode %q", mode)
+	}
+	if err := d.configureInterface(ctx, port, cmds...); err != nil {
+		return err
+	}
+	return d.verifySwitchport(ctx, port, mode, pvid, allowed)
+}
+
+// verifySwitchport checks the mode, PVID and allowed VLANs of port as
+// ConfigurePort sets them. VLANs the switch reports beyond allowed, such
+// as a default membership, are not an error.
+func (d *Device) verifySwitchport(ctx context.Context, port parser.PortID, mode PortMode, pvid int, allowed []int) error {
+	sw, err := d.Switchports(ctx)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	i := slices.IndexFunc(sw, func(s parser.Switchport) bool { return s.Port == port })
+	if i < 0 {
+		return fmt.Errorf("verify: port %s not in switchport configuration", port)
+	}
+	s := sw[i]
+	if s.Mode != "" && s.Mode != string(mode) {
+		return &VerifyError{Port: port, Setting: "port mode", Want: string(mode), Got: s.Mode}
+	}
+	if pvid != 0 && s.PVID != pvid {
+		return &VerifyError{Port: port, Setting: "pvid", Want: strconv.Itoa(pvid), Got: strconv.Itoa(s.PVID)}
+	}
+	want := allowed
+	if mode != PortModeTrunk && !slices.Contains(want, pvid) {
+		want = append(slices.Clone(allowed), pvid)
+		slices.Sort(want)
+	}
+	if s.AllowedVLANs == nil {
+		// Summary tables list no VLANs.
+		return nil
+	}
+	for _, id := range want {
+		if !slices.Contains(s.AllowedVLANs, id) {
+			return &VerifyError{Port: port, Setting: "allowed vlans", Want: vlanList(want), Got: vlanList(s.AllowedVLANs)}
+		}
+	}
+	return nil
+}
+
+// vlanList formats sorted VLAN IDs as the CLI takes them, e.g. "1,10-12".
+func vlanList(ids []int) string {
+	var items []string
+	for i := 0; i < len(ids); i++ {
+		j := i
+		for j+1 < len(ids) && ids[j+1] == ids[j]+1 {
+			j++
+		}
+		item := strconv.Itoa(ids[i])
+		if j > i {
+			item += "-" + strconv.Itoa(ids[j])
+		}
+		items = append(items, item)
+		i = j
+	}
+	return strings.Join(items, ",")
+}
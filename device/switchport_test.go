@@ -0,0 +1,129 @@
+package device_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pascal71/tplink-go/device"
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+// switchportBlock returns the "show interface switchport" block of port,
+// listing each VLAN in egress with its rule, "Untagged" or "Tagged".
+func switchportBlock(port, typ string, pvid int, egress ...string) string {
+	out := fmt.Sprintf(`Port %s:
+  Type: %s
+  PVID: %d
+  Acceptable frame type: All
+  Ingress Checking: Enable
+
+  Vlan    Name             Egress-rule
+  ------- ---------------- -----------`, port, typ, pvid)
+	for i := 0; i+1 < len(egress); i += 2 {
+		out += fmt.Sprintf("\n  %-7s VLAN%-12s %s", egress[i], egress[i], egress[i+1])
+	}
+	return out + "\n"
+}
+
+func TestConfigurePortReplay(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    device.PortMode
+		pvid    int
+		allowed []int
+		cmds    []string // sent in interface mode
+		block   string   // read back
+	}{
+		{
+			"access", device.PortModeAccess, 10, nil,
+			[]string{"switchport mode access", "switchport access vlan 10"},
+			switchportBlock("Gi1/0/2", "Access", 10, "10", "Untagged"),
+		},
+		{
+			"trunk", device.PortModeTrunk, 0, []int{30, 20, 10, 11, 12, 20},
+			[]string{"switchport mode trunk", "switchport trunk allowed vlan 10-12,20,30"},
+			switchportBlock("Gi1/0/2", "Trunk", 1, "1", "Untagged", "10", "Tagged", "11", "Tagged", "12", "Tagged", "20", "Tagged", "30", "Tagged"),
+		},
+		{
+			"trunk with native VLAN", device.PortModeTrunk, 10, []int{10, 20},
+			[]string{"switchport mode trunk", "switchport trunk allowed vlan 10,20", "switchport pvid 10"},
+			switchportBlock("Gi1/0/2", "Trunk", 10, "10", "Tagged", "20", "Tagged"),
+		},
+		{
+			"general", device.PortModeGeneral, 10, []int{21, 20, 10},
+			[]string{"switchport mode general", "switchport general allowed vlan 20-21 tagged", "switchport general allowed vlan 10 untagged", "switchport pvid 10"},
+			switchportBlock("Gi1/0/2", "General", 10, "10", "Untagged", "20", "Tagged", "21", "Tagged"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies := []testutil.Interaction{
+				reply("configure", "", configPrompt),
+				reply("interface gigabitEthernet 1/0/2", "", ifPrompt),
+			}
+			for _, cmd := range tt.cmds {
+				replies = append(replies, reply(cmd, "", ifPrompt))
+			}
+			replies = append(replies,
+				reply("exit", "", configPrompt),
+				reply("end", "", privPrompt),
+				reply("show interface switchport", tt.block, privPrompt),
+			)
+			d, tr := replayDevice(t, replies...)
+
+			if err := d.ConfigurePort(context.Background(), "gi1/0/2", tt.mode, tt.pvid, tt.allowed); err != nil {
+				t.Fatalf("ConfigurePort: %v", err)
+			}
+			want := append([]string{"configure", "interface gigabitEthernet 1/0/2"}, tt.cmds...)
+			wantSent(t, tr, append(want, "exit", "end", "show interface switchport")...)
+		})
+	}
+}
+
+func TestConfigurePortReplayVerifyError(t *testing.T) {
+	// The switch took the trunk mode but not VLAN 30.
+	d, _ := replayDevice(t,
+		reply("configure", "", configPrompt),
+		reply("interface gigabitEthernet 1/0/2", "", ifPrompt),
+		reply("switchport mode trunk", "", ifPrompt),
+		reply("switchport trunk allowed vlan 20,30", "", ifPrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+		reply("show interface switchport", switchportBlock("Gi1/0/2", "Trunk", 1, "1", "Untagged", "20", "Tagged"), privPrompt),
+	)
+
+	err := d.ConfigurePort(context.Background(), "Gi1/0/2", device.PortModeTrunk, 0, []int{20, 30})
+	var ve *device.VerifyError
+	if !errors.As(err, &ve) {
+		t.Fatalf("ConfigurePort error = %v, want *VerifyError", err)
+	}
+	if ve.Port != "Gi1/0/2" || ve.Setting != "allowed vlans" || ve.Want != "20,30" || ve.Got != "1,20" {
+		t.Errorf("VerifyError = %+v, want allowed vlans of Gi1/0/2 20,30, got 1,20", ve)
+	}
+}
+
+func TestConfigurePortInvalidSendsNothing(t *testing.T) {
+	d, tr := replayDevice(t)
+	ctx := context.Background()
+	for _, tt := range []struct {
+		mode    device.PortMode
+		pvid    int
+		allowed []int
+	}{
+		{device.PortModeAccess, 0, nil},
+		{device.PortModeAccess, 10, []int{10, 20}},
+		{device.PortModeTrunk, 0, nil},
+		{device.PortModeGeneral, 0, []int{10}},
+		{device.PortModeTrunk, 0, []int{4095}},
+		{"hybrid", 10, nil},
+	} {
+		if err := d.ConfigurePort(ctx, "Gi1/0/2", tt.mode, tt.pvid, tt.allowed); err == nil {
+			t.Errorf("ConfigurePort %s %d %v: want an error", tt.mode, tt.pvid, tt.allowed)
+		}
+	}
+	if got := tr.commands(); got != nil {
+		t.Errorf("sent %q, want nothing", got)
+	}
+}
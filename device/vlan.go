@@ -0,0 +1,296 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pascal71/tplink-go/parser"
+	"github.com/pascal71/tplink-go/profile"
+)
+
+// ErrNoSuchVLAN is returned for a VLAN that does not exist on the switch.
+var ErrNoSuchVLAN = errors.New("no such VLAN")
+
+// VLANMembership is the member ports of a VLAN by egress rule.
+type VLANMembership struct {
+	Tagged   []parser.PortID // ports egressing the VLAN tagged
+	Untagged []parser.PortID // ports egressing the VLAN untagged
+}
+
+// VLANs returns the VLANs of the switch and their member ports.
+func (d *Device) VLANs(ctx context.Context) ([]parser.VLAN, error) {
+	return show(ctx, d, profile.CmdVLAN, parser.ParseVLANs)
+}
+
+// Switchports returns the 802.1Q configuration of each port.
+func (d *Device) Switchports(ctx context.Context) ([]parser.Switchport, error) {
+	return show(ctx, d, profile.CmdSwitchport, parser.ParseSwitchport)
+}
+
+// vlan reads back the VLAN with the ID, wrapping ErrNoSuchVLAN if there is
+// none.
+func (d *Device) vlan(ctx context.Context, id int) (parser.VLAN, error) {
+	vlans, err := d.VLANs(ctx)
+	if err != nil {
+		return parser.VLAN{}, err
+	}
+	for _, v := range vlans {
+		if v.ID == id {
+			return v, nil
+		}
+	}
+	return parser.VLAN{}, fmt.Errorf("vlan %d: %w", id, ErrNoSuchVLAN)
+}
+
+// checkVLAN returns an error unless id is a valid 802.1Q VLAN ID.
+func checkVLAN(id int) error {
+	if id < 1 || id > 4094 {
+		return fmt.Errorf("invalid VLAN ID %d", id)
+	}
+	return nil
+}
+
+// checkVLANName returns an error for a name the CLI cannot take as one
+// word.
+func checkVLANName(name string) error {
+	if strings.ContainsAny(name, " \t\r\n") {
+		return fmt.Errorf("invalid VLAN name %q", name)
+	}
+	return nil
+}
+
+// CreateVLAN creates the VLAN with the ID, or renames it if it exists, and
+// verifies it. An empty name keeps the switch's default name.
+func (d *Device) CreateVLAN(ctx context.Context, id int, name string) error {
+	if err := checkVLAN(id); err != nil {
+		return err
+	}
+	if err := checkVLANName(name); err != nil {
+		return err
+	}
+	cmds := []string{"vlan " + strconv.Itoa(id)}
+	if name != "" {
+		cmds = append(cmds, "name "+name)
+	}
+	if err := d.Configure(ctx, append(cmds, "exit")...); err != nil {
+		return err
+	}
+	return d.verifyVLANName(ctx, id, name)
+}
+
+// SetVLANName renames the existing VLAN with the ID and verifies it.
+func (d *Device) SetVLANName(ctx context.Context, id int, name string) error {
+	if err := checkVLAN(id); err != nil {
+		return err
+	}
+	if name == "" {
+		return fmt.Errorf("vlan %d: empty name", id)
+	}
+	if err := checkVLANName(name); err != nil {
+		return err
+	}
+	if _, err := d.vlan(ctx, id); err != nil {
+		return err
+	}
+	if err := d.Configure(ctx, "vlan "+strconv.Itoa(id), "name "+name, "exit"); err != nil {
+		return err
+	}
+	return d.verifyVLANName(ctx, id, name)
+}
+
+// verifyVLANName checks that the VLAN exists and, unless name is empty,
+// is named name.
+func (d *Device) verifyVLANName(ctx context.Context, id int, name string) error {
+	v, err := d.vlan(ctx, id)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	if name != "" && v.Name != name {
+		return &VerifyError{Setting: fmt.Sprintf("vlan %d name", id), Want: name, Got: v.Name}
+	}
+	return nil
+}
+
+// DeleteVLAN deletes the VLAN with the ID and verifies it is gone.
+// Deleting a VLAN that does not exist is not an error. VLAN 1, the
+// system VLAN, cannot be deleted.
+func (d *Device) DeleteVLAN(ctx context.Context, id int) error {
+	if err := checkVLAN(id); err != nil {
+		return err
+	}
+	if id == 1 {
+		return errors.New("vlan 1 cannot be deleted")
+	}
+	if err := d.Configure(ctx, "no vlan "+strconv.Itoa(id)); err != nil {
+		return err
+	}
+	_, err := d.vlan(ctx, id)
+	switch {
+	case errors.Is(err, ErrNoSuchVLAN):
+		return nil
+	case err != nil:
+		return fmt.Errorf("verify: %w", err)
+	}
+	return &VerifyError{Setting: fmt.Sprintf("vlan %d", id), Want: "deleted", Got: "present"}
+}
+
+// AddVLANMembers adds the ports of m to the existing VLAN with the ID,
+// keeping its other members, and verifies the membership.
+func (d *Device) AddVLANMembers(ctx context.Context, id int, m VLANMembership) error {
+	return d.setVLANMembers(ctx, id, m, false)
+}
+
+// SetVLANMembers makes the ports of m the only members of the existing
+// VLAN with the ID, removing any others, and verifies the membership.
+func (d *Device) SetVLANMembers(ctx context.Context, id int, m VLANMembership) error {
+	return d.setVLANMembers(ctx, id, m, true)
+}
+
+// RemoveVLANMembers removes ports from the VLAN with the ID and verifies
+// they are no longer members.
+func (d *Device) RemoveVLANMembers(ctx context.Context, id int, ports ...parser.PortID) error {
+	if err := checkVLAN(id); err != nil {
+		return err
+	}
+	ports = normalizePorts(ports)
+	if len(ports) == 0 {
+		return nil
+	}
+	cmds, err := interfaceCommands(ports, "no switchport general allowed vlan "+strconv.Itoa(id))
+	if err != nil {
+		return err
+	}
+	if err := d.Configure(ctx, cmds...); err != nil {
+		return err
+	}
+	return d.verifyVLANMembers(ctx, id, VLANMembership{}, ports)
+}
+
+// setVLANMembers adds the ports of m to the VLAN and, if exact, removes
+// the members not in m first.
+func (d *Device) setVLANMembers(ctx context.Context, id int, m VLANMembership, exact bool) error {
+	if err := checkVLAN(id); err != nil {
+		return err
+	}
+	m = VLANMembership{Tagged: normalizePorts(m.Tagged), Untagged: normalizePorts(m.Untagged)}
+	for _, p := range m.Tagged {
+		if slices.Contains(m.Untagged, p) {
+			return fmt.Errorf("vlan %d: port %s both tagged and untagged", id, p)
+		}
+	}
+	cur, err := d.vlan(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cmds   []string
+		remove []parser.PortID
+	)
+	if exact {
+		for _, p := range cur.Ports {
+			if !slices.Contains(m.Tagged, p) && !slices.Contains(m.Untagged, p) {
+				remove = append(remove, p)
+			}
+		}
+		if len(remove) > 0 {
+			cmds, err = interfaceCommands(remove, "no switchport general allowed vlan "+strconv.Itoa(id))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, g := range []struct {
+		ports  []parser.PortID
+		egress string
+	}{{m.Tagged, "tagged"}, {m.Untagged, "untagged"}} {
+		if len(g.ports) == 0 {
+			continue
+		}
+		add, err := interfaceCommands(g.ports, fmt.Sprintf("switchport general allowed vlan %d %s", id, g.egress))
+		if err != nil {
+			return err
+		}
+		cmds = append(cmds, add...)
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+	if err := d.Configure(ctx, cmds...); err != nil {
+		return err
+	}
+	return d.verifyVLANMembers(ctx, id, m, remove)
+}
+
+// verifyVLANMembers checks that the ports of m are members of the VLAN
+// with their egress rule and that the absent ports are not. If the VLAN
+// table does not mark egress rules, only membership is checked.
+func (d *Device) verifyVLANMembers(ctx context.Context, id int, m VLANMembership, absent []parser.PortID) error {
+	v, err := d.vlan(ctx, id)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	setting := fmt.Sprintf("vlan %d membership", id)
+	marked := len(v.Tagged)+len(v.Untagged) > 0
+	egressOf := func(p parser.PortID) string {
+		switch {
+		case slices.Contains(v.Tagged, p):
+			return "tagged"
+		case slices.Contains(v.Untagged, p):
+			return "untagged"
+		case slices.Contains(v.Ports, p):
+			return "member"
+		}
+		return "none"
+	}
+	for _, g := range []struct {
+		ports  []parser.PortID
+		egress string
+	}{{m.Tagged, "tagged"}, {m.Untagged, "untagged"}} {
+		for _, p := range g.ports {
+			got := egressOf(p)
+			if got == "none" || marked && got != g.egress {
+				return &VerifyError{Port: p, Setting: setting, Want: g.egress, Got: got}
+			}
+		}
+	}
+	for _, p := range absent {
+		if got := egressOf(p); got != "none" {
+			return &VerifyError{Port: p, Setting: setting, Want: "none", Got: got}
+		}
+	}
+	return nil
+}
+
+// SetPVID sets the port VLAN ID, the VLAN untagged frames received on
+// ports are assigned to, and verifies it.
+func (d *Device) SetPVID(ctx context.Context, pvid int, ports ...parser.PortID) error {
+	if err := checkVLAN(pvid); err != nil {
+		return err
+	}
+	ports = normalizePorts(ports)
+	if len(ports) == 0 {
+		return nil
+	}
+	if err := d.configureInterfaces(ctx, ports, "switchport pvid "+strconv.Itoa(pvid)); err != nil {
+		return err
+	}
+	sw, err := d.Switchports(ctx)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	for _, port := range ports {
+		i := slices.IndexFunc(sw, func(s parser.Switchport) bool { return s.Port == port })
+		if i < 0 {
+			return fmt.Errorf("verify: port %s not in switchport configuration", port)
+		}
+		if sw[i].PVID != pvid {
+			return &VerifyError{Port: port, Setting: "pvid", Want: strconv.Itoa(pvid), Got: strconv.Itoa(sw[i].PVID)}
+		}
+	}
+	return nil
+}
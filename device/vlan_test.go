@@ -0,0 +1,86 @@
+package device_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pascal71/tplink-go/device"
+	"github.com/pascal71/tplink-go/parser"
+)
+
+func TestSetVLANMembersReplay(t *testing.T) {
+	after := `VLAN  Name         Status   Ports
+----  -----------  -------  -----------------------------------
+1     System-VLAN  active   Gi1/0/1-8
+10    users        active   Gi1/0/1-3(u),Gi1/0/5(u),Gi1/0/24(t)`
+	d, tr := replayDevice(t,
+		reply("show vlan", vlanTable("users"), privPrompt),
+		reply("show vlan", after, privPrompt),
+		reply("configure", "", configPrompt),
+		reply("interface range gigabitEthernet 1/0/9-10", "", rangePrompt),
+		reply("no switchport general allowed vlan 10", "", rangePrompt),
+		reply("interface gigabitEthernet 1/0/24", "", ifPrompt),
+		reply("switchport general allowed vlan 10 tagged", "", ifPrompt),
+		reply("interface range gigabitEthernet 1/0/1-3,1/0/5", "", rangePrompt),
+		reply("switchport general allowed vlan 10 untagged", "", rangePrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+	)
+
+	err := d.SetVLANMembers(context.Background(), 10, device.VLANMembership{
+		Tagged:   []parser.PortID{"Gi1/0/24"},
+		Untagged: []parser.PortID{"gi1/0/5", "Gi1/0/3", "Gi1/0/1", "Gi1/0/2"},
+	})
+	if err != nil {
+		t.Fatalf("SetVLANMembers: %v", err)
+	}
+	wantSent(t, tr, "show vlan", "configure",
+		"interface range gigabitEthernet 1/0/9-10", "no switchport general allowed vlan 10", "exit",
+		"interface gigabitEthernet 1/0/24", "switchport general allowed vlan 10 tagged", "exit",
+		"interface range gigabitEthernet 1/0/1-3,1/0/5", "switchport general allowed vlan 10 untagged", "exit",
+		"end", "show vlan")
+}
+
+func TestRemoveVLANMembersReplay(t *testing.T) {
+	d, tr := replayDevice(t,
+		reply("configure", "", configPrompt),
+		reply("interface range gigabitEthernet 1/0/9-10", "", rangePrompt),
+		reply("no switchport general allowed vlan 10", "", rangePrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+		reply("show vlan", vlanTable("users"), privPrompt),
+	)
+
+	// The VLAN table still lists both ports.
+	err := d.RemoveVLANMembers(context.Background(), 10, "Gi1/0/10", "Gi1/0/9")
+	var ve *device.VerifyError
+	if !errors.As(err, &ve) {
+		t.Fatalf("RemoveVLANMembers error = %v, want *VerifyError", err)
+	}
+	if ve.Port != "Gi1/0/9" || ve.Want != "none" || ve.Got != "untagged" {
+		t.Errorf("VerifyError = %+v, want Gi1/0/9 still an untagged member", ve)
+	}
+	wantSent(t, tr, "configure", "interface range gigabitEthernet 1/0/9-10", "no switchport general allowed vlan 10", "exit",
+		"end", "show vlan")
+}
+
+func TestSetPVIDReplay(t *testing.T) {
+	blocks := switchportBlock("Gi1/0/1", "General", 20, "20", "Untagged") + "\n" +
+		switchportBlock("Gi1/0/2", "General", 20, "20", "Untagged") + "\n" +
+		switchportBlock("Gi1/0/3", "General", 20, "20", "Untagged")
+	d, tr := replayDevice(t,
+		reply("configure", "", configPrompt),
+		reply("interface range gigabitEthernet 1/0/1-3", "", rangePrompt),
+		reply("switchport pvid 20", "", rangePrompt),
+		reply("exit", "", configPrompt),
+		reply("end", "", privPrompt),
+		reply("show interface switchport", blocks, privPrompt),
+	)
+
+	if err := d.SetPVID(context.Background(), 20, "Gi1/0/3", "Gi1/0/1", "Gi1/0/2", "gi1/0/1"); err != nil {
+		t.Fatalf("SetPVID: %v", err)
+	}
+	wantSent(t, tr, "configure", "interface range gigabitEthernet 1/0/1-3", "switchport pvid 20", "exit", "end",
+		"show interface switchport")
+}
@@ -2,6 +2,9 @@ module github.com/pascal71/tplink-go
 
 go 1.24.3
 
-require golang.org/x/crypto v0.38.0
+require (
+	golang.org/x/crypto v0.38.0
+	golang.org/x/net v0.40.0
+)
 
-require golang.org/x/sys v0.33.0 // indirect
+require golang.org/x/sys v0.33.0
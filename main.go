@@ -13,23 +13,19 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pascal71/tplink-go/parser"
 	"golang.org/x/crypto/ssh"
 )
 
 var (
 	promptRegex = regexp.MustCompile(`(?m)[\r\n]*(SG2210XMP-M2(-N\d+)?(\([^)]+\))?[>#])\s*$`)
 	ansiEscape  = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
-)
 
-type PoEPort struct {
-	PowerWatts float64 `json:"power_watts"`
-	CurrentMA  int     `json:"current_ma"`
-	VoltageV   float64 `json:"voltage_v"`
-	PDClass    string  `json:"pd_class"`
-	Status     string  `json:"status"`
-}
+	// redactor hides the credentials in logged switch output.
+	redactor = strings.NewReplacer()
+)
 
-func waitForPrompt(stdin io.Writer, stdout io.Reader, password string, fullOutput *bytes.Buffer) {
+func waitForPrompt(stdin io.Writer, stdout io.Reader, enablePassword string, fullOutput *bytes.Buffer) {
 	ctx := context.Background()
 	buffer := make([]byte, 4096)
 	tmp := make([]byte, 0)
@@ -41,7 +37,7 @@ func waitForPrompt(stdin io.Writer, stdout io.Reader, password string, fullOutpu
 			slog.WarnContext(ctx, "Prompt wait timed out")
 			partial := ansiEscape.ReplaceAllString(string(tmp), "")
 			partial = strings.ReplaceAll(partial, "\r", "")
-			slog.WarnContext(ctx, "Partial data received before timeout", "output", strings.TrimSpace(partial))
+			slog.WarnContext(ctx, "Partial data received before timeout", "output", redactor.Replace(strings.TrimSpace(partial)))
 			return
 		default:
 			n, err := stdout.Read(buffer)
@@ -51,16 +47,16 @@ func waitForPrompt(stdin io.Writer, stdout io.Reader, password string, fullOutpu
 			}
 			if n > 0 {
 				raw := buffer[:n]
-				slog.DebugContext(ctx, "Received raw", "raw", string(raw))
+				slog.DebugContext(ctx, "Received raw", "raw", redactor.Replace(string(raw)))
 				fullOutput.Write(raw)
 				tmp = append(tmp, raw...)
 
 				cleaned := ansiEscape.ReplaceAll(tmp, []byte(""))
-				slog.DebugContext(ctx, "Cleaned so far", "text", string(cleaned))
+				slog.DebugContext(ctx, "Cleaned so far", "text", redactor.Replace(string(cleaned)))
 
 				if bytes.Contains(cleaned, []byte("Password:")) {
 					slog.InfoContext(ctx, "Enable password prompt detected, sending password")
-					fmt.Fprintln(stdin, password)
+					fmt.Fprintln(stdin, enablePassword)
 					tmp = []byte{}
 					continue
 				}
@@ -77,6 +73,11 @@ func main() {
 	switchIP := "10.8.62.221:22"
 	username := "admin"
 	password := "Niilas12"
+	enablePassword := os.Getenv("TPLINK_ENABLE_PASS")
+	if enablePassword == "" {
+		enablePassword = password
+	}
+	redactor = strings.NewReplacer(password, "[REDACTED]", enablePassword, "[REDACTED]")
 
 	logFile, err := os.OpenFile("tplink_cli.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
 	if err != nil {
@@ -142,12 +143,12 @@ func main() {
 	slog.DebugContext(ctx, "Shell started")
 
 	var fullOutput bytes.Buffer
-	waitForPrompt(stdin, stdout, password, &fullOutput)
+	waitForPrompt(stdin, stdout, enablePassword, &fullOutput)
 
 	send := func(cmd string) {
 		slog.Info("Sending command", "command", cmd)
 		fmt.Fprint(stdin, cmd+"\r\n")
-		waitForPrompt(stdin, stdout, password, &fullOutput)
+		waitForPrompt(stdin, stdout, enablePassword, &fullOutput)
 	}
 
 	send("enable")
@@ -165,38 +166,9 @@ func main() {
 	fmt.Println("=== TP-Link Switch Output ===")
 	fmt.Println(strings.TrimSpace(cleaned))
 
-	lines := strings.Split(cleaned, "\n")
-	poeTable := make(map[string]PoEPort)
-
-	for _, line := range lines {
-		if strings.HasPrefix(line, "Tw") {
-			fields := strings.Fields(line)
-
-			if len(fields) < 6 {
-				continue
-			}
-
-			iface := fields[0]
-			var power float64
-			var current int
-			var voltage float64
-
-			fmt.Sscanf(fields[1], "%f", &power)
-			fmt.Sscanf(fields[2], "%d", &current)
-			fmt.Sscanf(fields[3], "%f", &voltage)
-
-			pdClassFields := fields[4 : len(fields)-1]
-			status := fields[len(fields)-1]
-			pdClass := strings.Join(pdClassFields, " ")
-
-			poeTable[iface] = PoEPort{
-				PowerWatts: power,
-				CurrentMA:  current,
-				VoltageV:   voltage,
-				PDClass:    pdClass,
-				Status:     status,
-			}
-		}
+	poeTable, err := parser.ParsePoETable(cleaned)
+	if err != nil {
+		log.Fatalf("Failed to parse PoE table: %v", err)
 	}
 
 	fmt.Println("\n=== Parsed JSON Output ===")
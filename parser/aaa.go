@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"strings"
+)
+
+// AAAServer is a RADIUS or TACACS+ server.
+type AAAServer struct {
+	Protocol       string `json:"protocol"` // "radius" or "tacacs+"
+	Host           string `json:"host"`
+	AuthPort       int    `json:"auth_port,omitempty"` // the TACACS+ port for TACACS+ servers
+	AcctPort       int    `json:"acct_port,omitempty"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+	Retransmit     int    `json:"retransmit,omitempty"`
+	Priority       int    `json:"priority,omitempty"`
+	KeyConfigured  bool   `json:"key_configured"`
+}
+
+// ParseAAAServers parses "show radius-server" and "show tacacs-server"
+// tables, alone or concatenated, and the "Server address:" blocks printed
+// by some firmware. Shared keys are never returned, only whether one is set.
+func ParseAAAServers(output string, opts ...Option) ([]AAAServer, error) {
+	o := newOptions(opts)
+	var servers []AAAServer
+	for _, t := range Tables(output) {
+		host := t.Col("server ip", "server-ip", "server", "host", "ip address", "server address")
+		if host < 0 {
+			continue
+		}
+		auth := t.Col("auth port", "auth-port", "port", "server port")
+		acct := t.Col("acct port", "acct-port", "accounting port")
+		timeout := t.Col("timeout", "timeout(s)", "timeout(sec)")
+		retrans := t.Col("retransmit", "retransmit count", "retries", "retry")
+		prio := t.Col("priority", "index", "id")
+		key := t.Col("shared key", "key", "shared-key", "secret")
+		protocol := "tacacs+"
+		if acct >= 0 {
+			protocol = "radius"
+		}
+		for r, row := range t.Rows {
+			if value(row, host) == "" {
+				continue
+			}
+			rr := t.reader(r)
+			s := AAAServer{
+				Protocol:       protocol,
+				Host:           value(row, host),
+				AuthPort:       rr.int(auth),
+				AcctPort:       rr.int(acct),
+				TimeoutSeconds: rr.int(timeout),
+				Retransmit:     rr.int(retrans),
+				Priority:       rr.int(prio),
+				KeyConfigured:  keySet(value(row, key)),
+			}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			servers = append(servers, s)
+		}
+	}
+	if len(servers) > 0 {
+		return servers, nil
+	}
+
+	protocol := "radius"
+	var cur *AAAServer
+	for i, line := range splitLines(output) {
+		lower := strings.ToLower(line)
+		if strings.Contains(lower, "tacacs") {
+			protocol = "tacacs+"
+		} else if strings.Contains(lower, "radius") {
+			protocol = "radius"
+		}
+		key, val, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		val = strings.TrimSpace(val)
+		key = strings.ToLower(strings.TrimSpace(key))
+		var num *int // field of cur taking a number
+		switch key {
+		case "server address", "server ip", "server host", "host":
+			servers = append(servers, AAAServer{Protocol: protocol, Host: val})
+			cur = &servers[len(servers)-1]
+		case "key", "shared key", "server key":
+			if cur != nil {
+				cur.KeyConfigured = keySet(val)
+			}
+		case "server port", "port", "auth port", "auth-port":
+			if cur != nil {
+				num = &cur.AuthPort
+			}
+		case "acct port", "acct-port":
+			if cur != nil {
+				num = &cur.AcctPort
+			}
+		case "timeout", "server timeout":
+			if cur != nil {
+				num = &cur.TimeoutSeconds
+			}
+		case "retransmit", "retransmit count", "retries":
+			if cur != nil {
+				num = &cur.Retransmit
+			}
+		case "priority":
+			if cur != nil {
+				num = &cur.Priority
+			}
+		}
+		if num == nil {
+			continue
+		}
+		n, err := parseInt(val)
+		if err != nil {
+			if err := o.malformed(lineError(i, line, key, err)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		*num = n
+	}
+	return servers, nil
+}
+
+// keySet reports whether a shared key column shows a key, which switches
+// print masked, as "Configured", or as a placeholder when there is none.
+func keySet(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "-", "none", "no", "not configured", "not set", "n/a":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,155 @@
+package parser
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ACL is an access control list with its rules and where it is bound.
+type ACL struct {
+	ID       int          `json:"id"`
+	Name     string       `json:"name,omitempty"`
+	Type     string       `json:"type"` // e.g. "MAC", "IP", "Combined", "IPv6"
+	Rules    []ACLRule    `json:"rules"`
+	Bindings []ACLBinding `json:"bindings,omitempty"`
+}
+
+// ACLRule is one rule of an ACL.
+type ACLRule struct {
+	ID       int               `json:"id"`
+	Action   string            `json:"action"` // "permit" or "deny"
+	SrcMAC   string            `json:"src_mac,omitempty"`
+	DstMAC   string            `json:"dst_mac,omitempty"`
+	SrcIP    string            `json:"src_ip,omitempty"`
+	SrcMask  string            `json:"src_mask,omitempty"` // IP or MAC mask
+	DstIP    string            `json:"dst_ip,omitempty"`
+	DstMask  string            `json:"dst_mask,omitempty"` // IP or MAC mask
+	Protocol string            `json:"protocol,omitempty"`
+	SrcPort  string            `json:"src_port,omitempty"`
+	DstPort  string            `json:"dst_port,omitempty"`
+	VLAN     int               `json:"vlan,omitempty"`
+	Match    map[string]string `json:"match"` // every "keyword value" pair of the rule
+	Text     string            `json:"text"`  // the rule as printed
+}
+
+// ACLBinding is an interface or VLAN an ACL is applied to.
+type ACLBinding struct {
+	Target    string `json:"target"` // port or VLAN
+	Direction string `json:"direction,omitempty"`
+	Type      string `json:"type,omitempty"` // "Port" or "VLAN"
+}
+
+// aclHeadRegex matches an ACL heading such as
+// `IP access list 500 name: "ACL_500"`.
+var aclHeadRegex = regexp.MustCompile(`(?i)^(\w+)?\s*access[- ]list\s+(\d+)(?:\s+name\s*:?\s*"?([^"]*)"?)?`)
+
+// ParseACLs parses "show access-list": each ACL heading followed by its
+// "rule N permit|deny ..." lines. A binding table with ACL ID and
+// interface/VLAN columns ("show access-list bind") adds bindings.
+func ParseACLs(output string, opts ...Option) ([]ACL, error) {
+	o := newOptions(opts)
+	var acls []ACL
+	index := make(map[int]int)
+	for n, line := range splitLines(output) {
+		trimmed := strings.TrimSpace(line)
+		if m := aclHeadRegex.FindStringSubmatch(trimmed); m != nil {
+			id, _ := strconv.Atoi(m[2])
+			index[id] = len(acls)
+			acls = append(acls, ACL{ID: id, Type: m[1], Name: strings.TrimSpace(m[3])})
+			continue
+		}
+		if len(acls) == 0 || !strings.HasPrefix(trimmed, "rule ") {
+			continue
+		}
+		r, err := parseACLRule(trimmed)
+		if err != nil {
+			pe := err.(*ParseError)
+			pe.Line, pe.Text = n+1, line
+			if err := o.malformed(pe); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		acl := &acls[len(acls)-1]
+		acl.Rules = append(acl.Rules, r)
+	}
+
+	for _, t := range Tables(output) {
+		idc := t.Col("acl id", "acl", "id", "acl-id")
+		target := t.Col("interface/vlan", "interface", "port", "vlan", "target", "bind object")
+		if idc < 0 || target < 0 {
+			continue
+		}
+		name := t.Col("acl name", "name")
+		dir := t.Col("direction", "dir")
+		typ := t.Col("type", "bind type")
+		for r, row := range t.Rows {
+			if value(row, idc) == "" {
+				continue
+			}
+			id, err := strconv.Atoi(value(row, idc))
+			if err != nil {
+				if err := o.malformed(t.rowError(r, t.Headers[idc], err)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			i, ok := index[id]
+			if !ok {
+				index[id] = len(acls)
+				i = len(acls)
+				acls = append(acls, ACL{ID: id, Name: value(row, name)})
+			}
+			acls[i].Bindings = append(acls[i].Bindings, ACLBinding{
+				Target:    value(row, target),
+				Direction: value(row, dir),
+				Type:      value(row, typ),
+			})
+		}
+	}
+	return acls, nil
+}
+
+// parseACLRule parses a "rule 5 permit smac ... dip ..." line.
+func parseACLRule(line string) (ACLRule, error) {
+	f := strings.Fields(line)
+	if len(f) < 3 {
+		return ACLRule{}, &ParseError{Text: line, Err: errors.New("rule has no action")}
+	}
+	id, err := strconv.Atoi(f[1])
+	if err != nil {
+		return ACLRule{}, &ParseError{Text: line, Field: "rule id", Err: err}
+	}
+	r := ACLRule{ID: id, Action: strings.ToLower(f[2]), Match: make(map[string]string), Text: line}
+	for i := 3; i+1 < len(f); i += 2 {
+		key, val := strings.ToLower(f[i]), f[i+1]
+		r.Match[key] = val
+		switch key {
+		case "smac", "src-mac":
+			r.SrcMAC = val
+		case "dmac", "dst-mac":
+			r.DstMAC = val
+		case "sip", "src-ip":
+			r.SrcIP = val
+		case "sip-mask", "smask", "src-mask":
+			r.SrcMask = val
+		case "dip", "dst-ip":
+			r.DstIP = val
+		case "dip-mask", "dmask", "dst-mask":
+			r.DstMask = val
+		case "protocol":
+			r.Protocol = val
+		case "s-port", "sport", "src-port":
+			r.SrcPort = val
+		case "d-port", "dport", "dst-port":
+			r.DstPort = val
+		case "vid", "vlan":
+			if r.VLAN, err = strconv.Atoi(val); err != nil {
+				return ACLRule{}, &ParseError{Text: line, Field: key, Err: err}
+			}
+		}
+	}
+	return r, nil
+}
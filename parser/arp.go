@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ARPEntry is one entry of the switch's ARP table.
+type ARPEntry struct {
+	IP        string `json:"ip" table:"ip address,ip,address,internet address,required"`
+	MAC       string `json:"mac" table:"mac address,mac,hardware address,hardware addr,required"`
+	Interface string `json:"interface" table:"interface,vlan,port"`
+	VLAN      int    `json:"vlan,omitempty"`                                          // from a VLAN interface name, 0 otherwise
+	Age       string `json:"age,omitempty" table:"age,age(min),age (min),aging time"` // as printed by the switch
+	Type      string `json:"type" table:"type,status"`                                // e.g. "Dynamic", "Static"
+}
+
+// ParseARPTable parses the "show arp" table. A row whose address is not
+// an IP address is malformed.
+func ParseARPTable(output string, opts ...Option) ([]ARPEntry, error) {
+	var entries []ARPEntry
+	for _, t := range Tables(output) {
+		if err := t.Decode(&entries, opts...); err != nil {
+			return nil, err
+		}
+	}
+	for i := range entries {
+		entries[i].VLAN = vlanOf(entries[i].Interface)
+	}
+	return entries, nil
+}
+
+func (e *ARPEntry) validate() error {
+	return checkIP(e.IP)
+}
+
+// vlanOf returns the VLAN ID of an interface name such as "VLAN10",
+// "vlan 10", "Vl10" or "10", or 0.
+func vlanOf(iface string) int {
+	s := strings.TrimSpace(iface)
+	if len(s) >= 4 && strings.EqualFold(s[:4], "vlan") {
+		s = strings.TrimSpace(s[4:])
+	} else if len(s) >= 2 && strings.EqualFold(s[:2], "vl") {
+		s = s[2:]
+	}
+	id, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,70 @@
+package parser
+
+// builtins are the parsers of this package by command.
+var builtins = []struct {
+	commands []string
+	parse    Func
+}{
+	{[]string{"show system-info"}, FuncOf(ParseSystemInfo)},
+	{[]string{"show running-config", "show startup-config"}, FuncOf(ParseRunningConfig)},
+	{[]string{"show interface counters"}, FuncOf(ParseInterfaceCounters)},
+	{[]string{"show interface description"}, FuncOf(ParseInterfaceDescriptions)},
+	{[]string{"show interface status"}, FuncOf(ParseInterfaceStatus)},
+	{[]string{"show interface configuration"}, FuncOf(ParseInterfaceConfig)},
+	{[]string{"show interface switchport"}, FuncOf(ParseSwitchport)},
+	{[]string{"show power inline information interface"}, FuncOf(ParsePoETable)},
+	{[]string{"show power inline", "show power inline information"}, FuncOf(ParsePoESystemInfo)},
+	{[]string{"show power inline configuration interface"}, FuncOf(ParsePoEConfig)},
+	{[]string{"show vlan"}, FuncOf(ParseVLANs)},
+	{[]string{"show lldp neighbor-information"}, FuncOf(ParseLLDPNeighbors)},
+	{[]string{"show spanning-tree"}, FuncOf(ParseSpanningTree)},
+	{[]string{"show arp", "show ip arp"}, FuncOf(ParseARPTable)},
+	{[]string{"show ipv6 neighbors"}, FuncOf(ParseIPv6Neighbors)},
+	{[]string{"show ip interface"}, FuncOf(ParseIPInterfaces)},
+	{[]string{"show ip route"}, FuncOf(ParseIPRoutes)},
+	{[]string{"show etherchannel", "show lacp"}, FuncOf(ParseLAGs)},
+	{[]string{"show logging buffer"}, FuncOf(ParseLogBuffer)},
+	{[]string{"show ddm"}, FuncOf(ParseSFPDiagnostics)},
+	{[]string{"show temperature"}, FuncOf(ParseTemperature)},
+	{[]string{"show fan"}, FuncOf(ParseFans)},
+	{[]string{"show power supply"}, FuncOf(ParsePowerSupplies)},
+	{[]string{"show stack", "show stack-port"}, FuncOf(ParseStackInfo)},
+	{[]string{"show users", "show user"}, FuncOf(ParseUserSessions)},
+	{[]string{"show snmp-server"}, FuncOf(ParseSNMPConfig)},
+	{[]string{"show dot1x"}, FuncOf(ParseDot1x)},
+	{[]string{"show ip dhcp snooping binding", "show ip source binding"}, FuncOf(ParseDHCPSnoopingBindings)},
+	{[]string{"show ip igmp snooping groups"}, FuncOf(ParseIGMPGroups)},
+	{[]string{"show monitor session"}, FuncOf(ParseMirrorSessions)},
+	{[]string{"show storm-control"}, FuncOf(ParseStormControl)},
+	{[]string{"show qos"}, FuncOf(ParseQoS)},
+	{[]string{"show access-list"}, FuncOf(ParseACLs)},
+	{[]string{"show loopback-detection"}, FuncOf(ParseLoopbackDetection)},
+	{[]string{"show cable-diagnostics"}, FuncOf(ParseCableDiagnostics)},
+	{[]string{"show system-time", "show sntp"}, FuncOf(ParseSystemTime)},
+	{[]string{"show port-security", "show mac address-table max-mac-count"}, FuncOf(ParsePortSecurity)},
+	{[]string{"show radius-server", "show tacacs-server", "show tacacs"}, FuncOf(ParseAAAServers)},
+	{[]string{"show jumbo-frame", "show system mtu"}, FuncOf(ParseJumboFrame)},
+	{[]string{"show voice vlan"}, FuncOf(ParseVoiceVLAN)},
+	{[]string{"show eee", "show green-ethernet"}, FuncOf(ParseEEE)},
+	{[]string{"show port isolation"}, FuncOf(ParsePortIsolation)},
+	{[]string{"show mac address-table multicast", "show mvr members"}, FuncOf(ParseMulticastTable)},
+	{[]string{"show errdisable"}, FuncOf(ParseErrDisable)},
+	{[]string{"show cpu-utilization"}, FuncOf(ParseCPUUtilization)},
+	{[]string{"show cpu-utilization history", "show memory-utilization history"}, FuncOf(ParseUtilizationHistory)},
+	{[]string{"show ip dhcp relay", "show ip helper-address"}, FuncOf(ParseDHCPRelay)},
+	{[]string{"show ip dhcp server"}, FuncOf(ParseDHCPServer)},
+	{[]string{"show gvrp"}, FuncOf(ParseGVRP)},
+	{[]string{"show mvr"}, FuncOf(ParseMVR)},
+	{[]string{"show mac address-table"}, FuncOf(ParseMACTable)},
+	{[]string{"show mac address-table count", "show mac address-table aging-time"}, FuncOf(ParseMACTableCount)},
+	{[]string{"show rmon statistics"}, FuncOf(ParseRMONStatistics)},
+	{[]string{"show rmon history"}, FuncOf(ParseRMONHistory)},
+}
+
+func init() {
+	for _, b := range builtins {
+		for _, cmd := range b.commands {
+			Register(Entry{Command: cmd, Parse: b.parse})
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"strings"
+)
+
+// CableTest is the virtual cable test result of one port.
+type CableTest struct {
+	Port  PortID      `json:"port"`
+	Pairs []CablePair `json:"pairs"`
+}
+
+// OK reports whether every tested pair is normal.
+func (c CableTest) OK() bool {
+	for _, p := range c.Pairs {
+		if !p.OK() {
+			return false
+		}
+	}
+	return len(c.Pairs) > 0
+}
+
+// CablePair is the result for one twisted pair.
+type CablePair struct {
+	Pair         string  `json:"pair"`   // e.g. "Pair A"
+	Status       string  `json:"status"` // e.g. "Normal", "Open", "Short", "Crosstalk"
+	LengthMeters float64 `json:"length_meters"`
+	LengthValid  bool    `json:"length_valid"` // false when no length was reported
+	Error        string  `json:"error,omitempty"`
+}
+
+// OK reports whether the pair tested normal.
+func (p CablePair) OK() bool {
+	s := strings.ToLower(p.Status)
+	return s == "normal" || s == "ok" || s == "good"
+}
+
+// ParseCableDiagnostics parses "show cable-diagnostics interface": one row
+// per pair, with the port given on the first row of each port. For a fault,
+// the length is the distance to the fault.
+func ParseCableDiagnostics(output string, opts ...Option) (map[PortID]CableTest, error) {
+	o := newOptions(opts)
+	tests := make(map[PortID]CableTest)
+	t, ok := FindTable(output, "port")
+	if !ok {
+		t, ok = FindTable(output, "interface")
+	}
+	if !ok {
+		return tests, nil
+	}
+	pair := t.Col("pair", "pairs")
+	status := t.Col("status", "result", "state")
+	length := t.Col("length", "length(m)", "cable length", "distance", "fault distance")
+	errc := t.Col("error", "deviation", "fault")
+	port := ""
+	for r, row := range t.Rows {
+		if p := value(row, 0); p != "" {
+			if !isPortName(p) {
+				if err := o.malformed(t.rowError(r, t.Headers[0], errNotPort)); err != nil {
+					return nil, err
+				}
+				port = ""
+				continue
+			}
+			port = p
+		}
+		if port == "" || value(row, pair) == "" {
+			continue
+		}
+		cp := CablePair{Pair: value(row, pair), Status: value(row, status), Error: value(row, errc)}
+		if cp.Error == "-" {
+			cp.Error = ""
+		}
+		rr := t.reader(r)
+		cp.LengthMeters = rr.float(length)
+		if rr.err != nil {
+			if err := o.malformed(rr.err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		_, cp.LengthValid = parseNumber(value(row, length))
+		id := portID(port)
+		c := tests[id]
+		c.Port = id
+		c.Pairs = append(c.Pairs, cp)
+		tests[id] = c
+	}
+	return tests, nil
+}
@@ -0,0 +1,173 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Config is a running or startup configuration split into global settings
+// and blocks such as "interface gigabitEthernet 1/0/1" or "vlan 10".
+type Config struct {
+	Header   []string        `json:"header,omitempty"` // leading "!" comment lines, e.g. the model name
+	Global   []string        `json:"global"`           // top-level settings outside any block
+	Sections []ConfigSection `json:"sections"`
+}
+
+// ConfigSection is a configuration block and its indented settings.
+type ConfigSection struct {
+	Header string   `json:"header"` // e.g. "interface gigabitEthernet 1/0/1"
+	Kind   string   `json:"kind"`   // first word of Header, e.g. "interface"
+	Name   string   `json:"name"`   // rest of Header, e.g. "gigabitEthernet 1/0/1"
+	Lines  []string `json:"lines"`  // settings, trimmed
+}
+
+// ParseRunningConfig parses "show running-config" (or startup-config).
+// Top-level lines followed by indented lines open a section; other
+// top-level lines are global settings. "#" and "!" separators and the
+// trailing "end" are dropped.
+func ParseRunningConfig(output string, opts ...Option) (Config, error) {
+	var (
+		cfg     Config
+		pending string // last top-level line, which may open a section
+		cur     *ConfigSection
+		seen    bool // a setting has been seen, so "!" lines are no longer header
+	)
+	flush := func() {
+		if pending != "" {
+			cfg.Global = append(cfg.Global, pending)
+			pending = ""
+		}
+	}
+	for _, line := range splitLines(output) {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || trimmed == "#" || trimmed == "!":
+			flush()
+			cur = nil
+			continue
+		case strings.HasPrefix(trimmed, "!") || strings.HasPrefix(trimmed, "#"):
+			if !seen {
+				cfg.Header = append(cfg.Header, strings.TrimSpace(trimmed[1:]))
+			}
+			continue
+		case trimmed == "end":
+			flush()
+			cur = nil
+			continue
+		}
+		seen = true
+		if line[0] == ' ' || line[0] == '\t' {
+			if cur == nil && pending != "" {
+				kind, name, _ := strings.Cut(pending, " ")
+				cfg.Sections = append(cfg.Sections, ConfigSection{Header: pending, Kind: kind, Name: strings.TrimSpace(name)})
+				cur = &cfg.Sections[len(cfg.Sections)-1]
+				pending = ""
+			}
+			if cur != nil {
+				cur.Lines = append(cur.Lines, trimmed)
+			} else {
+				cfg.Global = append(cfg.Global, trimmed)
+			}
+			continue
+		}
+		flush()
+		cur = nil
+		pending = trimmed
+	}
+	flush()
+	return cfg, nil
+}
+
+// Section returns the section with the given header.
+func (c Config) Section(header string) (ConfigSection, bool) {
+	for _, s := range c.Sections {
+		if s.Header == header {
+			return s, true
+		}
+	}
+	return ConfigSection{}, false
+}
+
+// SectionsOf returns the sections of one kind, e.g. "interface".
+func (c Config) SectionsOf(kind string) []ConfigSection {
+	var out []ConfigSection
+	for _, s := range c.Sections {
+		if s.Kind == kind {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Interfaces returns the interface sections.
+func (c Config) Interfaces() []ConfigSection {
+	return c.SectionsOf("interface")
+}
+
+// VLANs returns the VLAN sections by VLAN ID. A "vlan 10-12" section
+// applies to each VLAN in the range.
+func (c Config) VLANs() map[int]ConfigSection {
+	vlans := make(map[int]ConfigSection)
+	for _, s := range c.SectionsOf("vlan") {
+		ids, err := parseVLANList(s.Name)
+		if err != nil {
+			continue
+		}
+		for _, id := range ids {
+			vlans[id] = s
+		}
+	}
+	return vlans
+}
+
+// GlobalLines returns the global settings starting with prefix, e.g.
+// "user name" or "snmp-server".
+func (c Config) GlobalLines(prefix string) []string {
+	var out []string
+	for _, l := range c.Global {
+		if strings.HasPrefix(l, prefix) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// Users returns the "user name ..." account lines.
+func (c Config) Users() []string {
+	return c.GlobalLines("user name ")
+}
+
+// SNMP returns the "snmp-server ..." lines.
+func (c Config) SNMP() []string {
+	return c.GlobalLines("snmp-server")
+}
+
+// Hostname returns the configured host name, unquoted.
+func (c Config) Hostname() string {
+	for _, l := range c.GlobalLines("hostname ") {
+		return unquote(strings.TrimSpace(strings.TrimPrefix(l, "hostname ")))
+	}
+	return ""
+}
+
+// Setting returns the argument of the first line of s starting with key,
+// unquoted, e.g. Setting("description") for ` description "uplink"`.
+func (s ConfigSection) Setting(key string) (string, bool) {
+	for _, l := range s.Lines {
+		if l == key {
+			return "", true
+		}
+		if rest, ok := strings.CutPrefix(l, key+" "); ok {
+			return unquote(strings.TrimSpace(rest)), true
+		}
+	}
+	return "", false
+}
+
+// unquote removes the double quotes the CLI puts around string settings.
+func unquote(s string) string {
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+	return s
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfigDiff is the difference between two configurations, by section.
+type ConfigDiff struct {
+	Sections []SectionDiff `json:"sections"`
+}
+
+// SectionDiff lists the changes within one section.
+type SectionDiff struct {
+	Section string       `json:"section"` // section header, "" for global settings
+	Status  string       `json:"status"`  // "added", "removed" or "modified"
+	Added   []string     `json:"added,omitempty"`
+	Removed []string     `json:"removed,omitempty"`
+	Changed []LineChange `json:"changed,omitempty"`
+}
+
+// LineChange is a setting whose value changed, e.g. a new description.
+type LineChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// Empty reports whether the configurations are equivalent.
+func (d ConfigDiff) Empty() bool {
+	return len(d.Sections) == 0
+}
+
+// String formats the diff with "+", "-" and "~" markers under each section
+// header, for alert messages.
+func (d ConfigDiff) String() string {
+	var b strings.Builder
+	for _, s := range d.Sections {
+		name := s.Section
+		if name == "" {
+			name = "global"
+		}
+		fmt.Fprintf(&b, "[%s] %s\n", name, s.Status)
+		for _, l := range s.Removed {
+			fmt.Fprintf(&b, "- %s\n", l)
+		}
+		for _, l := range s.Added {
+			fmt.Fprintf(&b, "+ %s\n", l)
+		}
+		for _, c := range s.Changed {
+			fmt.Fprintf(&b, "~ %s -> %s\n", c.Old, c.New)
+		}
+	}
+	return b.String()
+}
+
+// Diff compares configuration a with b. Settings present only in a are
+// reported as removed, those only in b as added, and a removed and added
+// setting of the same key, such as two "hostname" lines, as changed. The
+// header comments are ignored and the order of lines within a section is
+// not significant.
+func Diff(a, b Config) ConfigDiff {
+	var d ConfigDiff
+	if s, ok := diffLines("", a.Global, b.Global); ok {
+		d.Sections = append(d.Sections, s)
+	}
+	old := make(map[string]ConfigSection, len(a.Sections))
+	for _, s := range a.Sections {
+		old[s.Header] = s
+	}
+	seen := make(map[string]bool, len(b.Sections))
+	for _, s := range b.Sections {
+		seen[s.Header] = true
+		prev, ok := old[s.Header]
+		if !ok {
+			d.Sections = append(d.Sections, SectionDiff{Section: s.Header, Status: "added", Added: s.Lines})
+			continue
+		}
+		if sd, ok := diffLines(s.Header, prev.Lines, s.Lines); ok {
+			d.Sections = append(d.Sections, sd)
+		}
+	}
+	for _, s := range a.Sections {
+		if !seen[s.Header] {
+			d.Sections = append(d.Sections, SectionDiff{Section: s.Header, Status: "removed", Removed: s.Lines})
+		}
+	}
+	return d
+}
+
+// diffLines compares the settings of one section.
+func diffLines(section string, a, b []string) (SectionDiff, bool) {
+	count := make(map[string]int, len(a))
+	for _, l := range a {
+		count[l]++
+	}
+	var added []string
+	for _, l := range b {
+		if count[l] > 0 {
+			count[l]--
+			continue
+		}
+		added = append(added, l)
+	}
+	var removed []string
+	for _, l := range a {
+		if count[l] > 0 {
+			count[l]--
+			removed = append(removed, l)
+		}
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		return SectionDiff{}, false
+	}
+
+	d := SectionDiff{Section: section, Status: "modified"}
+	byKey := make(map[string][]int)
+	for i, l := range removed {
+		byKey[settingKey(l)] = append(byKey[settingKey(l)], i)
+	}
+	paired := make(map[int]bool)
+	for _, l := range added {
+		k := settingKey(l)
+		if idx := byKey[k]; k != "" && len(idx) > 0 {
+			byKey[k] = idx[1:]
+			paired[idx[0]] = true
+			d.Changed = append(d.Changed, LineChange{Old: removed[idx[0]], New: l})
+			continue
+		}
+		d.Added = append(d.Added, l)
+	}
+	for i, l := range removed {
+		if !paired[i] {
+			d.Removed = append(d.Removed, l)
+		}
+	}
+	return d, true
+}
+
+// settingKey returns the part of a setting that names it: everything up to
+// a quoted argument, or all words but the last. Lines of one word, which
+// only toggle a feature, have no key.
+func settingKey(line string) string {
+	if i := strings.IndexByte(line, '"'); i > 0 {
+		return strings.TrimSpace(line[:i])
+	}
+	f := strings.Fields(line)
+	if len(f) < 2 {
+		return ""
+	}
+	return strings.Join(f[:len(f)-1], " ")
+}
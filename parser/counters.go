@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"strings"
+)
+
+// InterfaceCountersDetail is InterfaceCounters with the common counters in
+// named fields. Counters the switch does not print stay 0; counters this
+// type has no field for are kept in Other under the name the switch uses.
+type InterfaceCountersDetail struct {
+	RxBytes       uint64            `json:"rx_bytes"`
+	TxBytes       uint64            `json:"tx_bytes"`
+	RxPackets     uint64            `json:"rx_packets"`
+	TxPackets     uint64            `json:"tx_packets"`
+	RxUnicast     uint64            `json:"rx_unicast"`
+	TxUnicast     uint64            `json:"tx_unicast"`
+	RxMulticast   uint64            `json:"rx_multicast"`
+	TxMulticast   uint64            `json:"tx_multicast"`
+	RxBroadcast   uint64            `json:"rx_broadcast"`
+	TxBroadcast   uint64            `json:"tx_broadcast"`
+	RxPause       uint64            `json:"rx_pause"`
+	TxPause       uint64            `json:"tx_pause"`
+	RxErrors      uint64            `json:"rx_errors"`
+	TxErrors      uint64            `json:"tx_errors"`
+	RxDiscards    uint64            `json:"rx_discards"`
+	TxDiscards    uint64            `json:"tx_discards"`
+	CRCErrors     uint64            `json:"crc_errors"`
+	AlignErrors   uint64            `json:"align_errors"`
+	Undersize     uint64            `json:"undersize"`
+	Oversize      uint64            `json:"oversize"`
+	Fragments     uint64            `json:"fragments"`
+	Jabbers       uint64            `json:"jabbers"`
+	Collisions    uint64            `json:"collisions"`
+	LateCollision uint64            `json:"late_collisions"`
+	Other         map[string]uint64 `json:"other,omitempty"`
+}
+
+// field returns the field for a counter name normalised by counterKey, or
+// nil.
+func (d *InterfaceCountersDetail) field(key string) *uint64 {
+	switch key {
+	case "rxbytes", "rxoctets", "bytesreceived":
+		return &d.RxBytes
+	case "txbytes", "txoctets", "bytessent":
+		return &d.TxBytes
+	case "rxpkts", "rxpackets", "packetsreceived":
+		return &d.RxPackets
+	case "txpkts", "txpackets", "packetssent":
+		return &d.TxPackets
+	case "rxucast", "rxunicast", "rxunicastpkts":
+		return &d.RxUnicast
+	case "txucast", "txunicast", "txunicastpkts":
+		return &d.TxUnicast
+	case "rxmcast", "rxmulticast", "rxmulticastpkts":
+		return &d.RxMulticast
+	case "txmcast", "txmulticast", "txmulticastpkts":
+		return &d.TxMulticast
+	case "rxbcast", "rxbroadcast", "rxbroadcastpkts":
+		return &d.RxBroadcast
+	case "txbcast", "txbroadcast", "txbroadcastpkts":
+		return &d.TxBroadcast
+	case "rxpause", "rxpausepkts":
+		return &d.RxPause
+	case "txpause", "txpausepkts":
+		return &d.TxPause
+	case "rxerrors", "rxerrorpkts", "inputerrors":
+		return &d.RxErrors
+	case "txerrors", "txerrorpkts", "outputerrors":
+		return &d.TxErrors
+	case "rxdiscards", "rxdrops", "rxdroppkts":
+		return &d.RxDiscards
+	case "txdiscards", "txdrops", "txdroppkts":
+		return &d.TxDiscards
+	case "crc", "crcerrors", "rxcrcerrors", "crcalignerrors", "fcserrors":
+		return &d.CRCErrors
+	case "alignerrors", "alignmenterrors":
+		return &d.AlignErrors
+	case "undersize", "undersizepkts", "runts":
+		return &d.Undersize
+	case "oversize", "oversizepkts", "giants":
+		return &d.Oversize
+	case "fragments":
+		return &d.Fragments
+	case "jabbers", "jabber":
+		return &d.Jabbers
+	case "collisions", "collision":
+		return &d.Collisions
+	case "latecollisions", "latecollision":
+		return &d.LateCollision
+	}
+	return nil
+}
+
+// counterKey normalises a counter name: lower-cased, with spaces and
+// punctuation removed.
+func counterKey(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "", "/", "", ".", "").Replace(strings.ToLower(name))
+}
+
+// Detail returns c with the common counters in named fields.
+func (c InterfaceCounters) Detail() InterfaceCountersDetail {
+	var d InterfaceCountersDetail
+	for name, v := range c {
+		if f := d.field(counterKey(name)); f != nil {
+			*f = v
+			continue
+		}
+		if d.Other == nil {
+			d.Other = make(map[string]uint64)
+		}
+		d.Other[name] = v
+	}
+	return d
+}
+
+// ParseInterfaceCountersDetail is ParseInterfaceCounters returning typed
+// counters per port. It is not in the command registry, whose "show
+// interface counters" entry is ParseInterfaceCounters; call Detail on its
+// result for the typed form.
+func ParseInterfaceCountersDetail(output string, opts ...Option) (map[PortID]InterfaceCountersDetail, error) {
+	stats, err := ParseInterfaceCounters(output, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[PortID]InterfaceCountersDetail, len(stats))
+	for port, c := range stats {
+		out[port] = c.Detail()
+	}
+	return out, nil
+}
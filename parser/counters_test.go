@@ -0,0 +1,51 @@
+package parser_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pascal71/tplink-go/parser"
+)
+
+func TestInterfaceCountersDetail(t *testing.T) {
+	// Spellings of each counter, as printed by different firmware.
+	fields := map[string][]string{
+		"RxBytes":       {"Rx Bytes", "rx-octets", "Bytes Received"},
+		"TxBytes":       {"TX_BYTES", "Tx Octets", "Bytes Sent"},
+		"RxPackets":     {"Rx Pkts", "RxPackets", "Packets Received"},
+		"TxPackets":     {"Tx Pkts", "Tx-Packets", "Packets Sent"},
+		"RxUnicast":     {"Rx Ucast", "Rx Unicast", "Rx Unicast Pkts"},
+		"TxUnicast":     {"Tx Ucast", "Tx Unicast", "Tx Unicast Pkts"},
+		"RxMulticast":   {"Rx Mcast", "Rx Multicast", "Rx Multicast Pkts"},
+		"TxMulticast":   {"Tx Mcast", "Tx Multicast", "Tx Multicast Pkts"},
+		"RxBroadcast":   {"Rx Bcast", "Rx Broadcast", "Rx Broadcast Pkts"},
+		"TxBroadcast":   {"Tx Bcast", "Tx Broadcast", "Tx Broadcast Pkts"},
+		"RxPause":       {"Rx Pause", "Rx Pause Pkts"},
+		"TxPause":       {"Tx Pause", "Tx Pause Pkts"},
+		"RxErrors":      {"Rx Errors", "Rx Error Pkts", "Input Errors"},
+		"TxErrors":      {"Tx Errors", "Tx Error Pkts", "Output Errors"},
+		"RxDiscards":    {"Rx Discards", "Rx Drops", "Rx Drop Pkts"},
+		"TxDiscards":    {"Tx Discards", "Tx Drops", "Tx Drop Pkts"},
+		"CRCErrors":     {"CRC", "CRC Errors", "Rx CRC Errors", "CRC/Align Errors", "FCS Errors"},
+		"AlignErrors":   {"Align Errors", "Alignment Errors"},
+		"Undersize":     {"Undersize", "Undersize Pkts", "Runts"},
+		"Oversize":      {"Oversize", "Oversize Pkts", "Giants"},
+		"Fragments":     {"Fragments"},
+		"Jabbers":       {"Jabbers", "Jabber"},
+		"Collisions":    {"Collisions", "Collision"},
+		"LateCollision": {"Late Collisions", "Late-Collision"},
+	}
+	for field, names := range fields {
+		for _, name := range names {
+			d := parser.InterfaceCounters{name: 42}.Detail()
+			if got := reflect.ValueOf(d).FieldByName(field).Uint(); got != 42 || d.Other != nil {
+				t.Errorf("%q: %s = %d, Other = %v, want 42 in %s", name, field, got, d.Other, field)
+			}
+		}
+	}
+
+	d := parser.InterfaceCounters{"Rx Bytes": 1, "Rx 64 Octets": 2}.Detail()
+	if d.RxBytes != 1 || len(d.Other) != 1 || d.Other["Rx 64 Octets"] != 2 {
+		t.Errorf("Detail = %+v, want an unknown counter kept in Other under its printed name", d)
+	}
+}
@@ -0,0 +1,213 @@
+package parser
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// CPUUtilization is the CPU load of one stack unit, in percent.
+type CPUUtilization struct {
+	Unit        int       `json:"unit,omitempty"` // stack unit, 0 if not reported
+	FiveSeconds float64   `json:"five_seconds"`
+	OneMinute   float64   `json:"one_minute"`
+	FiveMinutes float64   `json:"five_minutes"`
+	Cores       []CPUCore `json:"cores,omitempty"` // per-core load, where reported
+}
+
+// CPUCore is the load of one core of a multi-core CPU, in percent.
+type CPUCore struct {
+	Core        int     `json:"core"`
+	FiveSeconds float64 `json:"five_seconds"`
+	OneMinute   float64 `json:"one_minute"`
+	FiveMinutes float64 `json:"five_minutes"`
+}
+
+// cpuPeriod returns which average a heading or key such as "CPU
+// utilization in five seconds" or "1 Min(%)" names: 0 for five seconds, 1
+// for one minute, 2 for five minutes, or -1.
+func cpuPeriod(s string) int {
+	s = strings.ToLower(s)
+	switch {
+	case strings.Contains(s, "five sec"), strings.Contains(s, "5 sec"), strings.Contains(s, "5sec"), strings.Contains(s, "5s"):
+		return 0
+	case strings.Contains(s, "one min"), strings.Contains(s, "1 min"), strings.Contains(s, "1min"), strings.Contains(s, "1m"):
+		return 1
+	case strings.Contains(s, "five min"), strings.Contains(s, "5 min"), strings.Contains(s, "5min"), strings.Contains(s, "5m"):
+		return 2
+	}
+	return -1
+}
+
+// setLoad stores the load for period p as returned by cpuPeriod.
+func setLoad(p int, v float64, five, one, fiveMin *float64) {
+	switch p {
+	case 0:
+		*five = v
+	case 1:
+		*one = v
+	case 2:
+		*fiveMin = v
+	}
+}
+
+// ParseCPUUtilization parses "show cpu-utilization": a table with a row per
+// stack unit, and optionally per core, and five second, one minute and five
+// minute columns; or "CPU utilization in five seconds: 11%" lines, grouped
+// under "Unit 1" and "Core 0" headings on stacks and multi-core switches.
+func ParseCPUUtilization(output string, opts ...Option) ([]CPUUtilization, error) {
+	o := newOptions(opts)
+	var units []CPUUtilization
+	unit := func(n int) *CPUUtilization {
+		for i := range units {
+			if units[i].Unit == n {
+				return &units[i]
+			}
+		}
+		units = append(units, CPUUtilization{Unit: n})
+		return &units[len(units)-1]
+	}
+	core := func(u *CPUUtilization, n int) *CPUCore {
+		for i := range u.Cores {
+			if u.Cores[i].Core == n {
+				return &u.Cores[i]
+			}
+		}
+		u.Cores = append(u.Cores, CPUCore{Core: n})
+		return &u.Cores[len(u.Cores)-1]
+	}
+
+	for _, t := range Tables(output) {
+		periods := make([]int, len(t.Headers))
+		found := false
+		for i, h := range t.Headers {
+			periods[i] = cpuPeriod(h)
+			found = found || periods[i] >= 0
+		}
+		if !found {
+			continue
+		}
+		unitCol := t.Col("unit", "unit id", "slot")
+		coreCol := t.Col("core", "core id", "cpu", "cpu id")
+		for r, row := range t.Rows {
+			rr := t.reader(r)
+			n := rr.int(unitCol)
+			loads := make([]float64, len(periods))
+			for i, p := range periods {
+				if p >= 0 {
+					loads[i] = rr.float(i)
+				}
+			}
+			c := -1 // core, -1 for the unit as a whole
+			if v := value(row, coreCol); v != "" && !strings.EqualFold(v, "total") && !strings.EqualFold(v, "all") {
+				if f, ok := parseNumber(strings.TrimLeft(v, "CcOoRrEePpUu ")); ok {
+					c = int(f)
+				} else {
+					rr.fail(coreCol, errors.New("invalid core"))
+				}
+			}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			u := unit(n)
+			five, one, fiveMin := &u.FiveSeconds, &u.OneMinute, &u.FiveMinutes
+			if c >= 0 {
+				cc := core(u, c)
+				five, one, fiveMin = &cc.FiveSeconds, &cc.OneMinute, &cc.FiveMinutes
+			}
+			for i, p := range periods {
+				if p >= 0 && !isPlaceholder(value(row, i)) {
+					setLoad(p, loads[i], five, one, fiveMin)
+				}
+			}
+		}
+	}
+	if len(units) > 0 {
+		return units, nil
+	}
+
+	var (
+		u  *CPUUtilization
+		cc *CPUCore
+	)
+	for n, line := range splitLines(output) {
+		key, val, _ := strings.Cut(strings.TrimSpace(line), ":")
+		key = strings.TrimSpace(key)
+		lower := strings.ToLower(key)
+		p := cpuPeriod(key)
+		if rest, ok := strings.CutPrefix(lower, "unit"); ok {
+			if n, ok := headingNumber(rest, val, p); ok {
+				u, cc = unit(n), nil
+			}
+		}
+		if rest, ok := strings.CutPrefix(lower, "core"); ok {
+			if n, ok := headingNumber(rest, val, p); ok {
+				if u == nil {
+					u = unit(0)
+				}
+				cc = core(u, n)
+			}
+		}
+		if p < 0 || isPlaceholder(val) {
+			continue
+		}
+		v, err := parseFloat(val)
+		if err != nil {
+			if err := o.malformed(&ParseError{Line: n + 1, Text: line, Field: key, Err: err}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if u == nil {
+			u = unit(0)
+		}
+		if cc != nil {
+			setLoad(p, v, &cc.FiveSeconds, &cc.OneMinute, &cc.FiveMinutes)
+		} else {
+			setLoad(p, v, &u.FiveSeconds, &u.OneMinute, &u.FiveMinutes)
+		}
+	}
+	return units, nil
+}
+
+// headingNumber returns the number of a "Unit 1" or "Core: 0" heading,
+// given the key after the word, its value and the period the key names. A
+// key naming a period, as in "Unit 1 CPU utilization in five seconds",
+// carries its number in the key.
+func headingNumber(rest, val string, period int) (int, bool) {
+	s := rest
+	if strings.TrimSpace(rest) == "" && period < 0 {
+		s = val
+	}
+	n, ok := parseNumber(s)
+	return int(n), ok
+}
+
+// UtilizationSample is one point of a CPU or memory utilization history,
+// in percent.
+type UtilizationSample struct {
+	Unit          int     `json:"unit,omitempty" table:"unit,unit id"`                       // stack unit, 0 if not reported
+	Time          string  `json:"time" table:"time,timestamp,date,sample,interval,required"` // as printed, e.g. "2024-05-01 10:05:00" or "5 min ago"
+	CPUPercent    float64 `json:"cpu_percent,omitempty" table:"cpu*"`
+	MemoryPercent float64 `json:"memory_percent,omitempty" table:"memory*,mem*"`
+	Percent       float64 `json:"percent,omitempty" table:"utilization*,usage*"` // a column not saying whether it is CPU or memory
+}
+
+// ParseUtilizationHistory parses the CPU and memory utilization history
+// tables ("show cpu-utilization history", "show memory-utilization
+// history"): a time column and one or more utilization columns.
+func ParseUtilizationHistory(output string, opts ...Option) ([]UtilizationSample, error) {
+	var samples []UtilizationSample
+	for _, t := range Tables(output) {
+		if t.Col("cpu*", "memory*", "mem*", "utilization*", "usage*") < 0 {
+			continue
+		}
+		if err := t.Decode(&samples, opts...); err != nil {
+			return nil, err
+		}
+	}
+	return slices.DeleteFunc(samples, func(s UtilizationSample) bool { return s.Time == "" }), nil
+}
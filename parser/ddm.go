@@ -0,0 +1,223 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SFPDiagnostics is the digital diagnostics (DDM) reading of one transceiver.
+type SFPDiagnostics struct {
+	Port        PortID   `json:"port"`
+	Temperature DDMValue `json:"temperature"`  // °C
+	Voltage     DDMValue `json:"voltage"`      // Vcc, V
+	BiasCurrent DDMValue `json:"bias_current"` // mA
+	TxPower     DDMValue `json:"tx_power"`     // dBm
+	RxPower     DDMValue `json:"rx_power"`     // dBm
+	TxFault     bool     `json:"tx_fault"`
+	RxLOS       bool     `json:"rx_los"` // loss of signal
+}
+
+// DDMValue is a DDM reading with the module's alarm and warning thresholds,
+// which are nil when not reported.
+type DDMValue struct {
+	Value       float64  `json:"value"`
+	Valid       bool     `json:"valid"` // false when the module reported no reading
+	HighAlarm   *float64 `json:"high_alarm,omitempty"`
+	HighWarning *float64 `json:"high_warning,omitempty"`
+	LowWarning  *float64 `json:"low_warning,omitempty"`
+	LowAlarm    *float64 `json:"low_alarm,omitempty"`
+}
+
+// Alarm returns "high-alarm", "high-warning", "low-warning" or "low-alarm"
+// when the reading crosses a threshold, or "".
+func (v DDMValue) Alarm() string {
+	switch {
+	case !v.Valid:
+		return ""
+	case v.HighAlarm != nil && v.Value >= *v.HighAlarm:
+		return "high-alarm"
+	case v.LowAlarm != nil && v.Value <= *v.LowAlarm:
+		return "low-alarm"
+	case v.HighWarning != nil && v.Value >= *v.HighWarning:
+		return "high-warning"
+	case v.LowWarning != nil && v.Value <= *v.LowWarning:
+		return "low-warning"
+	}
+	return ""
+}
+
+// numberRegex matches the leading number of a reading such as "-2.31dBm".
+var numberRegex = regexp.MustCompile(`^[-+]?\d+(?:\.\d+)?`)
+
+// parseNumber parses the leading number of s, ignoring units.
+func parseNumber(s string) (float64, bool) {
+	m := numberRegex.FindString(strings.TrimSpace(s))
+	if m == "" {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(m, 64)
+	return f, err == nil
+}
+
+// ParseSFPDiagnostics parses the transceiver DDM status table ("show ddm
+// status") and, if present, the per-parameter threshold tables that follow a
+// "Temperature", "Voltage", "Bias Current", "Tx Power" or "Rx Power" title.
+func ParseSFPDiagnostics(output string, opts ...Option) (map[PortID]SFPDiagnostics, error) {
+	o := newOptions(opts)
+	ports := make(map[PortID]SFPDiagnostics)
+	get := func(port string) SFPDiagnostics {
+		id := portID(port)
+		d, ok := ports[id]
+		if !ok {
+			d.Port = id
+		}
+		return d
+	}
+
+	// Remember the title line above each table, which names the parameter
+	// of threshold tables.
+	lines := splitLines(output)
+	titles := make(map[int]string)
+	n := 0
+	prev := ""
+	for i, line := range lines {
+		if isSeparator(line) && i > 0 && strings.TrimSpace(lines[i-1]) != "" {
+			titles[n] = strings.ToLower(prev)
+			n++
+		}
+		if strings.TrimSpace(line) != "" && (i+1 >= len(lines) || !isSeparator(lines[i+1])) {
+			prev = strings.TrimSpace(line)
+		}
+	}
+
+	for ti, t := range Tables(output) {
+		port := t.Col("port", "interface")
+		if port < 0 {
+			continue
+		}
+		if hi := t.Col("high alarm", "high-alarm", "alarm high"); hi >= 0 {
+			param := ddmParam(titles[ti])
+			if param == "" {
+				continue
+			}
+			cur := t.Col("current", "value", "current value")
+			hw := t.Col("high warn", "high warning", "high-warn", "warn high")
+			lw := t.Col("low warn", "low warning", "low-warn", "warn low")
+			la := t.Col("low alarm", "low-alarm", "alarm low")
+			for r, row := range t.Rows {
+				ok, err := o.portRow(t, r, port)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+				rr := t.reader(r)
+				d := get(value(row, port))
+				v := *d.reading(param)
+				if cur >= 0 {
+					v.Value, v.Valid = rr.optFloat(cur)
+				}
+				v.HighAlarm = threshold(rr, hi)
+				v.HighWarning = threshold(rr, hw)
+				v.LowWarning = threshold(rr, lw)
+				v.LowAlarm = threshold(rr, la)
+				if rr.err != nil {
+					if err := o.malformed(rr.err); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				*d.reading(param) = v
+				ports[d.Port] = d
+			}
+			continue
+		}
+		cols := make(map[string]int)
+		for i, h := range t.Headers {
+			if p := ddmParam(h); p != "" {
+				cols[p] = i
+			}
+		}
+		fault := t.Col("transmit fault", "tx fault", "tx-fault")
+		los := t.Col("loss of signal", "rx los", "los", "rx-los")
+		for r, row := range t.Rows {
+			ok, err := o.portRow(t, r, port)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			rr := t.reader(r)
+			d := get(value(row, port))
+			for p, i := range cols {
+				v := d.reading(p)
+				v.Value, v.Valid = rr.optFloat(i)
+			}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			d.TxFault = isYes(value(row, fault))
+			d.RxLOS = isYes(value(row, los))
+			ports[d.Port] = d
+		}
+	}
+	return ports, nil
+}
+
+// ddmParam returns the parameter a heading or title names, or "".
+func ddmParam(s string) string {
+	s = strings.ToLower(s)
+	switch {
+	case strings.Contains(s, "temperature") || strings.HasPrefix(s, "temp"):
+		return "temperature"
+	case strings.Contains(s, "voltage") || strings.HasPrefix(s, "vcc"):
+		return "voltage"
+	case strings.Contains(s, "bias"):
+		return "bias"
+	case strings.Contains(s, "tx power") || strings.Contains(s, "tx-power") || strings.Contains(s, "transmit power"):
+		return "tx"
+	case strings.Contains(s, "rx power") || strings.Contains(s, "rx-power") || strings.Contains(s, "receive power"):
+		return "rx"
+	}
+	return ""
+}
+
+// reading returns the reading for a ddmParam name.
+func (d *SFPDiagnostics) reading(param string) *DDMValue {
+	switch param {
+	case "temperature":
+		return &d.Temperature
+	case "voltage":
+		return &d.Voltage
+	case "bias":
+		return &d.BiasCurrent
+	case "tx":
+		return &d.TxPower
+	}
+	return &d.RxPower
+}
+
+// threshold parses a threshold column of the row rr reads, returning nil
+// if it holds no number.
+func threshold(rr *rowReader, col int) *float64 {
+	f, ok := rr.optFloat(col)
+	if !ok {
+		return nil
+	}
+	return &f
+}
+
+// isYes reports whether a flag column reads as set.
+func isYes(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "yes", "true", "on", "1", "fault", "detected":
+		return true
+	}
+	return false
+}
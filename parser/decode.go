@@ -0,0 +1,275 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	portIDType   = reflect.TypeOf(PortID(""))
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// errNotPort is the reason for a malformed row whose port column holds
+// something else.
+var errNotPort = errors.New("not a port name")
+
+// Decode appends the rows of t to the slice that out points to, one struct
+// per row. Struct fields are matched to columns by a "table" tag listing
+// the headings the column may have, as for Col, e.g.
+//
+//	Port PortID `table:"port,interface"`
+//	MAC  string `table:"mac address,mac,required"`
+//
+// Fields without a tag, and columns not in the table, are left zero. If a
+// column tagged "required" is missing, the table holds something else and
+// no rows are decoded.
+//
+// Supported field types are string, PortID, []PortID (an expanded port
+// list), int (the leading number, or the number after a word as in
+// "VLAN10"), float64 (the leading number, ignoring units), bool ("Enable",
+// "Yes", "On" and the like) and time.Duration ("hh:mm:ss", a Go duration or
+// seconds). Placeholders such as "--", "N/A" or "Infinite" are left zero.
+// A row with another value that does not parse is malformed: by default
+// Decode returns a *ParseError for it, with Lenient the row is skipped.
+// So is a row whose struct, having a validate method, fails it.
+func (t Table) Decode(out any, opts ...Option) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Slice || v.Elem().Type().Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode table: want a pointer to a slice of structs, got %T", out)
+	}
+	slice := v.Elem()
+	typ := slice.Type().Elem()
+
+	cols := make([]int, typ.NumField())
+	for i := range cols {
+		f := typ.Field(i)
+		tag, ok := f.Tag.Lookup("table")
+		if !ok || tag == "-" || !f.IsExported() {
+			cols[i] = -1
+			continue
+		}
+		if !decodable(f.Type) {
+			return fmt.Errorf("decode table: unsupported type %s of field %s", f.Type, f.Name)
+		}
+		names := strings.Split(tag, ",")
+		required := names[len(names)-1] == "required"
+		if required {
+			names = names[:len(names)-1]
+		}
+		cols[i] = t.Col(names...)
+		if cols[i] < 0 && required {
+			return nil
+		}
+	}
+
+	o := newOptions(opts)
+rows:
+	for r, row := range t.Rows {
+		elem := reflect.New(typ).Elem()
+		for i, col := range cols {
+			if col < 0 {
+				continue
+			}
+			if err := setField(elem.Field(i), value(row, col)); err != nil {
+				if err := o.malformed(t.rowError(r, t.Headers[col], err)); err != nil {
+					return err
+				}
+				continue rows
+			}
+		}
+		if v, ok := elem.Addr().Interface().(validator); ok {
+			if err := v.validate(); err != nil {
+				if err := o.malformed(t.rowError(r, "", err)); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		slice.Set(reflect.Append(slice, elem))
+	}
+	return nil
+}
+
+// validator is implemented by row types with checks beyond their field
+// types, such as that an address parses.
+type validator interface {
+	validate() error
+}
+
+// decodable reports whether Decode can fill a field of type t.
+func decodable(t reflect.Type) bool {
+	switch t {
+	case portIDType, durationType, reflect.TypeOf([]PortID(nil)):
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Int, reflect.Float64, reflect.Bool:
+		return true
+	}
+	return false
+}
+
+// setField parses s into f, whose type decodable accepted. It returns an
+// error if s is neither a value of the field's type nor a placeholder.
+func setField(f reflect.Value, s string) error {
+	switch f.Type() {
+	case portIDType:
+		f.SetString(string(portID(s)))
+		return nil
+	case durationType:
+		d, ok := parseSpanOK(s)
+		if !ok && !isPlaceholder(s) {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		f.SetInt(int64(d))
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Int:
+		n, err := parseInt(s)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(n))
+	case reflect.Float64:
+		n, err := parseFloat(s)
+		if err != nil {
+			return err
+		}
+		f.SetFloat(n)
+	case reflect.Bool:
+		f.SetBool(isEnabled(s) || isYes(s))
+	case reflect.Slice:
+		f.Set(reflect.ValueOf(expandPorts(s)))
+	}
+	return nil
+}
+
+// parseInt parses the leading number of s, or the number after a word as
+// in "VLAN10". A placeholder is 0; anything else is an error.
+func parseInt(s string) (int, error) {
+	n, ok := parseNumber(s)
+	if !ok {
+		n, ok = parseNumber(strings.TrimLeftFunc(s, func(r rune) bool { return r == ' ' || unicode.IsLetter(r) }))
+	}
+	if !ok && !isPlaceholder(s) {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return int(n), nil
+}
+
+// parseFloat parses the leading number of s, ignoring units. A placeholder
+// is 0; anything else is an error.
+func parseFloat(s string) (float64, error) {
+	n, ok := parseNumber(s)
+	if !ok && !isPlaceholder(s) {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return n, nil
+}
+
+// rowReader parses the fields of row r of t for parsers walking a table
+// by hand, keeping the first field that is malformed.
+type rowReader struct {
+	t   Table
+	r   int
+	err *ParseError // first malformed field, nil if none
+}
+
+// reader returns a rowReader for row r of t.
+func (t Table) reader(r int) *rowReader {
+	return &rowReader{t: t, r: r}
+}
+
+// int returns the number in column col as parseInt does, 0 if col is -1.
+func (rr *rowReader) int(col int) int {
+	n, err := parseInt(value(rr.t.Rows[rr.r], col))
+	rr.fail(col, err)
+	return n
+}
+
+// float returns the number in column col as parseFloat does, 0 if col is -1.
+func (rr *rowReader) float(col int) float64 {
+	n, err := parseFloat(value(rr.t.Rows[rr.r], col))
+	rr.fail(col, err)
+	return n
+}
+
+// span returns the time span in column col as parseSpan does, 0 for a
+// placeholder or if col is -1.
+func (rr *rowReader) span(col int) time.Duration {
+	s := value(rr.t.Rows[rr.r], col)
+	d, ok := parseSpanOK(s)
+	if !ok && !isPlaceholder(s) {
+		rr.fail(col, fmt.Errorf("invalid duration %q", s))
+	}
+	return d
+}
+
+// optFloat is float for columns that may hold no value: ok is false for a
+// placeholder, or if col is -1.
+func (rr *rowReader) optFloat(col int) (n float64, ok bool) {
+	s := value(rr.t.Rows[rr.r], col)
+	if isPlaceholder(s) {
+		return 0, false
+	}
+	return rr.float(col), true
+}
+
+// fail records err for column col unless a field has failed already.
+func (rr *rowReader) fail(col int, err error) {
+	if err != nil && rr.err == nil {
+		rr.err = rr.t.rowError(rr.r, value(rr.t.Headers, col), err)
+	}
+}
+
+// lineError returns a ParseError for line i, counted from 0, of the output
+// failing in field.
+func lineError(i int, line, field string, err error) *ParseError {
+	return &ParseError{Line: i + 1, Text: line, Field: field, Err: err}
+}
+
+// portRow reports whether column col of row r of t names a port. A row
+// holding something else there is malformed, unless it is empty as
+// continuation rows are; err is not nil if the parser must stop.
+func (o *options) portRow(t Table, r, col int) (ok bool, err error) {
+	v := value(t.Rows[r], col)
+	if isPortName(v) {
+		return true, nil
+	}
+	if v == "" {
+		return false, nil
+	}
+	return false, o.malformed(t.rowError(r, t.Headers[col], errNotPort))
+}
+
+// checkPort returns errNotPort for a decoded port column holding something
+// else. An empty one, from a continuation row, passes.
+func checkPort(p PortID) error {
+	if p != "" && !isPortName(string(p)) {
+		return errNotPort
+	}
+	return nil
+}
+
+// isPlaceholder reports whether s stands for no value, or an unbounded
+// one, rather than being a malformed value: empty, a run of dashes, or a
+// word such as "N/A", "None" or "Infinite".
+func isPlaceholder(s string) bool {
+	s = strings.TrimSpace(s)
+	if strings.Trim(s, "-") == "" {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "n/a", "na", "none", "unknown", "unassigned", "infinite", "infinity", "permanent", "unlimited", "forever", "static", "no limit":
+		return true
+	}
+	return false
+}
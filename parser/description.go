@@ -0,0 +1,37 @@
+package parser
+
+// InterfaceDescription is a row of "show interface description".
+type InterfaceDescription struct {
+	Port        PortID `json:"port" table:"interface,port,required"`
+	Status      string `json:"status,omitempty" table:"status,link status,state,admin"`
+	Protocol    string `json:"protocol,omitempty" table:"protocol,link,line protocol"`
+	Description string `json:"description" table:"description,desc,port description,name"` // "" if none is set
+}
+
+// ParseInterfaceDescriptions parses "show interface description". Columns
+// are split on the heading positions, so descriptions keep their spaces and
+// ports without one get an empty description.
+func ParseInterfaceDescriptions(output string, opts ...Option) (map[PortID]InterfaceDescription, error) {
+	ports := make(map[PortID]InterfaceDescription)
+	t, ok := FindTable(output, "interface")
+	if !ok {
+		t, ok = FindTable(output, "port")
+	}
+	if !ok {
+		return ports, nil
+	}
+	var rows []InterfaceDescription
+	if err := t.Decode(&rows, opts...); err != nil {
+		return nil, err
+	}
+	for _, d := range rows {
+		if isPortName(string(d.Port)) {
+			ports[d.Port] = d
+		}
+	}
+	return ports, nil
+}
+
+func (d *InterfaceDescription) validate() error {
+	return checkPort(d.Port)
+}
@@ -0,0 +1,200 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// DHCPRelay is the DHCP relay configuration.
+type DHCPRelay struct {
+	Enabled    bool                 `json:"enabled"`
+	Option82   bool                 `json:"option82"` // relay agent information option inserted
+	Servers    []string             `json:"servers"`  // global server addresses
+	Interfaces []DHCPRelayInterface `json:"interfaces"`
+	Fields     map[string]string    `json:"fields"`
+}
+
+// DHCPRelayInterface is the relay setting of one layer 3 interface.
+type DHCPRelayInterface struct {
+	Interface string   `json:"interface"` // as printed, e.g. "VLAN10"
+	Servers   []string `json:"servers"`   // helper addresses
+}
+
+// ParseDHCPRelay parses "show ip dhcp relay" and "show ip helper-address":
+// the relay and option 82 state, global server addresses, and a table of
+// interfaces and their helper addresses. A row with an empty interface adds
+// a server to the interface above it.
+func ParseDHCPRelay(output string, opts ...Option) (DHCPRelay, error) {
+	o := newOptions(opts)
+	r := DHCPRelay{Fields: make(map[string]string)}
+	for _, line := range splitLines(output) {
+		key, val, ok := cutField(line, ":")
+		if !ok {
+			continue
+		}
+		r.Fields[key] = val
+		k := strings.ToLower(key)
+		switch {
+		case strings.Contains(k, "option 82") || strings.Contains(k, "option82") || strings.Contains(k, "information option"):
+			r.Option82 = isEnabled(val)
+		case k == "dhcp relay" || strings.Contains(k, "relay status") || strings.Contains(k, "relay state") || k == "status" || k == "state":
+			r.Enabled = isEnabled(val)
+		case strings.Contains(k, "server") || strings.Contains(k, "helper"):
+			r.Servers = append(r.Servers, addressesOf(val)...)
+		}
+	}
+
+	for _, t := range Tables(output) {
+		iface := t.Col("interface", "vlan", "interface name")
+		server := t.Col("helper address", "helper-address", "server address", "server ip", "dhcp server", "server*", "helper*")
+		if iface < 0 || server < 0 {
+			continue
+		}
+		for i, row := range t.Rows {
+			addrs := addressesOf(value(row, server))
+			if len(addrs) == 0 && !isPlaceholder(value(row, server)) {
+				if err := o.malformed(t.rowError(i, t.Headers[server], errors.New("no IP address"))); err != nil {
+					return DHCPRelay{}, err
+				}
+				continue
+			}
+			switch name := value(row, iface); {
+			case name != "":
+				r.Interfaces = append(r.Interfaces, DHCPRelayInterface{Interface: name, Servers: addrs})
+			case len(r.Interfaces) > 0:
+				last := &r.Interfaces[len(r.Interfaces)-1]
+				last.Servers = append(last.Servers, addrs...)
+			}
+		}
+	}
+	return r, nil
+}
+
+// addressesOf returns the IP addresses in a list such as "10.0.0.1,
+// 10.0.0.2".
+func addressesOf(s string) []string {
+	var addrs []string
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == ';' }) {
+		if net.ParseIP(f) != nil {
+			addrs = append(addrs, f)
+		}
+	}
+	return addrs
+}
+
+// DHCPServer is the state of the switch's own DHCP server.
+type DHCPServer struct {
+	Enabled   bool                `json:"enabled"`
+	Pools     []DHCPPool          `json:"pools"`
+	Bindings  []DHCPServerBinding `json:"bindings"`
+	Conflicts []DHCPConflict      `json:"conflicts"`
+	Fields    map[string]string   `json:"fields"` // lines outside a pool
+}
+
+// DHCPPool is one address pool of the DHCP server.
+type DHCPPool struct {
+	Name    string            `json:"name"`
+	Network string            `json:"network,omitempty"` // as printed, e.g. "192.168.1.0/24"
+	Mask    string            `json:"mask,omitempty"`
+	Gateway string            `json:"gateway,omitempty"` // default router handed out
+	DNS     []string          `json:"dns"`
+	Domain  string            `json:"domain,omitempty"`
+	Lease   string            `json:"lease,omitempty"` // lease time as printed
+	Fields  map[string]string `json:"fields"`          // lines of the pool
+}
+
+// DHCPServerBinding is an address leased by the DHCP server.
+type DHCPServerBinding struct {
+	IP      string `json:"ip" table:"ip address,ip,ip-address,required"`
+	MAC     string `json:"mac" table:"hardware address,mac address,client-identifier,client id,client-id,mac,required"`
+	Expires string `json:"expires,omitempty" table:"lease expiration,lease expire,expires,lease*"` // as printed, e.g. "Infinite"
+	Type    string `json:"type,omitempty" table:"type,binding type"`                               // e.g. "Automatic", "Manual"
+	Pool    string `json:"pool,omitempty" table:"pool,pool name"`
+}
+
+// DHCPConflict is an address the DHCP server found already in use.
+type DHCPConflict struct {
+	IP     string `json:"ip" table:"ip address,ip,ip-address,required"`
+	Method string `json:"method" table:"detection method,method,required"` // e.g. "Ping", "Gratuitous ARP"
+	Time   string `json:"time,omitempty" table:"detection time,time"`
+}
+
+// ParseDHCPServer parses the DHCP server status: "show ip dhcp server
+// status" and the "Pool Name:" blocks of "show ip dhcp server pool", the
+// binding table of "show ip dhcp server binding" and the conflict table of
+// "show ip dhcp server conflict", alone or concatenated.
+func ParseDHCPServer(output string, opts ...Option) (DHCPServer, error) {
+	s := DHCPServer{Fields: make(map[string]string)}
+	var pool *DHCPPool
+	for _, line := range splitLines(output) {
+		if f := strings.Fields(line); len(f) > 0 && (net.ParseIP(f[0]) != nil || isMAC(f[0])) {
+			continue // a binding or conflict row
+		}
+		key, val, ok := cutField(line, ":")
+		if !ok {
+			continue
+		}
+		k := strings.ToLower(key)
+		if k == "pool name" || k == "pool" || k == "dhcp pool" {
+			s.Pools = append(s.Pools, DHCPPool{Name: val, Fields: make(map[string]string)})
+			pool = &s.Pools[len(s.Pools)-1]
+			continue
+		}
+		if pool == nil {
+			s.Fields[key] = val
+			if strings.Contains(k, "status") || strings.Contains(k, "state") || k == "dhcp server" {
+				s.Enabled = isEnabled(val)
+			}
+			continue
+		}
+		pool.Fields[key] = val
+		switch {
+		case k == "network" || k == "network address" || k == "subnet":
+			f := strings.Fields(val)
+			if len(f) > 0 {
+				pool.Network = f[0]
+			}
+			if len(f) > 1 {
+				pool.Mask = f[1]
+			}
+		case k == "mask" || k == "subnet mask" || k == "network mask":
+			pool.Mask = val
+		case strings.Contains(k, "gateway") || strings.Contains(k, "router"):
+			pool.Gateway = val
+		case strings.Contains(k, "dns"):
+			pool.DNS = append(pool.DNS, addressesOf(val)...)
+		case strings.Contains(k, "domain"):
+			pool.Domain = val
+		case strings.Contains(k, "lease"):
+			pool.Lease = val
+		}
+	}
+
+	for _, t := range Tables(output) {
+		if err := t.Decode(&s.Bindings, opts...); err != nil {
+			return DHCPServer{}, err
+		}
+		if err := t.Decode(&s.Conflicts, opts...); err != nil {
+			return DHCPServer{}, err
+		}
+	}
+	return s, nil
+}
+
+func (b *DHCPServerBinding) validate() error {
+	return checkIP(b.IP)
+}
+
+func (c *DHCPConflict) validate() error {
+	return checkIP(c.IP)
+}
+
+// checkIP returns an error unless s is an IP address.
+func checkIP(s string) error {
+	if net.ParseIP(s) == nil {
+		return fmt.Errorf("invalid IP address %q", s)
+	}
+	return nil
+}
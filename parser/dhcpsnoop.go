@@ -0,0 +1,31 @@
+package parser
+
+import (
+	"time"
+)
+
+// DHCPBinding is an entry of the DHCP snooping binding table.
+type DHCPBinding struct {
+	MAC   string        `json:"mac" table:"mac address,mac,macaddress,mac-address,required"`
+	IP    string        `json:"ip" table:"ip address,ip,ipaddress,ip-address,required"`
+	Lease time.Duration `json:"lease" table:"lease*"` // remaining lease, 0 if infinite or not reported
+	Type  string        `json:"type,omitempty" table:"type,binding type,source"`
+	VLAN  int           `json:"vlan" table:"vlan,vlan id,vid"`
+	Port  PortID        `json:"port" table:"interface,port"`
+}
+
+// ParseDHCPSnoopingBindings parses "show ip dhcp snooping binding" (or the
+// source guard binding table) with MAC, IP, lease, VLAN and port columns.
+func ParseDHCPSnoopingBindings(output string, opts ...Option) ([]DHCPBinding, error) {
+	var bindings []DHCPBinding
+	for _, t := range Tables(output) {
+		if err := t.Decode(&bindings, opts...); err != nil {
+			return nil, err
+		}
+	}
+	return bindings, nil
+}
+
+func (b *DHCPBinding) validate() error {
+	return checkIP(b.IP)
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"strings"
+)
+
+// Dot1xStatus is the 802.1X configuration and state of the switch.
+type Dot1xStatus struct {
+	Enabled    bool              `json:"enabled"`
+	AuthMethod string            `json:"auth_method,omitempty"` // e.g. "PAP", "EAP"
+	Ports      []Dot1xPort       `json:"ports"`
+	Fields     map[string]string `json:"fields"`
+}
+
+// Dot1xPort is the 802.1X state of one port.
+type Dot1xPort struct {
+	Port       PortID   `json:"port"`
+	Enabled    bool     `json:"enabled"`
+	Control    string   `json:"control"`        // "auto", "force-authorized", "force-unauthorized"
+	Type       string   `json:"type,omitempty"` // "MAC Based" or "Port Based"
+	State      string   `json:"state"`          // e.g. "Authorized", "Unauthorized"
+	GuestVLAN  string   `json:"guest_vlan,omitempty"`
+	ClientMACs []string `json:"client_macs,omitempty"` // authenticated supplicants
+}
+
+// Authorized reports whether the port is authorized.
+func (p Dot1xPort) Authorized() bool {
+	s := strings.ToLower(p.State)
+	return strings.HasPrefix(s, "authorized") || strings.HasPrefix(s, "authenticated")
+}
+
+// ParseDot1x parses "show dot1x global" and "show dot1x interface", alone
+// or concatenated. A table with port and MAC address columns, such as the
+// authenticated client list, adds client MACs to the ports.
+func ParseDot1x(output string, opts ...Option) (Dot1xStatus, error) {
+	o := newOptions(opts)
+	st := Dot1xStatus{Fields: make(map[string]string)}
+	for _, line := range splitLines(output) {
+		key, val, ok := cutField(line, ":")
+		if !ok || strings.Contains(key, "  ") {
+			continue
+		}
+		st.Fields[key] = val
+		switch strings.ToLower(key) {
+		case "802.1x state", "dot1x state", "802.1x", "global state", "system auth control":
+			st.Enabled = isEnabled(val)
+		case "authentication method", "auth method", "auth protocol":
+			st.AuthMethod = val
+		}
+	}
+
+	index := make(map[string]int)
+	port := func(name string) *Dot1xPort {
+		i, ok := index[name]
+		if !ok {
+			i = len(st.Ports)
+			index[name] = i
+			st.Ports = append(st.Ports, Dot1xPort{Port: portID(name)})
+		}
+		return &st.Ports[i]
+	}
+	for _, t := range Tables(output) {
+		pc := t.Col("port", "interface")
+		if pc < 0 {
+			continue
+		}
+		if mac := t.Col("mac address", "mac", "client mac", "supplicant mac"); mac >= 0 {
+			state := t.Col("status", "state", "auth state")
+			for r, row := range t.Rows {
+				if ok, err := o.portRow(t, r, pc); !ok || value(row, mac) == "" {
+					if err != nil {
+						return Dot1xStatus{}, err
+					}
+					continue
+				}
+				p := port(value(row, pc))
+				p.ClientMACs = append(p.ClientMACs, value(row, mac))
+				if s := value(row, state); s != "" && p.State == "" {
+					p.State = s
+				}
+			}
+			continue
+		}
+		enabled := t.Col("state", "dot1x state", "802.1x", "admin")
+		control := t.Col("control", "port control", "portcontrol", "control mode", "pae control")
+		typ := t.Col("type", "control type", "auth type", "port method", "portmethod")
+		status := t.Col("status", "auth status", "auth state", "authorized", "port status")
+		guest := t.Col("guestvlan", "guest vlan", "guest-vlan")
+		for r, row := range t.Rows {
+			if ok, err := o.portRow(t, r, pc); !ok {
+				if err != nil {
+					return Dot1xStatus{}, err
+				}
+				continue
+			}
+			p := port(value(row, pc))
+			p.Enabled = isEnabled(value(row, enabled))
+			p.Control = strings.ToLower(value(row, control))
+			p.Type = value(row, typ)
+			p.State = value(row, status)
+			p.GuestVLAN = value(row, guest)
+		}
+	}
+	return st, nil
+}
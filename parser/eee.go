@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"strings"
+)
+
+// EEEPort is the energy saving state of one port.
+type EEEPort struct {
+	Port              PortID `json:"port"`
+	EEE               bool   `json:"eee"`                    // Energy-Efficient-Ethernet (802.3az) configured
+	EEEActive         bool   `json:"eee_active"`             // EEE negotiated with the link partner
+	CableLengthSaving bool   `json:"cable_length_saving"`    // power reduced on short cables
+	EnergyDetect      bool   `json:"energy_detect"`          // power reduced while the link is down
+	CableLength       string `json:"cable_length,omitempty"` // as printed, if measured
+}
+
+// ParseEEE parses "show eee" / "show green-ethernet" style tables with
+// per-port EEE and power saving columns. Only the columns a firmware
+// prints are set; EEEActive falls back to EEE when no operational state is
+// shown.
+func ParseEEE(output string, opts ...Option) (map[PortID]EEEPort, error) {
+	o := newOptions(opts)
+	ports := make(map[PortID]EEEPort)
+	t, ok := FindTable(output, "port")
+	if !ok {
+		t, ok = FindTable(output, "interface")
+	}
+	if !ok {
+		return ports, nil
+	}
+	eee := t.Col("eee", "eee status", "eee admin", "eee admin status", "eee state", "status")
+	oper := t.Col("eee oper", "eee operational", "eee oper status", "eee active", "operational")
+	short := t.Col("cable length saving", "short-reach", "short reach", "cable-length power saving", "length saving")
+	detect := t.Col("energy-detect", "energy detect", "link-down saving", "link-down power saving")
+	length := t.Col("cable length", "vct cable length", "length")
+	for r, row := range t.Rows {
+		if ok, err := o.portRow(t, r, 0); !ok {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		p := EEEPort{
+			Port:              portID(value(row, 0)),
+			EEE:               isEnabled(value(row, eee)),
+			CableLengthSaving: isEnabled(value(row, short)),
+			EnergyDetect:      isEnabled(value(row, detect)),
+			CableLength:       value(row, length),
+		}
+		if oper >= 0 {
+			p.EEEActive = isEnabled(value(row, oper)) || strings.EqualFold(value(row, oper), "active")
+		} else {
+			p.EEEActive = p.EEE
+		}
+		ports[p.Port] = p
+	}
+	return ports, nil
+}
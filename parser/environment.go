@@ -0,0 +1,294 @@
+package parser
+
+import (
+	"strings"
+)
+
+// TemperatureSensor is one temperature reading of the switch.
+type TemperatureSensor struct {
+	Unit      int     `json:"unit,omitempty"` // stack unit, 0 if not reported
+	Sensor    string  `json:"sensor"`         // sensor name
+	Celsius   float64 `json:"celsius"`
+	WarningC  float64 `json:"warning_c,omitempty"`  // warning threshold, 0 if not reported
+	ShutdownC float64 `json:"shutdown_c,omitempty"` // critical threshold, 0 if not reported
+	Status    string  `json:"status,omitempty"`     // e.g. "Normal", "Warning"
+	Alarm     bool    `json:"alarm"`                // status is abnormal or a threshold is reached
+}
+
+// ParseTemperature parses "show temperature" and similar environment
+// output: a table with a temperature column, or "Temperature: 45 C (Normal)"
+// lines, optionally prefixed with the sensor or unit name.
+func ParseTemperature(output string, opts ...Option) ([]TemperatureSensor, error) {
+	o := newOptions(opts)
+	var sensors []TemperatureSensor
+	for _, t := range Tables(output) {
+		temp := -1
+		for i, h := range t.Headers {
+			if strings.HasPrefix(h, "temperature") || strings.HasPrefix(h, "temp") || h == "current" || strings.HasPrefix(h, "current(") {
+				temp = i
+				break
+			}
+		}
+		if temp < 0 {
+			continue
+		}
+		unit := t.Col("unit", "unit id", "slot")
+		name := t.Col("sensor", "name", "location", "id")
+		warn := t.Col("warning", "warning(c)", "warn", "high warning", "alarm", "warning threshold")
+		crit := t.Col("shutdown", "shutdown(c)", "critical", "critical(c)", "high alarm", "shutdown threshold")
+		status := t.Col("status", "state")
+		for r, row := range t.Rows {
+			rr := t.reader(r)
+			c, ok := rr.optFloat(temp)
+			s := TemperatureSensor{
+				Unit:      rr.int(unit),
+				Sensor:    value(row, name),
+				Celsius:   c,
+				WarningC:  rr.float(warn),
+				ShutdownC: rr.float(crit),
+				Status:    value(row, status),
+			}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if !ok {
+				continue
+			}
+			s.setAlarm()
+			sensors = append(sensors, s)
+		}
+	}
+	if len(sensors) > 0 {
+		return sensors, nil
+	}
+
+	for n, line := range splitLines(output) {
+		key, val, ok := strings.Cut(strings.TrimSpace(line), ":")
+		lower := strings.ToLower(key)
+		if !ok || !strings.Contains(lower, "temperature") || strings.Contains(lower, "threshold") || isPlaceholder(val) {
+			continue
+		}
+		c, err := parseFloat(val)
+		if err != nil {
+			if err := o.malformed(&ParseError{Line: n + 1, Text: line, Field: strings.TrimSpace(key), Err: err}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		s := TemperatureSensor{Celsius: c}
+		s.Sensor = strings.TrimSpace(key[:strings.Index(lower, "temperature")])
+		if u, ok := strings.CutPrefix(s.Sensor, "Unit "); ok {
+			if n, ok := parseNumber(u); ok {
+				s.Unit, s.Sensor = int(n), ""
+			}
+		}
+		if open := strings.IndexByte(val, '('); open >= 0 {
+			s.Status = strings.TrimSpace(strings.Trim(val[open:], "()"))
+		}
+		s.setAlarm()
+		sensors = append(sensors, s)
+	}
+	return sensors, nil
+}
+
+// setAlarm sets Alarm from the status and thresholds.
+func (s *TemperatureSensor) setAlarm() {
+	st := strings.ToLower(s.Status)
+	s.Alarm = st != "" && st != "normal" && st != "ok" && st != "good"
+	if s.WarningC > 0 && s.Celsius >= s.WarningC || s.ShutdownC > 0 && s.Celsius >= s.ShutdownC {
+		s.Alarm = true
+	}
+}
+
+// Fan is the state of one fan.
+type Fan struct {
+	Unit     int    `json:"unit,omitempty"` // stack unit, 0 if not reported
+	Fan      string `json:"fan"`            // fan number or name
+	Status   string `json:"status"`         // as printed, e.g. "Normal", "Fault"
+	SpeedRPM int    `json:"speed_rpm,omitempty"`
+	Speed    string `json:"speed,omitempty"` // speed as printed when not in RPM, e.g. "Low"
+	OK       bool   `json:"ok"`
+}
+
+// PowerSupply is the state of one power supply.
+type PowerSupply struct {
+	Unit    int    `json:"unit,omitempty"` // stack unit, 0 if not reported
+	PSU     string `json:"psu"`            // supply number or name
+	Present bool   `json:"present"`
+	Status  string `json:"status"` // as printed, e.g. "Normal", "Not Present"
+	Type    string `json:"type,omitempty"`
+	OK      bool   `json:"ok"`
+}
+
+// ParseFans parses "show fan": a table with a fan status column, or
+// "Fan 1 Status: Normal" lines.
+func ParseFans(output string, opts ...Option) ([]Fan, error) {
+	o := newOptions(opts)
+	var fans []Fan
+	for _, t := range Tables(output) {
+		status := t.Col("status", "state", "fan status")
+		if status < 0 {
+			continue
+		}
+		unit := t.Col("unit", "unit id", "slot")
+		id := t.Col("fan", "fan id", "id", "name", "index")
+		speed := t.Col("speed", "speed(rpm)", "rpm", "fan speed")
+		for r, row := range t.Rows {
+			if value(row, status) == "" {
+				continue
+			}
+			rr := t.reader(r)
+			f := Fan{Unit: rr.int(unit), Fan: value(row, id), Status: value(row, status)}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			f.setSpeed(value(row, speed))
+			f.OK = statusOK(f.Status)
+			fans = append(fans, f)
+		}
+	}
+	if len(fans) > 0 {
+		return fans, nil
+	}
+
+	for _, line := range splitLines(output) {
+		key, val, ok := strings.Cut(strings.TrimSpace(line), ":")
+		lower := strings.ToLower(key)
+		if !ok || !strings.HasPrefix(lower, "fan") && !strings.HasPrefix(lower, "unit") {
+			continue
+		}
+		val = strings.TrimSpace(val)
+		switch {
+		case strings.HasSuffix(lower, "speed"):
+			if n := len(fans); n > 0 {
+				fans[n-1].setSpeed(val)
+			}
+		case strings.HasSuffix(lower, "status") || strings.HasSuffix(lower, "state") || lower == "fan" || isDigits(lastWord(lower)):
+			f := Fan{Status: val, OK: statusOK(val)}
+			f.Unit, f.Fan = unitAndName(key)
+			fans = append(fans, f)
+		}
+	}
+	return fans, nil
+}
+
+// setSpeed records a speed reading, in RPM if numeric.
+func (f *Fan) setSpeed(s string) {
+	if n, ok := parseNumber(s); ok {
+		f.SpeedRPM = int(n)
+	} else {
+		f.Speed = s
+	}
+}
+
+// ParsePowerSupplies parses "show power supply": a table with a status
+// column, or "Power Supply 1: Normal" lines.
+func ParsePowerSupplies(output string, opts ...Option) ([]PowerSupply, error) {
+	o := newOptions(opts)
+	var psus []PowerSupply
+	for _, t := range Tables(output) {
+		status := t.Col("status", "state", "power status")
+		if status < 0 {
+			continue
+		}
+		unit := t.Col("unit", "unit id", "slot")
+		id := t.Col("power", "psu", "power supply", "id", "name", "index")
+		present := t.Col("present", "presence", "installed")
+		typ := t.Col("type", "model", "mode")
+		for r, row := range t.Rows {
+			if value(row, status) == "" {
+				continue
+			}
+			rr := t.reader(r)
+			p := PowerSupply{Unit: rr.int(unit), PSU: value(row, id), Status: value(row, status), Type: value(row, typ)}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			p.Present = !strings.Contains(strings.ToLower(p.Status), "not present") && !strings.Contains(strings.ToLower(p.Status), "absent")
+			if present >= 0 {
+				p.Present = isYes(value(row, present)) || strings.EqualFold(value(row, present), "present")
+			}
+			p.OK = p.Present && statusOK(p.Status)
+			psus = append(psus, p)
+		}
+	}
+	if len(psus) > 0 {
+		return psus, nil
+	}
+
+	for _, line := range splitLines(output) {
+		key, val, ok := strings.Cut(strings.TrimSpace(line), ":")
+		lower := strings.ToLower(key)
+		if !ok || !strings.Contains(lower, "power") && !strings.Contains(lower, "psu") {
+			continue
+		}
+		val = strings.TrimSpace(val)
+		p := PowerSupply{Status: val}
+		p.Unit, p.PSU = unitAndName(key)
+		lv := strings.ToLower(val)
+		p.Present = !strings.Contains(lv, "not present") && !strings.Contains(lv, "absent")
+		p.OK = p.Present && statusOK(strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(lv, "present"), ",")))
+		psus = append(psus, p)
+	}
+	return psus, nil
+}
+
+// statusOK reports whether a hardware status reads as healthy.
+func statusOK(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "normal", "ok", "good", "on", "working", "present", "up", "running":
+		return true
+	}
+	return false
+}
+
+// unitAndName splits a label such as "Unit 1 Fan 2 Status" into the unit
+// number and the number of the fan or supply.
+func unitAndName(label string) (int, string) {
+	f := strings.Fields(label)
+	unit := 0
+	name := ""
+	for i := 0; i < len(f); i++ {
+		if strings.EqualFold(f[i], "unit") && i+1 < len(f) && isDigits(f[i+1]) {
+			n, _ := parseNumber(f[i+1])
+			unit = int(n)
+			i++
+			continue
+		}
+		if isDigits(f[i]) && name == "" {
+			name = f[i]
+		}
+	}
+	return unit, name
+}
+
+// isDigits reports whether s is a non-empty decimal number.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// lastWord returns the last whitespace separated word of s.
+func lastWord(s string) string {
+	f := strings.Fields(s)
+	if len(f) == 0 {
+		return ""
+	}
+	return f[len(f)-1]
+}
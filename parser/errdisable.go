@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"strings"
+	"time"
+)
+
+// ErrDisable is the error-disable state and automatic recovery setup.
+type ErrDisable struct {
+	IntervalSeconds int               `json:"interval_seconds,omitempty"` // recovery timer interval
+	Recovery        map[string]bool   `json:"recovery"`                   // cause -> automatic recovery enabled
+	Ports           []ErrDisabledPort `json:"ports"`
+	Fields          map[string]string `json:"fields"`
+}
+
+// ErrDisabledPort is a port shut down by an error-disable cause.
+type ErrDisabledPort struct {
+	Port      PortID        `json:"port"`
+	Cause     string        `json:"cause"`               // e.g. "loopback", "storm-control", "bpduguard"
+	Remaining time.Duration `json:"remaining,omitempty"` // time until recovery, 0 if none is scheduled
+}
+
+// ParseErrDisable parses "show errdisable recovery" and "show errdisable
+// detect": the per-cause recovery table, the timer interval and the ports
+// currently error-disabled.
+func ParseErrDisable(output string, opts ...Option) (ErrDisable, error) {
+	o := newOptions(opts)
+	e := ErrDisable{
+		Recovery: make(map[string]bool),
+		Fields:   make(map[string]string),
+	}
+	for n, line := range splitLines(output) {
+		key, val, ok := cutField(line, ":")
+		if !ok || val == "" {
+			continue
+		}
+		e.Fields[key] = val
+		switch strings.ToLower(key) {
+		case "timer interval", "recovery interval", "interval", "recovery time", "errdisable recovery interval":
+			i, err := parseInt(val)
+			if err != nil {
+				if err := o.malformed(&ParseError{Line: n + 1, Text: line, Field: key, Err: err}); err != nil {
+					return ErrDisable{}, err
+				}
+				continue
+			}
+			e.IntervalSeconds = i
+		}
+	}
+
+	for _, t := range Tables(output) {
+		port := t.Col("interface", "port")
+		cause := t.Col("errdisable reason", "reason", "cause", "error-disable reason", "error disable reason")
+		if cause < 0 {
+			continue
+		}
+		if port < 0 {
+			status := t.Col("timer status", "status", "recovery", "state", "recovery status")
+			for _, row := range t.Rows {
+				if c := value(row, cause); c != "" {
+					e.Recovery[strings.ToLower(c)] = isEnabled(value(row, status))
+				}
+			}
+			continue
+		}
+		left := -1
+		for i, h := range t.Headers {
+			if strings.HasPrefix(h, "time left") || strings.HasPrefix(h, "remaining") || strings.HasPrefix(h, "recovery time") {
+				left = i
+			}
+		}
+		for r, row := range t.Rows {
+			if ok, err := o.portRow(t, r, port); !ok {
+				if err != nil {
+					return ErrDisable{}, err
+				}
+				continue
+			}
+			rr := t.reader(r)
+			p := ErrDisabledPort{
+				Port:      portID(value(row, port)),
+				Cause:     strings.ToLower(value(row, cause)),
+				Remaining: rr.span(left),
+			}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return ErrDisable{}, err
+				}
+				continue
+			}
+			e.Ports = append(e.Ports, p)
+		}
+	}
+	return e, nil
+}
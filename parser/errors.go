@@ -0,0 +1,35 @@
+package parser
+
+import "fmt"
+
+// ParseError reports output a parser could not make sense of, with enough
+// context to log what the device actually printed.
+type ParseError struct {
+	Command string // CLI command, if known; set by Parse and ParseModel
+	Line    int    // 1-based line number in the output, 0 if unknown
+	Text    string // the offending line
+	Field   string // field or column that failed to parse, if known
+	Err     error  // underlying error, if any
+}
+
+func (e *ParseError) Error() string {
+	msg := "parse error"
+	if e.Command != "" {
+		msg = e.Command + ": " + msg
+	}
+	if e.Line > 0 {
+		msg += fmt.Sprintf(" on line %d", e.Line)
+	} else {
+		msg += " on line"
+	}
+	if e.Field != "" {
+		msg += fmt.Sprintf(" (%s)", e.Field)
+	}
+	msg += fmt.Sprintf(": %q", e.Text)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
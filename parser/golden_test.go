@@ -0,0 +1,9 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/pascal71/tplink-go/testutil"
+)
+
+func TestGolden(t *testing.T) { testutil.Golden(t, "testdata/golden") }
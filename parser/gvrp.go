@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+)
+
+// GVRP is the GVRP (GARP VLAN Registration Protocol) state.
+type GVRP struct {
+	Enabled      bool              `json:"enabled"`
+	Ports        []GVRPPort        `json:"ports"`
+	DynamicVLANs []int             `json:"dynamic_vlans"` // VLANs learned through GVRP
+	Fields       map[string]string `json:"fields"`
+}
+
+// GVRPPort is the GVRP setting of one port. Timers are in centiseconds, as
+// configured on the switch.
+type GVRPPort struct {
+	Port          PortID `json:"port" table:"port,interface,required"`
+	Enabled       bool   `json:"enabled" table:"status,state,gvrp status,gvrp"`
+	Registration  string `json:"registration,omitempty" table:"registration mode,registration,reg mode,mode"` // "Normal", "Fixed" or "Forbidden"
+	JoinTimer     int    `json:"join_timer,omitempty" table:"join timer*,join*"`
+	LeaveTimer    int    `json:"leave_timer,omitempty" table:"leave timer*,leave"`
+	LeaveAllTimer int    `json:"leave_all_timer,omitempty" table:"leaveall timer*,leave all timer*,leaveall*,leave all*"`
+	LAG           string `json:"lag,omitempty" table:"lag,lag id"` // LAG the port belongs to, if any
+}
+
+// ParseGVRP parses "show gvrp global" and "show gvrp interface", alone or
+// concatenated. Dynamically learned VLANs come from a "Dynamic VLANs:" line
+// or from a table of VLANs with a "Dynamic" type, as in "show vlan" output
+// appended to the GVRP status.
+func ParseGVRP(output string, opts ...Option) (GVRP, error) {
+	o := newOptions(opts)
+	g := GVRP{Fields: make(map[string]string)}
+	for n, line := range splitLines(output) {
+		if f := strings.Fields(line); len(f) > 0 && isPortName(f[0]) {
+			continue // a port table row
+		}
+		key, val, ok := cutField(line, ":")
+		if !ok {
+			continue
+		}
+		g.Fields[key] = val
+		k := strings.ToLower(key)
+		switch {
+		case strings.Contains(k, "dynamic vlan"):
+			if isPlaceholder(val) {
+				continue
+			}
+			ids, err := parseVLANList(val)
+			if err != nil {
+				if err := o.malformed(&ParseError{Line: n + 1, Text: line, Field: key, Err: err}); err != nil {
+					return GVRP{}, err
+				}
+				continue
+			}
+			g.DynamicVLANs = append(g.DynamicVLANs, ids...)
+		case k == "gvrp" || k == "status" || k == "state" || strings.Contains(k, "gvrp status") || strings.Contains(k, "gvrp state") || k == "global gvrp":
+			g.Enabled = isEnabled(val)
+		}
+	}
+
+	for _, t := range Tables(output) {
+		if err := t.Decode(&g.Ports, opts...); err != nil {
+			return GVRP{}, err
+		}
+		vlan := t.Col("vlan", "vlan id", "vid")
+		typ := t.Col("type", "vlan type", "registration")
+		if vlan < 0 || typ < 0 {
+			continue
+		}
+		for r, row := range t.Rows {
+			if !strings.EqualFold(value(row, typ), "dynamic") {
+				continue
+			}
+			rr := t.reader(r)
+			id := rr.int(vlan)
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return GVRP{}, err
+				}
+				continue
+			}
+			g.DynamicVLANs = append(g.DynamicVLANs, id)
+		}
+	}
+	g.Ports = slices.DeleteFunc(g.Ports, func(p GVRPPort) bool { return p.Port == "" })
+	for i, p := range g.Ports {
+		if strings.EqualFold(p.LAG, "n/a") || p.LAG == "-" {
+			g.Ports[i].LAG = ""
+		}
+	}
+	slices.Sort(g.DynamicVLANs)
+	g.DynamicVLANs = slices.Compact(g.DynamicVLANs)
+	return g, nil
+}
+
+func (p *GVRPPort) validate() error {
+	return checkPort(p.Port)
+}
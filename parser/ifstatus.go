@@ -0,0 +1,84 @@
+package parser
+
+import "strings"
+
+// InterfaceStatus is a row of "show interface status".
+type InterfaceStatus struct {
+	Port        PortID `json:"port" table:"port,interface,required"`
+	Status      string `json:"status" table:"status,link status,state"` // as printed, e.g. "LinkUp"
+	Up          bool   `json:"up"`
+	Speed       string `json:"speed,omitempty" table:"speed"`   // e.g. "1000M", "Auto"
+	Duplex      string `json:"duplex,omitempty" table:"duplex"` // e.g. "Full", "Auto"
+	FlowControl bool   `json:"flow_control" table:"flowctrl,flow control,flow-control,flowcontrol"`
+	Medium      string `json:"medium,omitempty" table:"active-medium,active medium,medium,type"` // e.g. "Copper", "Fiber"
+	Description string `json:"description,omitempty" table:"description,desc"`
+}
+
+// ParseInterfaceStatus parses "show interface status".
+func ParseInterfaceStatus(output string, opts ...Option) (map[PortID]InterfaceStatus, error) {
+	ports := make(map[PortID]InterfaceStatus)
+	t, ok := FindTable(output, "port")
+	if !ok {
+		t, ok = FindTable(output, "interface")
+	}
+	if !ok {
+		return ports, nil
+	}
+	var rows []InterfaceStatus
+	if err := t.Decode(&rows, opts...); err != nil {
+		return nil, err
+	}
+	for _, s := range rows {
+		if !isPortName(string(s.Port)) {
+			continue
+		}
+		switch strings.ToLower(s.Status) {
+		case "linkup", "link up", "up", "connected":
+			s.Up = true
+		}
+		ports[s.Port] = s
+	}
+	return ports, nil
+}
+
+// InterfaceConfig is a row of "show interface configuration": the
+// configured, rather than negotiated, settings of a port.
+type InterfaceConfig struct {
+	Port        PortID `json:"port" table:"port,interface,required"`
+	Enabled     bool   `json:"enabled" table:"state,status,admin"`
+	Speed       string `json:"speed" table:"speed"`   // e.g. "Auto", "100M"
+	Duplex      string `json:"duplex" table:"duplex"` // e.g. "Auto", "Full"
+	FlowControl bool   `json:"flow_control" table:"flowctrl,flow control,flow-control,flowcontrol"`
+	Medium      string `json:"medium,omitempty" table:"active-medium,active medium,medium,type"`
+	Description string `json:"description,omitempty" table:"description,desc"`
+}
+
+// ParseInterfaceConfig parses "show interface configuration".
+func ParseInterfaceConfig(output string, opts ...Option) (map[PortID]InterfaceConfig, error) {
+	ports := make(map[PortID]InterfaceConfig)
+	t, ok := FindTable(output, "port")
+	if !ok {
+		t, ok = FindTable(output, "interface")
+	}
+	if !ok {
+		return ports, nil
+	}
+	var rows []InterfaceConfig
+	if err := t.Decode(&rows, opts...); err != nil {
+		return nil, err
+	}
+	for _, c := range rows {
+		if isPortName(string(c.Port)) {
+			ports[c.Port] = c
+		}
+	}
+	return ports, nil
+}
+
+func (s *InterfaceStatus) validate() error {
+	return checkPort(s.Port)
+}
+
+func (c *InterfaceConfig) validate() error {
+	return checkPort(c.Port)
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// IGMPGroup is a multicast group learned by IGMP snooping.
+type IGMPGroup struct {
+	VLAN    int           `json:"vlan"`
+	Group   string        `json:"group"`
+	Source  string        `json:"source,omitempty"` // IGMPv3 source, "" for any
+	Ports   []PortID      `json:"ports"`
+	Type    string        `json:"type,omitempty"`    // e.g. "Dynamic", "Static"
+	Expires time.Duration `json:"expires,omitempty"` // time until the entry ages out
+}
+
+// ParseIGMPGroups parses "show ip igmp snooping groups". Member port lists
+// wrapped onto following lines are joined.
+func ParseIGMPGroups(output string, opts ...Option) ([]IGMPGroup, error) {
+	o := newOptions(opts)
+	var groups []IGMPGroup
+	for _, t := range Tables(output) {
+		group := t.Col("multicast ip", "group", "group address", "multicast group", "ip address", "multicast address")
+		ports := t.Col("forward ports", "ports", "port", "member ports", "forward port", "interface")
+		if group < 0 || ports < 0 {
+			continue
+		}
+		vlan := t.Col("vlan id", "vlan", "vid")
+		source := t.Col("source", "source ip", "source address")
+		typ := t.Col("type", "mode")
+		expire := -1
+		for i, h := range t.Headers {
+			if strings.HasPrefix(h, "expir") || strings.HasPrefix(h, "timeout") || strings.HasPrefix(h, "age") {
+				expire = i
+			}
+		}
+		for r, row := range t.Rows {
+			if value(row, group) == "" {
+				if n := len(groups); n > 0 {
+					groups[n-1].Ports = append(groups[n-1].Ports, expandPorts(value(row, ports))...)
+				}
+				continue
+			}
+			rr := t.reader(r)
+			if net.ParseIP(value(row, group)) == nil {
+				rr.fail(group, fmt.Errorf("invalid group address %q", value(row, group)))
+			}
+			g := IGMPGroup{
+				VLAN:    rr.int(vlan),
+				Group:   value(row, group),
+				Ports:   expandPorts(value(row, ports)),
+				Type:    value(row, typ),
+				Source:  value(row, source),
+				Expires: rr.span(expire),
+			}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if g.Source == "*" || g.Source == "-" {
+				g.Source = ""
+			}
+			groups = append(groups, g)
+		}
+	}
+	return groups, nil
+}
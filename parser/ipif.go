@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// IPInterface is a layer 3 interface, usually a VLAN interface.
+type IPInterface struct {
+	Interface string `json:"interface"`
+	VLAN      int    `json:"vlan,omitempty"`
+	IP        string `json:"ip"`
+	Mask      string `json:"mask"`
+	AdminUp   bool   `json:"admin_up"`
+	LinkUp    bool   `json:"link_up"`
+	Origin    string `json:"origin,omitempty"` // "DHCP", "Static", "BOOTP"...
+}
+
+// ParseIPInterfaces parses "show ip interface". Both the brief table and the
+// per-interface listing, a "... is up, line protocol is up" heading followed
+// by "key: value" lines, are understood.
+func ParseIPInterfaces(output string, opts ...Option) ([]IPInterface, error) {
+	o := newOptions(opts)
+	var ifaces []IPInterface
+	for _, t := range Tables(output) {
+		name := t.Col("interface", "vlan", "name")
+		ip := t.Col("ip address", "ip-address", "ip address/mask", "address")
+		if name < 0 || ip < 0 {
+			continue
+		}
+		mask := t.Col("mask", "subnet mask", "ip mask")
+		status := t.Col("status", "admin status", "admin")
+		link := t.Col("protocol", "link status", "link", "line protocol")
+		mode := t.Col("method", "mode", "type", "ip mode", "origin")
+		for r, row := range t.Rows {
+			if value(row, name) == "" {
+				continue
+			}
+			i := IPInterface{Interface: value(row, name), Origin: value(row, mode)}
+			i.IP, i.Mask = splitAddr(value(row, ip))
+			if err := checkAddr(i.IP); err != nil {
+				if err := o.malformed(t.rowError(r, t.Headers[ip], err)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if m := value(row, mask); m != "" {
+				i.Mask = m
+			}
+			i.AdminUp = isUp(value(row, status))
+			i.LinkUp = isUp(value(row, link))
+			if link < 0 {
+				i.LinkUp = i.AdminUp
+			}
+			i.VLAN = vlanOf(i.Interface)
+			ifaces = append(ifaces, i)
+		}
+	}
+	if len(ifaces) > 0 {
+		return ifaces, nil
+	}
+
+	var cur *IPInterface
+	for n, line := range splitLines(output) {
+		line = strings.TrimSpace(line)
+		lower := strings.ToLower(line)
+		if head, proto, ok := strings.Cut(lower, ", line protocol is"); ok {
+			name, state, _ := strings.Cut(line[:len(head)], " is ")
+			ifaces = append(ifaces, IPInterface{
+				Interface: strings.TrimSpace(name),
+				AdminUp:   isUp(state),
+				LinkUp:    isUp(proto),
+			})
+			cur = &ifaces[len(ifaces)-1]
+			cur.VLAN = vlanOf(cur.Interface)
+			continue
+		}
+		for _, prefix := range []string{"internet address is ", "ip address is "} {
+			if strings.HasPrefix(lower, prefix) && !strings.Contains(line, ":") {
+				line = line[:len(prefix)-4] + ":" + line[len(prefix):]
+			}
+		}
+		key, val, ok := strings.Cut(line, ":")
+		if cur == nil || !ok {
+			continue
+		}
+		val = strings.TrimSpace(val)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "ip address", "ip address is", "internet address", "primary ip address":
+			ip, mask := splitAddr(val)
+			if err := checkAddr(ip); err != nil {
+				if err := o.malformed(lineError(n, line, strings.TrimSpace(key), err)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			cur.IP = ip
+			if mask != "" {
+				cur.Mask = mask
+			}
+		case "subnet mask", "mask", "ip mask":
+			cur.Mask = val
+		case "ip address mode", "address mode", "ip mode", "method":
+			cur.Origin = val
+		}
+	}
+	return ifaces, nil
+}
+
+// checkAddr returns an error unless s is an IP address or a placeholder
+// for an interface without one.
+func checkAddr(s string) error {
+	if isPlaceholder(s) {
+		return nil
+	}
+	return checkIP(s)
+}
+
+// splitAddr splits "10.0.0.1/24" or "10.0.0.1 255.255.255.0" into address
+// and mask, converting prefix lengths to dotted masks.
+func splitAddr(s string) (addr, mask string) {
+	if a, m, ok := strings.Cut(s, "/"); ok {
+		return strings.TrimSpace(a), prefixMask(strings.TrimSpace(m))
+	}
+	f := strings.Fields(s)
+	switch len(f) {
+	case 0:
+		return "", ""
+	case 1:
+		return f[0], ""
+	}
+	return f[0], f[1]
+}
+
+// prefixMask converts an IPv4 prefix length such as "24" to a dotted mask;
+// other values are returned unchanged.
+func prefixMask(bits string) string {
+	n, err := strconv.Atoi(bits)
+	if err != nil || n < 0 || n > 32 {
+		return bits
+	}
+	return net.IP(net.CIDRMask(n, 32)).String()
+}
+
+// isUp reports whether a link or admin state reads as up.
+func isUp(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "up", "enable", "enabled", "link-up", "linkup":
+		return true
+	}
+	return false
+}
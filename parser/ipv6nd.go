@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+// IPv6Neighbor is one entry of the switch's IPv6 neighbor cache.
+type IPv6Neighbor struct {
+	IP        string `json:"ip"`
+	MAC       string `json:"mac"`
+	Interface string `json:"interface"`
+	VLAN      int    `json:"vlan,omitempty"`  // from a VLAN interface name, 0 otherwise
+	State     string `json:"state,omitempty"` // e.g. "REACH", "STALE", "Reachable"
+	Age       string `json:"age,omitempty"`   // as printed by the switch
+}
+
+// neighborStates are the neighbor cache states printed by the switches,
+// abbreviated or in full.
+var neighborStates = map[string]bool{
+	"incmp": true, "incomplete": true,
+	"reach": true, "reachable": true,
+	"stale": true, "delay": true, "probe": true,
+	"static": true, "permanent": true, "dynamic": true, "noarp": true,
+}
+
+// errNoMAC is the reason for a malformed neighbor entry that is resolved
+// but has no MAC address.
+var errNoMAC = errors.New("no MAC address")
+
+// incomplete reports whether a neighbor in state has no MAC address yet.
+func incomplete(state string) bool {
+	s := strings.ToLower(state)
+	return s == "incmp" || s == "incomplete"
+}
+
+// ParseIPv6Neighbors parses "show ipv6 neighbors". Columns are told apart
+// by content, not position, so the various layouts (and single-space
+// headings) all parse alike.
+func ParseIPv6Neighbors(output string, opts ...Option) ([]IPv6Neighbor, error) {
+	o := newOptions(opts)
+	var neighbors []IPv6Neighbor
+	for i, line := range splitLines(output) {
+		f := strings.Fields(line)
+		if len(f) < 3 {
+			continue
+		}
+		addr, _, _ := strings.Cut(f[0], "%")
+		if ip := net.ParseIP(addr); ip == nil || ip.To4() != nil {
+			continue
+		}
+		n := IPv6Neighbor{IP: f[0]}
+		var iface []string
+		for _, s := range f[1:] {
+			switch {
+			case n.MAC == "" && isMAC(s):
+				n.MAC = s
+			case n.State == "" && neighborStates[strings.ToLower(s)]:
+				n.State = s
+			case n.Age == "" && (s == "-" || isDigits(s) || strings.Contains(s, ":")):
+				n.Age = s
+			default:
+				iface = append(iface, s)
+			}
+		}
+		if n.MAC == "" && !incomplete(n.State) {
+			if err := o.malformed(lineError(i, strings.TrimSpace(line), "mac", errNoMAC)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		n.Interface = strings.Join(iface, " ")
+		n.VLAN = vlanOf(n.Interface)
+		neighbors = append(neighbors, n)
+	}
+	return neighbors, nil
+}
+
+// isMAC reports whether s is a MAC address in any of the usual notations.
+func isMAC(s string) bool {
+	hw, err := net.ParseMAC(s)
+	return err == nil && len(hw) == 6
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"strings"
+)
+
+// PortIsolation is the port isolation setting of one port: the ports it
+// may forward traffic to.
+type PortIsolation struct {
+	Port         PortID   `json:"port"`
+	LAG          string   `json:"lag,omitempty"` // LAG the port belongs to, if any
+	ForwardPorts []PortID `json:"forward_ports"` // expanded forward list
+}
+
+// Forwards reports whether p may forward traffic to port.
+func (p PortIsolation) Forwards(port PortID) bool {
+	for _, f := range p.ForwardPorts {
+		if f == NormalizePortID(string(port)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePortIsolation parses "show port isolation interface". Forward lists
+// wrapped onto continuation lines are joined.
+func ParsePortIsolation(output string, opts ...Option) (map[PortID]PortIsolation, error) {
+	o := newOptions(opts)
+	ports := make(map[PortID]PortIsolation)
+	t, ok := FindTable(output, "port")
+	if !ok {
+		t, ok = FindTable(output, "interface")
+	}
+	if !ok {
+		return ports, nil
+	}
+	lag := t.Col("lag", "lag id", "trunk")
+	fwd := t.Col("forward-list", "forward list", "forward portlist", "forward-portlist", "forward ports", "forward")
+	var last PortID
+	for r, row := range t.Rows {
+		port := value(row, 0)
+		if port == "" && last != "" {
+			p := ports[last]
+			p.ForwardPorts = append(p.ForwardPorts, expandPorts(value(row, fwd))...)
+			ports[last] = p
+			continue
+		}
+		if ok, err := o.portRow(t, r, 0); !ok {
+			if err != nil {
+				return nil, err
+			}
+			last = ""
+			continue
+		}
+		p := PortIsolation{
+			Port:         portID(port),
+			ForwardPorts: expandPorts(value(row, fwd)),
+		}
+		if l := value(row, lag); l != "" && !strings.EqualFold(l, "n/a") && l != "-" {
+			p.LAG = l
+		}
+		ports[p.Port] = p
+		last = p.Port
+	}
+	return ports, nil
+}
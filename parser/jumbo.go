@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"strings"
+)
+
+// JumboFrame is the switch's frame size configuration.
+type JumboFrame struct {
+	MTU    int               `json:"mtu"` // global or system MTU in bytes, 0 if not shown
+	Ports  []JumboPort       `json:"ports,omitempty"`
+	Fields map[string]string `json:"fields"`
+}
+
+// JumboPort is the frame size setting of one port.
+type JumboPort struct {
+	Port    PortID `json:"port"`
+	Enabled bool   `json:"enabled"`       // jumbo frames accepted
+	MTU     int    `json:"mtu,omitempty"` // 0 if the port follows the global MTU
+}
+
+// standardMTU is the largest frame size, in bytes, that is not a jumbo
+// frame on the switches this package supports.
+const standardMTU = 1522
+
+// ParseJumboFrame parses "show jumbo-frame" or "show system mtu", and any
+// per-port jumbo table that follows.
+func ParseJumboFrame(output string, opts ...Option) (JumboFrame, error) {
+	o := newOptions(opts)
+	j := JumboFrame{Fields: make(map[string]string)}
+	for _, line := range splitLines(output) {
+		key, val, ok := cutField(line, ":")
+		if !ok {
+			continue
+		}
+		j.Fields[key] = val
+		k := strings.ToLower(key)
+		if strings.Contains(k, "mtu") || strings.Contains(k, "jumbo") {
+			if n, ok := parseNumber(val); ok && j.MTU == 0 {
+				j.MTU = int(n)
+			}
+		}
+	}
+	if j.MTU == 0 {
+		// "System MTU size is 9216 bytes"
+		for _, line := range splitLines(output) {
+			_, val, ok := strings.Cut(strings.ToLower(line), "mtu size is ")
+			if !ok {
+				continue
+			}
+			if n, ok := parseNumber(val); ok {
+				j.MTU = int(n)
+				break
+			}
+		}
+	}
+
+	t, ok := FindTable(output, "port")
+	if !ok {
+		t, ok = FindTable(output, "interface")
+	}
+	if !ok {
+		return j, nil
+	}
+	status := t.Col("jumbo", "jumbo frame", "jumbo-frame", "status", "state")
+	size := t.Col("mtu", "jumbo size", "jumbo-size", "frame size", "max frame size", "size")
+	for r := range t.Rows {
+		if ok, err := o.portRow(t, r, 0); !ok {
+			if err != nil {
+				return JumboFrame{}, err
+			}
+			continue
+		}
+		row := t.Rows[r]
+		rr := t.reader(r)
+		p := JumboPort{Port: portID(value(row, 0)), MTU: rr.int(size)}
+		if rr.err != nil {
+			if err := o.malformed(rr.err); err != nil {
+				return JumboFrame{}, err
+			}
+			continue
+		}
+		if status >= 0 {
+			p.Enabled = isEnabled(value(row, status))
+		} else {
+			p.Enabled = p.MTU > standardMTU
+		}
+		j.Ports = append(j.Ports, p)
+	}
+	return j, nil
+}
@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LAG is a link aggregation group (port-channel) and its members.
+type LAG struct {
+	ID       int         `json:"id"`
+	Name     string      `json:"name"`            // e.g. "Po1"
+	Flags    string      `json:"flags,omitempty"` // e.g. "SU"
+	Protocol string      `json:"protocol"`        // "LACP" or "-" for static
+	Members  []LAGMember `json:"members"`
+}
+
+// Up reports whether the port-channel is in use.
+func (l LAG) Up() bool {
+	return strings.Contains(l.Flags, "U")
+}
+
+// LAGMember is a port of a LAG.
+type LAGMember struct {
+	Port      PortID `json:"port"`
+	Flags     string `json:"flags,omitempty"` // e.g. "P"
+	State     string `json:"state"`           // "bundled", "down", "standalone", "suspended", "hot-standby"
+	Bundled   bool   `json:"bundled"`
+	Key       string `json:"key,omitempty"`        // LACP operational key
+	LACPState string `json:"lacp_state,omitempty"` // LACP port state bits as printed
+}
+
+// lagMemberStates names the member flags of the etherchannel summary legend.
+var lagMemberStates = map[string]string{
+	"P": "bundled",
+	"D": "down",
+	"I": "standalone",
+	"s": "suspended",
+	"H": "hot-standby",
+}
+
+// ParseLAGs parses "show etherchannel summary" and the per-port listing of
+// "show lacp internal", whose ports follow a "Channel group N" heading.
+func ParseLAGs(output string, opts ...Option) ([]LAG, error) {
+	o := newOptions(opts)
+	var lags []LAG
+	for _, t := range Tables(output) {
+		group := t.Col("group", "channel-group", "id")
+		ports := t.Col("ports", "member ports", "members")
+		if group < 0 || ports < 0 {
+			continue
+		}
+		channel := t.Col("port-channel", "port channel", "lag")
+		proto := t.Col("protocol", "mode")
+		for r, row := range t.Rows {
+			if value(row, group) == "" {
+				// Continuation of the previous group's member list.
+				if len(lags) > 0 {
+					lags[len(lags)-1].addMembers(value(row, ports))
+				}
+				continue
+			}
+			id, err := strconv.Atoi(value(row, group))
+			if err != nil {
+				if err := o.malformed(t.rowError(r, t.Headers[group], fmt.Errorf("invalid group %q", value(row, group)))); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			l := LAG{ID: id, Protocol: value(row, proto)}
+			if ch := markedPorts(value(row, channel)); len(ch) > 0 {
+				l.Name, l.Flags = string(ch[0].name), ch[0].marker
+			}
+			l.addMembers(value(row, ports))
+			lags = append(lags, l)
+		}
+	}
+	if len(lags) > 0 {
+		return lags, nil
+	}
+
+	var cur *LAG
+	for _, line := range splitLines(output) {
+		f := strings.Fields(line)
+		if len(f) == 3 && strings.EqualFold(f[0], "channel") && strings.EqualFold(f[1], "group") {
+			id, err := strconv.Atoi(f[2])
+			if err != nil {
+				continue
+			}
+			lags = append(lags, LAG{ID: id, Name: "Po" + f[2], Protocol: "LACP"})
+			cur = &lags[len(lags)-1]
+			continue
+		}
+		if cur == nil || len(f) < 2 || !isPortName(f[0]) {
+			continue
+		}
+		m := LAGMember{Port: portID(f[0]), Flags: f[1]}
+		if len(f) >= 5 {
+			m.Key = f[4]
+		}
+		if len(f) >= 7 {
+			m.LACPState = f[6]
+		}
+		// The actor state is synchronized, collecting and distributing
+		// (0x38) once the port is bundled.
+		if bits, err := strconv.ParseUint(strings.TrimPrefix(m.LACPState, "0x"), 16, 8); err == nil {
+			m.Bundled = bits&0x38 == 0x38
+			m.State = "standalone"
+			if m.Bundled {
+				m.State = "bundled"
+			}
+		}
+		cur.Members = append(cur.Members, m)
+	}
+	return lags, nil
+}
+
+// addMembers adds the ports of a summary member list such as
+// "Gi1/0/1(P) Gi1/0/2(D)".
+func (l *LAG) addMembers(list string) {
+	for _, p := range markedPorts(list) {
+		m := LAGMember{Port: p.name, Flags: p.marker}
+		for _, flag := range p.marker {
+			if state, ok := lagMemberStates[string(flag)]; ok {
+				m.State = state
+				break
+			}
+		}
+		m.Bundled = m.State == "bundled"
+		l.Members = append(l.Members, m)
+	}
+}
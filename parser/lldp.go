@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// LLDPNeighbor is a device seen by LLDP on a local port.
+type LLDPNeighbor struct {
+	LocalPort           PortID   `json:"local_port"`
+	ChassisIDSubtype    string   `json:"chassis_id_subtype,omitempty"`
+	ChassisID           string   `json:"chassis_id"`
+	PortIDSubtype       string   `json:"port_id_subtype,omitempty"`
+	PortID              string   `json:"port_id"`
+	PortDescription     string   `json:"port_description,omitempty"`
+	SystemName          string   `json:"system_name"`
+	SystemDescription   string   `json:"system_description,omitempty"`
+	Capabilities        []string `json:"capabilities"`                   // supported system capabilities
+	EnabledCapabilities []string `json:"enabled_capabilities,omitempty"` // capabilities in use
+	ManagementAddress   string   `json:"management_address"`
+	TTL                 int      `json:"ttl,omitempty"` // seconds
+}
+
+// lldpPortRegex matches the line naming the local port of the neighbors
+// that follow, e.g. "Gi1/0/1", "2/0/15" or "LLDP Neighbor Information of
+// port Gi1/0/1".
+var lldpPortRegex = regexp.MustCompile(`(?i)^(?:.*\bport\s*:?\s*)?((?:[A-Za-z][A-Za-z-]*)?\d+(?:/\d+)+)\s*:?$`)
+
+// ParseLLDPNeighbors parses "show lldp neighbor-information". Each local
+// port header is followed by one or more neighbors listed as "key: value"
+// lines, with "Neighbor N:" separating several neighbors on one port.
+func ParseLLDPNeighbors(output string, opts ...Option) ([]LLDPNeighbor, error) {
+	o := newOptions(opts)
+	var (
+		neighbors []LLDPNeighbor
+		port      string
+		cur       *LLDPNeighbor
+	)
+	next := func() *LLDPNeighbor {
+		neighbors = append(neighbors, LLDPNeighbor{LocalPort: portID(port)})
+		return &neighbors[len(neighbors)-1]
+	}
+	for i, line := range splitLines(output) {
+		line = strings.TrimSpace(line)
+		key, val, ok := cutField(line, ":")
+		key = strings.ToLower(key)
+		if ok && (key == "local port" || key == "local interface") {
+			port, cur = val, nil
+			continue
+		}
+		if m := lldpPortRegex.FindStringSubmatch(line); m != nil && (!ok || val == "") {
+			port, cur = m[1], nil
+			continue
+		}
+		if port == "" || !ok {
+			continue
+		}
+		if strings.HasPrefix(key, "neighbor") && val == "" {
+			cur = next()
+			continue
+		}
+		if cur == nil {
+			cur = next()
+		}
+		switch key {
+		case "chassis id subtype", "chassis type":
+			cur.ChassisIDSubtype = val
+		case "chassis id":
+			cur.ChassisID = val
+		case "port id subtype", "port id type":
+			cur.PortIDSubtype = val
+		case "port id":
+			cur.PortID = val
+		case "port description":
+			cur.PortDescription = val
+		case "system name":
+			cur.SystemName = val
+		case "system description":
+			cur.SystemDescription = val
+		case "system capabilities", "system capabilities supported", "capabilities":
+			cur.Capabilities = splitList(val)
+		case "enabled capabilities", "system capabilities enabled":
+			cur.EnabledCapabilities = splitList(val)
+		case "management address":
+			if cur.ManagementAddress == "" {
+				cur.ManagementAddress = val
+			}
+		case "time to live", "ttl":
+			n, err := parseInt(val)
+			if err != nil {
+				if err := o.malformed(lineError(i, line, key, err)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			cur.TTL = n
+		}
+	}
+	return neighbors, nil
+}
+
+// splitList splits a comma separated list, dropping empty items.
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogEntry is one message from the switch's log buffer.
+type LogEntry struct {
+	Index     int       `json:"index,omitempty"`
+	Timestamp string    `json:"timestamp"`     // as printed by the switch
+	Time      time.Time `json:"time,omitzero"` // Timestamp parsed, if it holds a date
+	Severity  int       `json:"severity"`      // syslog level, 0 (emergency) to 7 (debug), -1 if unknown
+	Module    string    `json:"module"`
+	Mnemonic  string    `json:"mnemonic,omitempty"` // e.g. "LOGIN" in "SYSTEM-5-LOGIN"
+	Message   string    `json:"message"`
+}
+
+// SeverityName returns the syslog name of the entry's severity.
+func (e LogEntry) SeverityName() string {
+	if e.Severity < 0 || e.Severity >= len(severityNames) {
+		return ""
+	}
+	return severityNames[e.Severity]
+}
+
+// severityNames are the syslog severity names, indexed by level.
+var severityNames = []string{"emergencies", "alerts", "critical", "errors", "warnings", "notifications", "informational", "debugging"}
+
+// logTimeLayouts are the timestamp formats tried for LogEntry.Time.
+var logTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"Jan 2 15:04:05 2006",
+	"Jan 2 2006 15:04:05",
+	"01/02/2006 15:04:05",
+}
+
+// logLineRegex matches an untabulated log line such as
+// "#1 2024-05-01 12:00:01 <5> SYSTEM-5-LOGIN: User admin logged in".
+var logLineRegex = regexp.MustCompile(`^#?(\d+)?\s*((?:\d{4}-\d\d-\d\d[ T]\d\d:\d\d:\d\d)|(?:[A-Z][a-z]{2}\s+\d+\s+(?:\d\d:\d\d:\d\d\s+\d{4}|\d{4}\s+\d\d:\d\d:\d\d))|(?:\d+[dD]\s*\d\d:\d\d:\d\d)|(?:\d\d:\d\d:\d\d))\s+(?:<(\d)>\s*)?([A-Za-z_][\w.]*)(?:-(\d)-([\w]+))?\s*:\s*(.*)$`)
+
+// ParseLogBuffer parses "show logging buffer", either as a table with
+// index, time, module, severity and content columns or as one
+// "time MODULE-severity-MNEMONIC: message" line per entry.
+func ParseLogBuffer(output string, opts ...Option) ([]LogEntry, error) {
+	o := newOptions(opts)
+	var entries []LogEntry
+	for _, t := range Tables(output) {
+		msg := t.Col("content", "message", "description", "msg")
+		when := t.Col("time", "timestamp", "date", "date/time")
+		if msg < 0 || when < 0 {
+			continue
+		}
+		idx := t.Col("index", "no.", "#", "id")
+		mod := t.Col("module", "facility", "source")
+		sev := t.Col("severity", "level", "sev")
+		for r, row := range t.Rows {
+			if value(row, when) == "" {
+				// A wrapped message continues on the next line.
+				if n := len(entries); n > 0 && value(row, msg) != "" {
+					entries[n-1].Message += " " + value(row, msg)
+				}
+				continue
+			}
+			rr := t.reader(r)
+			e := LogEntry{
+				Index:     rr.int(idx),
+				Timestamp: value(row, when),
+				Module:    value(row, mod),
+				Severity:  parseSeverity(value(row, sev)),
+				Message:   value(row, msg),
+			}
+			if s := value(row, sev); e.Severity < 0 && !isPlaceholder(s) {
+				rr.fail(sev, fmt.Errorf("unknown severity %q", s))
+			}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			e.Time = parseLogTime(e.Timestamp)
+			entries = append(entries, e)
+		}
+	}
+	if len(entries) > 0 {
+		return entries, nil
+	}
+
+	for _, line := range splitLines(output) {
+		line = strings.TrimSpace(line)
+		m := logLineRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		e := LogEntry{Timestamp: m[2], Module: m[4], Mnemonic: m[6], Message: m[7], Severity: -1}
+		e.Index, _ = strconv.Atoi(m[1])
+		switch {
+		case m[5] != "":
+			e.Severity, _ = strconv.Atoi(m[5])
+		case m[3] != "":
+			e.Severity, _ = strconv.Atoi(m[3])
+		}
+		e.Time = parseLogTime(e.Timestamp)
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// parseSeverity parses a severity such as "5", "level_5", "<5>" or "warnings".
+func parseSeverity(s string) int {
+	s = strings.ToLower(strings.Trim(s, "<>() "))
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "level_"), "level ")
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 7 {
+		return n
+	}
+	if s == "notice" {
+		return 5
+	}
+	for i, name := range severityNames {
+		if s != "" && strings.HasPrefix(name, s) {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseLogTime parses an absolute log timestamp, or returns the zero time
+// for uptime stamps such as "1d 02:03:04".
+func parseLogTime(s string) time.Time {
+	s = strings.Join(strings.Fields(s), " ")
+	for _, layout := range logTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
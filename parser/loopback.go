@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"strings"
+)
+
+// LoopbackDetection is the loopback detection configuration and state.
+type LoopbackDetection struct {
+	Enabled         bool              `json:"enabled"`
+	IntervalSeconds int               `json:"interval_seconds,omitempty"`
+	RecoverySeconds int               `json:"recovery_seconds,omitempty"` // automatic recovery interval
+	Ports           []LoopbackPort    `json:"ports"`
+	Fields          map[string]string `json:"fields"`
+}
+
+// Blocked returns the ports blocked because a loop was detected.
+func (l LoopbackDetection) Blocked() []LoopbackPort {
+	var out []LoopbackPort
+	for _, p := range l.Ports {
+		if p.Blocked {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// LoopbackPort is the loopback detection state of one port.
+type LoopbackPort struct {
+	Port         PortID `json:"port"`
+	Enabled      bool   `json:"enabled"`
+	ProcessMode  string `json:"process_mode,omitempty"`  // "Alert", "Port Based" or "VLAN Based"
+	RecoveryMode string `json:"recovery_mode,omitempty"` // "Auto" or "Manual"
+	LoopStatus   string `json:"loop_status"`             // e.g. "Normal", "Loop"
+	BlockStatus  string `json:"block_status"`            // e.g. "Normal", "Blocked"
+	Looped       bool   `json:"looped"`
+	Blocked      bool   `json:"blocked"`
+}
+
+// ParseLoopbackDetection parses "show loopback-detection global" and
+// "show loopback-detection interface", alone or concatenated.
+func ParseLoopbackDetection(output string, opts ...Option) (LoopbackDetection, error) {
+	o := newOptions(opts)
+	l := LoopbackDetection{Fields: make(map[string]string)}
+	for i, line := range splitLines(output) {
+		key, val, ok := cutField(line, ":")
+		if !ok {
+			continue
+		}
+		l.Fields[key] = val
+		k := strings.TrimPrefix(strings.ToLower(key), "loopback detection ")
+		var num *int // field of l taking a number
+		switch k {
+		case "status", "state", "global state", "loopback detection":
+			l.Enabled = isEnabled(val)
+		case "interval", "detection interval", "interval(s)":
+			num = &l.IntervalSeconds
+		case "recovery time", "automatic recovery time", "recovery time(s)", "recovery":
+			num = &l.RecoverySeconds
+		}
+		if num == nil {
+			continue
+		}
+		n, err := parseInt(val)
+		if err != nil {
+			if err := o.malformed(lineError(i, strings.TrimSpace(line), key, err)); err != nil {
+				return LoopbackDetection{}, err
+			}
+			continue
+		}
+		*num = n
+	}
+
+	t, ok := FindTable(output, "port")
+	if !ok {
+		t, ok = FindTable(output, "interface")
+	}
+	if !ok {
+		return l, nil
+	}
+	status := t.Col("status", "state", "loopback detection")
+	process := t.Col("process mode", "process-mode", "mode")
+	recovery := t.Col("recovery mode", "recovery-mode")
+	loop := t.Col("loop status", "loop-status", "loop")
+	block := t.Col("block status", "block-status", "blocked")
+	for r, row := range t.Rows {
+		if ok, err := o.portRow(t, r, 0); !ok {
+			if err != nil {
+				return LoopbackDetection{}, err
+			}
+			continue
+		}
+		p := LoopbackPort{
+			Port:         portID(value(row, 0)),
+			Enabled:      isEnabled(value(row, status)),
+			ProcessMode:  value(row, process),
+			RecoveryMode: value(row, recovery),
+			LoopStatus:   value(row, loop),
+			BlockStatus:  value(row, block),
+		}
+		p.Looped = strings.Contains(strings.ToLower(p.LoopStatus), "loop")
+		p.Blocked = strings.Contains(strings.ToLower(p.BlockStatus), "block") &&
+			!strings.Contains(strings.ToLower(p.BlockStatus), "unblock")
+		l.Ports = append(l.Ports, p)
+	}
+	return l, nil
+}
@@ -0,0 +1,155 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MACTableCount is the MAC address table usage from "show mac
+// address-table count" and its aging time.
+type MACTableCount struct {
+	Total        int               `json:"total"`
+	Dynamic      int               `json:"dynamic"`
+	Static       int               `json:"static"`
+	Filter       int               `json:"filter,omitempty"`        // blackhole entries
+	Available    int               `json:"available,omitempty"`     // free entries, 0 if not reported
+	Capacity     int               `json:"capacity,omitempty"`      // table size, 0 if not reported
+	AgingSeconds int               `json:"aging_seconds,omitempty"` // 0 if not reported or aging is disabled
+	VLANs        []MACVLANCount    `json:"vlans"`                   // per-VLAN counts, where reported
+	Fields       map[string]string `json:"fields"`                  // lines outside a VLAN block
+}
+
+// MACVLANCount is the number of MAC addresses learned in one VLAN.
+type MACVLANCount struct {
+	VLAN    int `json:"vlan"`
+	Total   int `json:"total"`
+	Dynamic int `json:"dynamic,omitempty"`
+	Static  int `json:"static,omitempty"`
+}
+
+// UsedPercent returns the share of the table in use, from Capacity or,
+// failing that, from Total and Available; 0 if neither is known.
+func (c MACTableCount) UsedPercent() float64 {
+	capacity := c.Capacity
+	if capacity <= 0 && c.Available > 0 {
+		capacity = c.Total + c.Available
+	}
+	if capacity <= 0 {
+		return 0
+	}
+	return float64(c.Total) / float64(capacity) * 100
+}
+
+// ParseMACTableCount parses "show mac address-table count", with or
+// without a VLAN, and "show mac address-table aging-time", alone or
+// concatenated. Per-VLAN counts come from a table with VLAN and count
+// columns or from "VLAN ID:" blocks.
+func ParseMACTableCount(output string, opts ...Option) (MACTableCount, error) {
+	o := newOptions(opts)
+	c := MACTableCount{Fields: make(map[string]string)}
+	vlans := make(map[int]*MACVLANCount)
+	vlan := func(id int) *MACVLANCount {
+		if vlans[id] == nil {
+			vlans[id] = &MACVLANCount{VLAN: id}
+		}
+		return vlans[id]
+	}
+
+	var cur *MACVLANCount
+	for i, line := range splitLines(output) {
+		trimmed := strings.TrimSpace(line)
+		lower := strings.ToLower(trimmed)
+		if rest, ok := strings.CutPrefix(lower, "aging time is"); ok {
+			if n, ok := parseNumber(rest); ok {
+				c.AgingSeconds = int(n)
+			}
+			continue
+		}
+		key, val, ok := cutField(trimmed, ":")
+		if !ok {
+			continue
+		}
+		k := strings.ToLower(key)
+		n, isNum := parseNumber(val)
+		if k == "vlan" || k == "vlan id" {
+			cur = nil
+			if isNum {
+				cur = vlan(int(n))
+			} else if err := o.malformed(lineError(i, trimmed, key, fmt.Errorf("invalid VLAN %q", val))); err != nil {
+				return MACTableCount{}, err
+			}
+			continue
+		}
+		if cur == nil {
+			c.Fields[key] = val
+		}
+		if !isNum {
+			continue
+		}
+		switch {
+		case strings.Contains(k, "aging"):
+			c.AgingSeconds = int(n)
+		case strings.Contains(k, "available") || strings.Contains(k, "free") || strings.Contains(k, "remain"):
+			c.Available = int(n)
+		case strings.Contains(k, "max") || strings.Contains(k, "capacity") || strings.Contains(k, "size"):
+			c.Capacity = int(n)
+		case strings.Contains(k, "dynamic"):
+			if cur != nil {
+				cur.Dynamic = int(n)
+			} else {
+				c.Dynamic = int(n)
+			}
+		case strings.Contains(k, "static"):
+			if cur != nil {
+				cur.Static = int(n)
+			} else {
+				c.Static = int(n)
+			}
+		case strings.Contains(k, "filter") || strings.Contains(k, "blackhole"):
+			c.Filter = int(n)
+		case strings.Contains(k, "total") || strings.Contains(k, "count"):
+			if cur != nil {
+				cur.Total = int(n)
+			} else {
+				c.Total = int(n)
+			}
+		}
+	}
+
+	for _, t := range Tables(output) {
+		id := t.Col("vlan", "vlan id", "vid")
+		total := t.Col("count", "total", "mac count", "address count", "total count", "entries")
+		if id < 0 || total < 0 {
+			continue
+		}
+		dynamic := t.Col("dynamic", "dynamic count")
+		static := t.Col("static", "static count")
+		for r, row := range t.Rows {
+			if value(row, id) == "" {
+				continue
+			}
+			rr := t.reader(r)
+			n := MACVLANCount{VLAN: rr.int(id), Total: rr.int(total), Dynamic: rr.int(dynamic), Static: rr.int(static)}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return MACTableCount{}, err
+				}
+				continue
+			}
+			*vlan(n.VLAN) = n
+		}
+	}
+
+	for _, v := range vlans {
+		if v.Total == 0 {
+			v.Total = v.Dynamic + v.Static
+		}
+		c.VLANs = append(c.VLANs, *v)
+	}
+	sort.Slice(c.VLANs, func(i, j int) bool { return c.VLANs[i].VLAN < c.VLANs[j].VLAN })
+	if c.Total == 0 && c.Dynamic+c.Static+c.Filter > 0 {
+		c.Total = c.Dynamic + c.Static + c.Filter
+	}
+	return c, nil
+}
@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+)
+
+// MACEntry is an entry of the MAC address table.
+type MACEntry struct {
+	MAC   string `json:"mac" table:"mac address,mac,mac addr,required"`
+	VLAN  int    `json:"vlan" table:"vlan,vlan id,vid"`
+	Port  PortID `json:"port" table:"port,interface,ports"`
+	Type  string `json:"type,omitempty" table:"type"`                // e.g. "dynamic", "static", "config"
+	Aging string `json:"aging,omitempty" table:"aging,aging status"` // e.g. "Aging", "No-Aging"
+}
+
+// ParseMACTable parses "show mac address-table" and its variants such as
+// "all", "vlan" and "interface". The trailing count line is ignored, and so
+// are rows without a MAC address; a row whose MAC address does not parse is
+// malformed.
+func ParseMACTable(output string, opts ...Option) ([]MACEntry, error) {
+	var entries []MACEntry
+	for _, t := range Tables(output) {
+		if err := t.Decode(&entries, opts...); err != nil {
+			return nil, err
+		}
+	}
+	return slices.DeleteFunc(entries, func(e MACEntry) bool { return e.MAC == "" }), nil
+}
+
+func (e *MACEntry) validate() error {
+	if e.MAC != "" && !isMAC(e.MAC) {
+		return fmt.Errorf("invalid MAC address %q", e.MAC)
+	}
+	return nil
+}
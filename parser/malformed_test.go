@@ -0,0 +1,273 @@
+package parser_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/pascal71/tplink-go/parser"
+)
+
+// malformedCases hold one good and one malformed row or field each.
+var malformedCases = []struct {
+	name    string
+	command string
+	output  string
+	line    int             // line of the malformed row
+	field   string          // field reported for it
+	kept    func(v any) int // entries parsed in lenient mode; the length of v if nil
+	want    int             // what kept must return
+}{
+	{
+		name:    "mac table vlan",
+		command: "show mac address-table",
+		output: `MAC Address         VLAN    Port      Type      Aging
+-----------------   ----    -------   -------   -----
+00:0a:eb:13:a2:01   1       Gi1/0/1   dynamic   Aging
+00:0a:eb:13:a2:02   ten     Gi1/0/2   dynamic   Aging
+Total MAC Addresses for this criterion: 2
+`,
+		line:  4,
+		field: "vlan",
+		want:  1,
+	},
+	{
+		name:    "mac table address",
+		command: "show mac address-table",
+		output: `MAC Address         VLAN    Port      Type      Aging
+-----------------   ----    -------   -------   -----
+00:0a:eb:13:a2:01   1       Gi1/0/1   dynamic   Aging
+00:0a:eb:13:zz:02   1       Gi1/0/2   dynamic   Aging
+`,
+		line: 4,
+		want: 1,
+	},
+	{
+		name:    "arp address",
+		command: "show arp",
+		output: `Interface   IP Address       MAC Address         Type
+---------   --------------   -----------------   -------
+VLAN1       192.168.0.1      00:11:22:33:44:66   Static
+VLAN10      192.168.10.300   00:11:22:33:44:55   Dynamic
+`,
+		line: 4,
+		want: 1,
+	},
+	{
+		name:    "interface status port",
+		command: "show interface status",
+		output: `Port      Status    Speed     Duplex    FlowCtrl  Active-Medium
+-------   ------    -------   ------    --------  -------------
+Gi1/0/1   LinkUp    1000M     Full      Disable   Copper
+garbage   LinkDown  Auto      Auto      Enable    Copper
+`,
+		line: 4,
+		want: 1,
+	},
+	{
+		name:    "radius port",
+		command: "show radius-server",
+		output: `Server Ip        Auth Port  Acct Port  Timeout  Retransmit  Priority  Key
+---------------  ---------  ---------  -------  ----------  --------  ---
+10.0.0.5         1812       1813       5        2           1         ******
+10.0.0.6         radius     1813       5        2           2         ******
+`,
+		line:  4,
+		field: "auth port",
+		want:  1,
+	},
+	{
+		name:    "storm control rate",
+		command: "show storm-control",
+		output: `Port      Rate Mode  BC-Rate   MC-Rate   UL-Rate
+-------   ---------  -------   -------   -------
+Gi1/0/1   kbps       1000      Disable   Disable
+Gi1/0/2   kbps       lots      Disable   Disable
+`,
+		line:  4,
+		field: "bc-rate",
+		want:  1,
+	},
+	{
+		name:    "loopback interval",
+		command: "show loopback-detection",
+		output: `Loopback Detection Status: Enable
+Interval: often
+Recovery Time: 3
+`,
+		line:  2,
+		field: "Interval",
+		kept:  func(v any) int { return v.(parser.LoopbackDetection).RecoverySeconds },
+		want:  3,
+	},
+	{
+		name:    "lldp ttl",
+		command: "show lldp neighbor-information",
+		output: `LLDP Neighbor Information of port Gi1/0/1
+  Chassis ID: 00:11:22:33:44:55
+  System Name: core1
+  Time To Live: forever and ever
+`,
+		line:  4,
+		field: "time to live",
+		kept:  func(v any) int { return len(v.([]parser.LLDPNeighbor)) },
+		want:  1,
+	},
+	{
+		name:    "igmp group",
+		command: "show ip igmp snooping groups",
+		output: `VLAN   Multicast IP     Forward Ports
+----   --------------   -------------
+10     239.1.1.1        Gi1/0/1
+10     not-an-address   Gi1/0/2
+`,
+		line:  4,
+		field: "multicast ip",
+		want:  1,
+	},
+	{
+		name:    "stack priority",
+		command: "show stack",
+		output: `Unit  Role    MAC Address         Priority  Status
+----  ------  -----------------   --------  ------
+1*    Master  00:11:22:33:44:55   15        Ready
+2     Member  00:11:22:33:44:66   high      Ready
+`,
+		line:  4,
+		field: "priority",
+		kept:  func(v any) int { return len(v.(parser.StackInfo).Units) },
+		want:  1,
+	},
+	{
+		name:    "system mac address",
+		command: "show system-info",
+		output: ` System Name            - core1
+ Mac Address            - 00-11-22-33-44
+ Running Time           - 5 day - 3 hour - 22 min - 6 sec
+`,
+		line:  2,
+		field: "mac address",
+		kept:  func(v any) int { return len(v.(parser.SystemInfo).MACAddress) },
+		want:  0,
+	},
+	{
+		name:    "system running time",
+		command: "show system-info",
+		output: ` System Name            - core1
+ Mac Address            - 00-11-22-33-44-55
+ Running Time           - since Tuesday
+`,
+		line:  3,
+		field: "running time",
+		kept:  func(v any) int { return len(v.(parser.SystemInfo).MACAddress) },
+		want:  len("00-11-22-33-44-55"),
+	},
+	{
+		name:    "user idle",
+		command: "show users",
+		output: `User      Type    IP Address     Idle
+-------   -----   ------------   --------
+admin     SSH     10.0.0.9       00:01:12
+guest     SSH     10.0.0.10      a while
+`,
+		line:  4,
+		field: "idle",
+		want:  1,
+	},
+}
+
+func TestMalformedStrict(t *testing.T) {
+	for _, tc := range malformedCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parser.Parse(tc.command, tc.output)
+			var pe *parser.ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("error = %v, want a *ParseError", err)
+			}
+			if pe.Line != tc.line {
+				t.Errorf("Line = %d, want %d", pe.Line, tc.line)
+			}
+			if tc.field != "" && pe.Field != tc.field {
+				t.Errorf("Field = %q, want %q", pe.Field, tc.field)
+			}
+			if pe.Text == "" {
+				t.Error("Text is empty")
+			}
+			if pe.Command != tc.command {
+				t.Errorf("Command = %q, want %q", pe.Command, tc.command)
+			}
+		})
+	}
+}
+
+func TestMalformedLenient(t *testing.T) {
+	for _, tc := range malformedCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var warnings []parser.Warning
+			v, err := parser.Parse(tc.command, tc.output, parser.Lenient(&warnings))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(warnings) != 1 {
+				t.Fatalf("warnings = %v, want one", warnings)
+			}
+			if warnings[0].Line != tc.line {
+				t.Errorf("warning line = %d, want %d", warnings[0].Line, tc.line)
+			}
+			kept := tc.kept
+			if kept == nil {
+				kept = func(v any) int { return reflect.ValueOf(v).Len() }
+			}
+			if n := kept(v); n != tc.want {
+				t.Errorf("kept %d, want %d", n, tc.want)
+			}
+		})
+	}
+}
+
+func TestPlaceholdersAreNotMalformed(t *testing.T) {
+	out := `MAC Address         VLAN    Port      Type      Aging
+-----------------   ----    -------   -------   -----
+00:0a:eb:13:a2:01   --      Gi1/0/1   dynamic   Aging
+00:0a:eb:13:a2:02   N/A     Gi1/0/2   static    No-Aging
+`
+	entries, err := parser.ParseMACTable(out)
+	if err != nil {
+		t.Fatalf("ParseMACTable: %v", err)
+	}
+	if len(entries) != 2 || entries[0].VLAN != 0 || entries[1].VLAN != 0 {
+		t.Errorf("entries = %+v, want two with VLAN 0", entries)
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	type row struct {
+		Name  string `table:"name,required"`
+		Count int    `table:"count"`
+	}
+	tables := parser.Tables(`Name    Count
+-----   -----
+one     1
+two     many
+three   3
+`)
+	if len(tables) != 1 {
+		t.Fatalf("got %d tables, want 1", len(tables))
+	}
+
+	var rows []row
+	err := tables[0].Decode(&rows)
+	var pe *parser.ParseError
+	if !errors.As(err, &pe) || pe.Line != 4 || pe.Field != "count" {
+		t.Fatalf("Decode error = %v, want a *ParseError for line 4 (count)", err)
+	}
+
+	rows = nil
+	var warnings []parser.Warning
+	if err := tables[0].Decode(&rows, parser.Lenient(&warnings)); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(rows) != 2 || rows[1].Name != "three" || len(warnings) != 1 {
+		t.Errorf("rows = %+v, warnings = %v; want one and three, and one warning", rows, warnings)
+	}
+}
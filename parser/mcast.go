@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"errors"
+	"net"
+)
+
+// MulticastEntry is an entry of the multicast forwarding table.
+type MulticastEntry struct {
+	VLAN  int      `json:"vlan,omitempty"`
+	MAC   string   `json:"mac,omitempty"`   // group MAC address, if printed
+	Group string   `json:"group,omitempty"` // group IP address, if printed
+	Type  string   `json:"type,omitempty"`  // type or status as printed, e.g. "Dynamic", "Active"
+	Ports []PortID `json:"ports"`           // egress ports
+}
+
+// errNoGroup is the reason for a malformed multicast row without a group
+// MAC or IP address.
+var errNoGroup = errors.New("no group address")
+
+// ParseMulticastTable parses "show mac address-table multicast" and the
+// MVR group tables ("show mvr members"). The group column may hold a MAC or
+// an IP address; egress port lists wrapped onto following lines are joined.
+func ParseMulticastTable(output string, opts ...Option) ([]MulticastEntry, error) {
+	o := newOptions(opts)
+	var entries []MulticastEntry
+	for _, t := range Tables(output) {
+		mac := t.Col("mac address", "mac", "multicast mac", "group mac", "mac addr")
+		group := t.Col("multicast ip", "group", "group ip", "mvr group ip", "group address", "ip address", "multicast group")
+		ports := t.Col("ports", "port", "egress ports", "member ports", "members", "forward ports", "interface")
+		if mac < 0 && group < 0 || ports < 0 {
+			continue
+		}
+		vlan := t.Col("vlan", "vlan id", "vid", "mvr vlan")
+		typ := t.Col("type", "status", "mode")
+		for r, row := range t.Rows {
+			if value(row, mac) == "" && value(row, group) == "" {
+				if n := len(entries); n > 0 {
+					entries[n-1].Ports = append(entries[n-1].Ports, expandPorts(value(row, ports))...)
+				}
+				continue
+			}
+			rr := t.reader(r)
+			e := MulticastEntry{
+				VLAN:  rr.int(vlan),
+				Type:  value(row, typ),
+				Ports: expandPorts(value(row, ports)),
+			}
+			for _, s := range []string{value(row, mac), value(row, group)} {
+				switch {
+				case isMAC(s):
+					e.MAC = s
+				case net.ParseIP(s) != nil:
+					e.Group = s
+				}
+			}
+			if e.MAC == "" && e.Group == "" {
+				rr.fail(max(mac, group), errNoGroup)
+			}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MirrorSession is a port mirroring (monitor) session.
+type MirrorSession struct {
+	ID          int            `json:"id"`
+	Destination string         `json:"destination"` // monitor port, "" if unset
+	Sources     []MirrorSource `json:"sources"`
+}
+
+// Configured reports whether the session has a destination and a source.
+func (m MirrorSession) Configured() bool {
+	return m.Destination != "" && len(m.Sources) > 0
+}
+
+// MirrorSource is a mirrored port and the traffic direction copied.
+type MirrorSource struct {
+	Port      PortID `json:"port"`
+	Direction string `json:"direction"` // "rx", "tx" or "both"
+}
+
+// ParseMirrorSessions parses "show monitor session". Each session starts
+// with a "Monitor Session: 1" or "Session 1" line followed by "key: value"
+// lines; source ports are keyed by direction, e.g. "Source Ports(Ingress)"
+// or an indented "Both:" under "Source Ports:".
+func ParseMirrorSessions(output string, opts ...Option) ([]MirrorSession, error) {
+	o := newOptions(opts)
+	var (
+		sessions []MirrorSession
+		cur      *MirrorSession
+		inSource bool // within a "Source Ports:" group of direction lines
+	)
+	for i, line := range splitLines(output) {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || isSeparator(trimmed) {
+			continue
+		}
+		key, val, hasColon := cutField(trimmed, ":")
+		lower := strings.ToLower(key)
+		if id, ok := mirrorSessionID(lower, val, hasColon); ok {
+			sessions = append(sessions, MirrorSession{ID: id})
+			cur, inSource = &sessions[len(sessions)-1], false
+			continue
+		}
+		if cur == nil || !hasColon {
+			continue
+		}
+		if isMirrorPortKey(lower, inSource) {
+			if err := checkPortList(val); err != nil {
+				if err := o.malformed(lineError(i, trimmed, key, err)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		switch {
+		case strings.HasPrefix(lower, "destination") || strings.HasPrefix(lower, "monitor port") || strings.HasPrefix(lower, "analysis port"):
+			var dst []string
+			for _, p := range expandPorts(val) {
+				dst = append(dst, string(p))
+			}
+			cur.Destination = strings.Join(dst, ",")
+			inSource = false
+		case strings.HasPrefix(lower, "source") || strings.HasPrefix(lower, "mirrored port"):
+			inSource = true
+			if dir := mirrorDirection(lower); dir != "" {
+				cur.addSources(val, dir)
+			} else if val != "" {
+				cur.addSources(val, "both")
+			}
+		case inSource && mirrorDirection(lower) != "":
+			cur.addSources(val, mirrorDirection(lower))
+		default:
+			inSource = false
+		}
+	}
+	return sessions, nil
+}
+
+// mirrorSessionID parses a session heading such as "Monitor Session: 1".
+func mirrorSessionID(key, val string, hasColon bool) (int, bool) {
+	if !strings.HasPrefix(key, "session") && !strings.HasPrefix(key, "monitor session") {
+		return 0, false
+	}
+	s := val
+	if !hasColon {
+		s = lastWord(key)
+	}
+	id, err := strconv.Atoi(s)
+	return id, err == nil
+}
+
+// isMirrorPortKey reports whether the value of key is a port list.
+func isMirrorPortKey(key string, inSource bool) bool {
+	for _, prefix := range []string{"destination", "monitor port", "analysis port", "source", "mirrored port"} {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return inSource && mirrorDirection(key) != ""
+}
+
+// mirrorDirection returns the direction named in a source key, or "".
+func mirrorDirection(key string) string {
+	switch {
+	case strings.Contains(key, "both") || strings.Contains(key, "ingress/egress"):
+		return "both"
+	case strings.Contains(key, "ingress") || strings.Contains(key, "rx"):
+		return "rx"
+	case strings.Contains(key, "egress") || strings.Contains(key, "tx"):
+		return "tx"
+	}
+	return ""
+}
+
+// addSources adds the ports of list as sources mirrored in dir.
+func (m *MirrorSession) addSources(list, dir string) {
+	for _, p := range expandPorts(list) {
+		if strings.EqualFold(string(p), "none") || p == "-" {
+			continue
+		}
+		m.Sources = append(m.Sources, MirrorSource{Port: p, Direction: dir})
+	}
+}
@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+)
+
+// MVR is the Multicast VLAN Registration configuration.
+type MVR struct {
+	Enabled   bool              `json:"enabled"`
+	VLAN      int               `json:"vlan,omitempty"`       // multicast VLAN
+	Mode      string            `json:"mode,omitempty"`       // "Compatible" or "Dynamic"
+	MaxGroups int               `json:"max_groups,omitempty"` // 0 if not reported
+	Groups    []MVRGroupRange   `json:"groups"`
+	Ports     []MVRPort         `json:"ports"`
+	Fields    map[string]string `json:"fields"`
+}
+
+// MVRGroupRange is a range of multicast group addresses carried on the MVR
+// VLAN. End is empty for a single group.
+type MVRGroupRange struct {
+	Start  string `json:"start" table:"start ip,group start,start address,start*,mvr group ip,group ip,group address,group,required"`
+	End    string `json:"end,omitempty" table:"end ip,group end,end address,end*"`
+	Count  int    `json:"count,omitempty" table:"count,group count,contiguous count,number"` // number of groups, if printed
+	Status string `json:"status,omitempty" table:"status,state"`
+}
+
+// MVRPort is the MVR role of one port.
+type MVRPort struct {
+	Port           PortID `json:"port" table:"port,interface,required"`
+	Enabled        bool   `json:"enabled" table:"mode,mvr mode,mvr,enable"`
+	Role           string `json:"role" table:"type,role,port type"` // "Source", "Receiver" or "None"
+	Status         string `json:"status,omitempty" table:"status,state"`
+	ImmediateLeave bool   `json:"immediate_leave" table:"immediate leave,immediate-leave,immediate*"`
+}
+
+// ParseMVR parses "show mvr", "show mvr interface" and the group ranges of
+// "show mvr group", alone or concatenated. Group ranges may also be given as
+// "MVR Group: 239.1.1.1 - 239.1.1.10" lines.
+func ParseMVR(output string, opts ...Option) (MVR, error) {
+	o := newOptions(opts)
+	m := MVR{Fields: make(map[string]string)}
+	for i, line := range splitLines(output) {
+		if f := strings.Fields(line); len(f) > 0 && (isPortName(f[0]) || net.ParseIP(f[0]) != nil) {
+			continue // a port or group table row
+		}
+		line = strings.TrimSpace(line)
+		key, val, ok := cutField(line, ":")
+		if !ok {
+			continue
+		}
+		m.Fields[key] = val
+		var (
+			num *int  // field of m taking a number
+			err error // set if val does not parse
+		)
+		switch k := strings.TrimPrefix(strings.ToLower(key), "mvr "); {
+		case k == "status" || k == "state" || k == "mvr" || k == "global status":
+			m.Enabled = isEnabled(val)
+		case k == "vlan" || k == "vlan id" || k == "multicast vlan":
+			num = &m.VLAN
+		case k == "mode":
+			m.Mode = val
+		case strings.HasPrefix(k, "max") && strings.Contains(k, "group"):
+			num = &m.MaxGroups
+		case k == "group" || k == "group ip" || k == "group range" || k == "group address":
+			start, end, _ := strings.Cut(val, "-")
+			if ip := strings.TrimSpace(start); net.ParseIP(ip) != nil {
+				m.Groups = append(m.Groups, MVRGroupRange{Start: ip, End: strings.TrimSpace(end)})
+			} else if !isPlaceholder(val) {
+				err = fmt.Errorf("invalid group range %q", val)
+			}
+		}
+		if num != nil {
+			*num, err = parseInt(val)
+		}
+		if err != nil {
+			if err := o.malformed(lineError(i, line, key, err)); err != nil {
+				return MVR{}, err
+			}
+		}
+	}
+
+	for _, t := range Tables(output) {
+		if err := t.Decode(&m.Groups, opts...); err != nil {
+			return MVR{}, err
+		}
+		if err := t.Decode(&m.Ports, opts...); err != nil {
+			return MVR{}, err
+		}
+	}
+	m.Groups = slices.DeleteFunc(m.Groups, func(g MVRGroupRange) bool { return g.Start == "" })
+	m.Ports = slices.DeleteFunc(m.Ports, func(p MVRPort) bool { return p.Port == "" })
+	return m, nil
+}
+
+func (g *MVRGroupRange) validate() error {
+	if g.Start != "" && net.ParseIP(g.Start) == nil {
+		return fmt.Errorf("invalid group address %q", g.Start)
+	}
+	return nil
+}
+
+func (p *MVRPort) validate() error {
+	return checkPort(p.Port)
+}
@@ -0,0 +1,65 @@
+package parser
+
+import "errors"
+
+// Option configures how a parser treats malformed output.
+type Option func(*options)
+
+type options struct {
+	lenient  bool
+	warnings *[]Warning
+}
+
+// Warning describes a malformed line skipped in lenient mode.
+type Warning struct {
+	Line   int    `json:"line"`            // 1-based line number in the output, 0 if unknown
+	Text   string `json:"text"`            // the offending line
+	Field  string `json:"field,omitempty"` // field or column that failed to parse, if known
+	Reason string `json:"reason"`          // what was wrong with it
+}
+
+func (w Warning) String() string {
+	return (&ParseError{Line: w.Line, Text: w.Text, Field: w.Field, Err: errors.New(w.Reason)}).Error()
+}
+
+// Strict makes a parser fail on the first malformed line. This is the
+// default.
+func Strict() Option {
+	return func(o *options) {
+		o.lenient = false
+	}
+}
+
+// Lenient makes a parser skip malformed lines and carry on. A Warning for
+// each skipped line is appended to *warnings, if warnings is not nil.
+func Lenient(warnings *[]Warning) Option {
+	return func(o *options) {
+		o.lenient = true
+		o.warnings = warnings
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// malformed handles a malformed line: in strict mode it returns err, in
+// lenient mode it records a warning and returns nil so the parser skips the
+// line.
+func (o *options) malformed(err *ParseError) error {
+	if !o.lenient {
+		return err
+	}
+	if o.warnings != nil {
+		w := Warning{Line: err.Line, Text: err.Text, Field: err.Field, Reason: "malformed"}
+		if err.Err != nil {
+			w.Reason = err.Err.Error()
+		}
+		*o.warnings = append(*o.warnings, w)
+	}
+	return nil
+}
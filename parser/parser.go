@@ -2,9 +2,7 @@
 package parser
 
 import (
-	"fmt"
-	"regexp"
-	"strconv"
+	"errors"
 	"strings"
 )
 
@@ -17,80 +15,164 @@ type PoEPort struct {
 	Status     string  `json:"status"`
 }
 
-// ParsePoETable extracts a map of PoEPort entries from switch output.
-func ParsePoETable(output string) (map[string]PoEPort, error) {
-	lines := strings.Split(output, "\n")
-	ports := make(map[string]PoEPort)
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "Tw") {
-			fields := strings.Fields(line)
-			if len(fields) < 6 {
-				continue
+// ParsePoETable extracts a map of PoEPort entries from switch output. Both
+// the port table and the per-port detail of "show power inline information
+// interface <port>", with "Interface:", "Power(W):" and similar lines, are
+// parsed.
+func ParsePoETable(output string, opts ...Option) (map[PortID]PoEPort, error) {
+	o := newOptions(opts)
+	ports := make(map[PortID]PoEPort)
+	var cur PortID // port of the detail block being read
+	for n, line := range splitLines(output) {
+		key, val, ok := cutField(line, ":")
+		if !ok || isPortName(key) {
+			continue
+		}
+		if err := parsePoEDetail(ports, &cur, poeKey(key), val); err != nil {
+			err.Line, err.Text, err.Field = n+1, strings.TrimSpace(line), key
+			if err := o.malformed(err); err != nil {
+				return nil, err
 			}
-			iface := fields[0]
+		}
+	}
 
-			power, err1 := strconv.ParseFloat(fields[1], 64)
-			current, err2 := strconv.Atoi(fields[2])
-			voltage, err3 := strconv.ParseFloat(fields[3], 64)
-			if err1 != nil || err2 != nil || err3 != nil {
-				return nil, fmt.Errorf("parse error on line: %q", line)
-			}
+	t, ok := FindTable(output, "interface")
+	if !ok {
+		t, ok = FindTable(output, "port")
+	}
+	if !ok {
+		return ports, nil
+	}
+	var rows []poeRow
+	if err := t.Decode(&rows, opts...); err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		if r.Port == "" {
+			continue
+		}
+		ports[r.Port] = PoEPort{
+			PowerWatts: r.PowerWatts,
+			CurrentMA:  r.CurrentMA,
+			VoltageV:   r.VoltageV,
+			PDClass:    r.PDClass,
+			Status:     r.Status,
+		}
+	}
+	return ports, nil
+}
 
-			pdClass := strings.Join(fields[4:len(fields)-1], " ")
-			status := fields[len(fields)-1]
+// poeRow is a row of the PoE port table.
+type poeRow struct {
+	Port       PortID  `table:"interface,port,required"`
+	PowerWatts float64 `table:"power(w),power,actual power(w)"`
+	CurrentMA  int     `table:"current(ma),current"`
+	VoltageV   float64 `table:"voltage(v),voltage"`
+	PDClass    string  `table:"pd class,class,power class"`
+	Status     string  `table:"power status,status"`
+}
 
-			ports[iface] = PoEPort{
-				PowerWatts: power,
-				CurrentMA:  current,
-				VoltageV:   voltage,
-				PDClass:    pdClass,
-				Status:     status,
-			}
-		}
+func (r *poeRow) validate() error {
+	return checkPort(r.Port)
+}
+
+// poeKey normalises a detail key such as "Power(W)" to "power".
+func poeKey(key string) string {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if i := strings.Index(key, "("); i >= 0 {
+		key = strings.TrimSpace(key[:i])
 	}
+	return key
+}
 
-	return ports, nil
+// parsePoEDetail applies one "key: value" line of a per-port detail block.
+// An "Interface" or "Port" line starts a block for *cur; other lines set the
+// fields of that port and are ignored outside a block.
+func parsePoEDetail(ports map[PortID]PoEPort, cur *PortID, key, val string) *ParseError {
+	switch key {
+	case "interface", "port":
+		if !isPortName(val) {
+			*cur = ""
+			return nil
+		}
+		*cur = portID(val)
+		ports[*cur] = ports[*cur]
+		return nil
+	}
+	if *cur == "" {
+		return nil
+	}
+	p := ports[*cur]
+	number := func(dst *float64) *ParseError {
+		n, ok := parseNumber(val)
+		if !ok {
+			return &ParseError{Err: errors.New("not a number")}
+		}
+		*dst = n
+		return nil
+	}
+	switch key {
+	case "power", "power consumption", "actual power":
+		if err := number(&p.PowerWatts); err != nil {
+			return err
+		}
+	case "current":
+		var ma float64
+		if err := number(&ma); err != nil {
+			return err
+		}
+		p.CurrentMA = int(ma)
+	case "voltage":
+		if err := number(&p.VoltageV); err != nil {
+			return err
+		}
+	case "pd class", "class", "power class":
+		p.PDClass = val
+	case "power status", "status":
+		p.Status = val
+	default:
+		return nil
+	}
+	ports[*cur] = p
+	return nil
 }
 
 // InterfaceCounters represents a set of counters for a single port.
 type InterfaceCounters map[string]uint64
 
 // InterfaceStats holds counters per port.
-type InterfaceStats map[string]InterfaceCounters
+type InterfaceStats map[PortID]InterfaceCounters
 
 // ParseInterfaceCounters parses the "show interface counters" output into structured data.
-func ParseInterfaceCounters(output string) (InterfaceStats, error) {
-	lines := strings.Split(output, "\n")
+// Each port's counters follow a "Port:" line as "key: value" lines, so the
+// output is read line by line rather than as a Table. Lines whose value is
+// not a number are skipped.
+func ParseInterfaceCounters(output string, opts ...Option) (InterfaceStats, error) {
+	o := newOptions(opts)
 	stats := make(InterfaceStats)
-	var currentPort string
+	var currentPort PortID
 
-	keyValRegex := regexp.MustCompile(`^([\w\- /]+):\s+([\d,]+)$`)
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "Port:") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				currentPort = strings.TrimSpace(parts[1])
-				stats[currentPort] = make(InterfaceCounters)
-			}
+	for n, line := range splitLines(output) {
+		key, val, ok := cutField(line, ":")
+		if !ok {
 			continue
 		}
-		if currentPort == "" {
+		if key == "Port" {
+			currentPort = portID(val)
+			stats[currentPort] = make(InterfaceCounters)
 			continue
 		}
-
-		if matches := keyValRegex.FindStringSubmatch(line); len(matches) == 3 {
-			key := strings.TrimSpace(matches[1])
-			valStr := strings.ReplaceAll(matches[2], ",", "")
-			val, err := strconv.ParseUint(valStr, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid number for key %q: %v", key, err)
+		if currentPort == "" || val == "" || strings.Trim(val, "0123456789,") != "" {
+			continue
+		}
+		v, err := parseCounter(val)
+		if err != nil {
+			if err := o.malformed(&ParseError{Line: n + 1, Text: strings.TrimSpace(line), Field: key, Err: err}); err != nil {
+				return nil, err
 			}
-			stats[currentPort][key] = val
+			continue
 		}
+		stats[currentPort][key] = v
 	}
 
 	return stats, nil
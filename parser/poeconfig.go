@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PoEPortConfig is the PoE configuration of one port.
+type PoEPortConfig struct {
+	Port            PortID  `json:"port"`
+	Enabled         bool    `json:"enabled"`
+	Priority        string  `json:"priority"`          // "Low", "Middle", "High"
+	PowerLimit      string  `json:"power_limit"`       // as printed, e.g. "Class4" or "15.4"
+	PowerLimitWatts float64 `json:"power_limit_watts"` // PowerLimit in watts, class limits resolved; 0 for "Auto"
+	TimeRange       string  `json:"time_range,omitempty"`
+	Profile         string  `json:"profile,omitempty"`
+}
+
+// classLimitWatts is the 802.3af/at/bt power a PD class may draw at the PSE.
+var classLimitWatts = map[string]float64{
+	"class0": 15.4, "class1": 4.0, "class2": 7.0, "class3": 15.4,
+	"class4": 30.0, "class5": 45.0, "class6": 60.0, "class7": 75.0, "class8": 90.0,
+}
+
+// ParsePoEConfig parses "show power inline configuration interface".
+func ParsePoEConfig(output string, opts ...Option) (map[PortID]PoEPortConfig, error) {
+	o := newOptions(opts)
+	ports := make(map[PortID]PoEPortConfig)
+	t, ok := FindTable(output, "interface")
+	if !ok {
+		t, ok = FindTable(output, "port")
+	}
+	if !ok {
+		return ports, nil
+	}
+	status := t.Col("status", "poe-status", "poe status", "admin", "power")
+	prio := t.Col("priority", "poe-prio", "poe priority", "prio")
+	limit := t.Col("power-limit(w)", "power-limit", "power limit", "power limit(w)", "max power(w)")
+	timeRange := t.Col("time-range", "time range")
+	profile := t.Col("profile", "poe-profile", "poe profile")
+	for r, row := range t.Rows {
+		port := value(row, 0)
+		if ok, err := o.portRow(t, r, 0); !ok {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		c := PoEPortConfig{
+			Port:       portID(port),
+			Enabled:    isEnabled(value(row, status)),
+			Priority:   value(row, prio),
+			PowerLimit: value(row, limit),
+			TimeRange:  value(row, timeRange),
+			Profile:    value(row, profile),
+		}
+		key := strings.ToLower(strings.ReplaceAll(c.PowerLimit, " ", ""))
+		if w, ok := classLimitWatts[key]; ok {
+			c.PowerLimitWatts = w
+		} else if m := wattsRegex.FindStringSubmatch(c.PowerLimit); m != nil {
+			c.PowerLimitWatts, _ = strconv.ParseFloat(m[1], 64)
+		} else if !isPlaceholder(c.PowerLimit) && !strings.EqualFold(c.PowerLimit, "auto") {
+			err := t.rowError(r, t.Headers[limit], fmt.Errorf("invalid power limit %q", c.PowerLimit))
+			if err := o.malformed(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		ports[c.Port] = c
+	}
+	return ports, nil
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PoESystemInfo is the switch-wide PoE power budget from "show power inline".
+type PoESystemInfo struct {
+	BudgetWatts    float64           `json:"budget_watts"`
+	ConsumedWatts  float64           `json:"consumed_watts"`
+	RemainingWatts float64           `json:"remaining_watts"`
+	Status         string            `json:"status,omitempty"` // system power status, e.g. "On"
+	Fields         map[string]string `json:"fields"`
+}
+
+// UsedPercent returns the consumed share of the power budget.
+func (p PoESystemInfo) UsedPercent() float64 {
+	if p.BudgetWatts <= 0 {
+		return 0
+	}
+	return p.ConsumedWatts / p.BudgetWatts * 100
+}
+
+// wattsRegex matches the number of a power reading such as "240.0 w".
+var wattsRegex = regexp.MustCompile(`^(-?\d+(?:\.\d+)?)\s*(?:w|W|watts?|Watts?)?\b`)
+
+// ParsePoESystemInfo parses the system section of "show power inline".
+func ParsePoESystemInfo(output string, opts ...Option) (PoESystemInfo, error) {
+	o := newOptions(opts)
+	info := PoESystemInfo{Fields: make(map[string]string)}
+	haveRemaining := false
+	for n, line := range splitLines(output) {
+		key, val, ok := cutField(line, ":")
+		if !ok {
+			continue
+		}
+		info.Fields[key] = val
+
+		k := strings.TrimPrefix(strings.ToLower(key), "system ")
+		k = strings.TrimPrefix(k, "poe ")
+		var dst *float64
+		switch k {
+		case "power limit", "power budget", "total power", "max power", "maximum power":
+			dst = &info.BudgetWatts
+		case "power consumption", "consumed power", "power consumed", "used power", "allocated power":
+			dst = &info.ConsumedWatts
+		case "power remain", "remaining power", "power remaining", "available power":
+			dst, haveRemaining = &info.RemainingWatts, true
+		case "power status", "status", "system power status":
+			info.Status = val
+		}
+		if dst == nil {
+			continue
+		}
+		m := wattsRegex.FindStringSubmatch(val)
+		if m == nil {
+			if err := o.malformed(&ParseError{Line: n + 1, Text: line, Field: key, Err: errors.New("no wattage")}); err != nil {
+				return PoESystemInfo{}, err
+			}
+			continue
+		}
+		*dst, _ = strconv.ParseFloat(m[1], 64)
+	}
+	if !haveRemaining && info.BudgetWatts > 0 {
+		info.RemainingWatts = info.BudgetWatts - info.ConsumedWatts
+	}
+	return info, nil
+}
@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PortID identifies a switch port, such as "Gi1/0/3", "Te1/0/25", "Po1" or,
+// without a type prefix, "2/0/15". Parsers return port names normalised by
+// NormalizePortID, so the same port has the same PortID in every command's
+// output.
+type PortID string
+
+// portTypes maps the port type names switches print, long or short and
+// lower-cased, to their canonical abbreviation.
+var portTypes = map[string]string{
+	"fa": "Fa", "fastethernet": "Fa",
+	"gi": "Gi", "gigabitethernet": "Gi",
+	"tw": "Tw", "twogigabitethernet": "Tw", "tu": "Tw",
+	"fi": "Fi", "fivegigabitethernet": "Fi",
+	"te": "Te", "tengigabitethernet": "Te",
+	"tf": "Tf", "twentyfivegigabitethernet": "Tf",
+	"fo": "Fo", "fortygigabitethernet": "Fo",
+	"hu": "Hu", "hundredgigabitethernet": "Hu",
+	"po": "Po", "port-channel": "Po", "portchannel": "Po",
+	"lag": "LAG",
+}
+
+// ParsePortID parses and normalises a port name. It fails if s does not
+// name a single port.
+func ParsePortID(s string) (PortID, error) {
+	s = strings.TrimSpace(s)
+	if !isPortName(s) {
+		return "", fmt.Errorf("invalid port name: %q", s)
+	}
+	return NormalizePortID(s), nil
+}
+
+// NormalizePortID returns s with the port type abbreviated to its
+// canonical form, e.g. "GigabitEthernet1/0/1" and "gi 1/0/1" become
+// "Gi1/0/1". Names that are not ports are returned unchanged.
+func NormalizePortID(s string) PortID {
+	s = strings.TrimSpace(s)
+	if !isPortName(s) {
+		return PortID(s)
+	}
+	i := strings.IndexFunc(s, func(r rune) bool { return r >= '0' && r <= '9' })
+	prefix, nums := strings.TrimSpace(s[:i]), s[i:]
+	if t, ok := portTypes[strings.ToLower(prefix)]; ok {
+		prefix = t
+	}
+	return PortID(prefix + nums)
+}
+
+// portID is NormalizePortID for parser results.
+func portID(s string) PortID { return NormalizePortID(s) }
+
+// portIDs normalises each name of list.
+func portIDs(list []string) []PortID {
+	if list == nil {
+		return nil
+	}
+	ids := make([]PortID, len(list))
+	for i, s := range list {
+		ids[i] = portID(s)
+	}
+	return ids
+}
+
+// String returns the port name.
+func (p PortID) String() string { return string(p) }
+
+// Type returns the port type prefix, such as "Gi", or "" if there is none.
+func (p PortID) Type() string {
+	i := strings.IndexFunc(string(p), func(r rune) bool { return r >= '0' && r <= '9' })
+	if i < 0 {
+		return string(p)
+	}
+	return strings.TrimSpace(string(p[:i]))
+}
+
+// Numbers returns the unit, slot and port numbers of p, e.g. [1 0 3] for
+// "Gi1/0/3" or [2] for "Po2", or nil if p is not a port name.
+func (p PortID) Numbers() []int {
+	s := string(p)
+	i := strings.IndexFunc(s, func(r rune) bool { return r >= '0' && r <= '9' })
+	if i < 0 || !isPortName(s) {
+		return nil
+	}
+	var nums []int
+	for _, f := range strings.Split(s[i:], "/") {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil
+		}
+		nums = append(nums, n)
+	}
+	return nums
+}
+
+// Physical reports whether p is a front-panel port, numbered unit/slot/port,
+// rather than a port-channel or other logical port.
+func (p PortID) Physical() bool {
+	return len(p.Numbers()) > 1
+}
+
+// Unit returns the stack unit number of p, or 0 for a port without one,
+// such as "Po1".
+func (p PortID) Unit() int {
+	if n := p.Numbers(); len(n) > 1 {
+		return n[0]
+	}
+	return 0
+}
+
+// Compare orders ports naturally: physical ports before port-channels and
+// other logical ports, then by unit, slot and port number, then by type;
+// "Gi1/0/2" sorts before "Gi1/0/10". It returns -1, 0 or +1.
+func (p PortID) Compare(q PortID) int {
+	a, b := p.Numbers(), q.Numbers()
+	if a == nil || b == nil {
+		switch {
+		case a != nil:
+			return -1
+		case b != nil:
+			return 1
+		}
+		return strings.Compare(string(p), string(q))
+	}
+	if c := compareBool(len(a) == 1, len(b) == 1); c != 0 {
+		return c
+	}
+	if c := slices.Compare(a, b); c != 0 {
+		return c
+	}
+	return strings.Compare(p.Type(), q.Type())
+}
+
+// compareBool orders false before true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case b:
+		return -1
+	}
+	return 1
+}
+
+// SortPortIDs sorts ids in natural port order.
+func SortPortIDs(ids []PortID) {
+	slices.SortFunc(ids, PortID.Compare)
+}
+
+// MarshalText implements encoding.TextMarshaler, so a PortID encodes as its
+// name, also as a JSON map key.
+func (p PortID) MarshalText() ([]byte, error) {
+	return []byte(p), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, normalising the name.
+func (p *PortID) UnmarshalText(text []byte) error {
+	*p = NormalizePortID(string(text))
+	return nil
+}
+
+// PortIDsOf returns the keys of a per-port map in natural port order.
+func PortIDsOf[V any](m map[PortID]V) []PortID {
+	ids := make([]PortID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	SortPortIDs(ids)
+	return ids
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// portRangeRegex matches a port or port range such as "Gi1/0/1",
+// "Gi1/0/1-8" or "2/0/1-8", with an optional marker such as "(u)" after
+// it. A port without a type prefix must have a unit or slot number.
+var portRangeRegex = regexp.MustCompile(`^([A-Za-z][A-Za-z-]*)?((?:\d+/)*)(\d+)(?:-(\d+))?(?:\((\w+)\))?$`)
+
+// expandPorts splits a comma or space separated port list, expanding ranges
+// such as "Gi1/0/1-4". Tokens that are not ports are kept as they are.
+func expandPorts(list string) []PortID {
+	var ports []PortID
+	for _, p := range markedPorts(list) {
+		ports = append(ports, p.name)
+	}
+	return ports
+}
+
+// markedPort is a port from a list, with the marker that followed it, if any.
+type markedPort struct {
+	name   PortID
+	marker string // e.g. "u" for "Gi1/0/1(u)", as printed
+}
+
+// markedPorts is expandPorts keeping per-port markers.
+func markedPorts(list string) []markedPort {
+	var ports []markedPort
+	for _, tok := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		m := portRangeRegex.FindStringSubmatch(tok)
+		if m == nil || m[1] == "" && m[2] == "" {
+			ports = append(ports, markedPort{name: portID(tok)})
+			continue
+		}
+		marker := m[5]
+		first, _ := strconv.Atoi(m[3])
+		last := first
+		if m[4] != "" {
+			last, _ = strconv.Atoi(m[4])
+		}
+		if last < first || last-first > 4096 {
+			ports = append(ports, markedPort{name: portID(tok)})
+			continue
+		}
+		for n := first; n <= last; n++ {
+			ports = append(ports, markedPort{name: portID(m[1] + m[2] + strconv.Itoa(n)), marker: marker})
+		}
+	}
+	return ports
+}
+
+// portNameRegex matches a single port name such as "Gi1/0/1", "LAG2",
+// "Port-channel1" or, on stacked switches, a bare unit/slot/port such as
+// "2/0/15".
+var portNameRegex = regexp.MustCompile(`^(?:[A-Za-z][A-Za-z-]*\s?(?:\d+/)*\d+|\d+(?:/\d+)+)$`)
+
+// isPortName reports whether s names a single port.
+func isPortName(s string) bool {
+	return portNameRegex.MatchString(s)
+}
+
+// checkPortList returns an error if a port list, such as
+// "Gi1/0/1-3,Gi1/0/8", holds something other than ports. A placeholder
+// such as "None" or "--" is an empty list.
+func checkPortList(list string) error {
+	if isPlaceholder(list) {
+		return nil
+	}
+	for _, p := range expandPorts(list) {
+		if !isPortName(string(p)) && !isPlaceholder(string(p)) {
+			return fmt.Errorf("invalid port %q", p)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"strings"
+)
+
+// PortSecurity is the MAC limiting state of one port.
+type PortSecurity struct {
+	Port        PortID `json:"port" table:"port,interface"`
+	Enabled     bool   `json:"enabled"`
+	MaxMACs     int    `json:"max_macs" table:"max*"`
+	LearnedMACs int    `json:"learned_macs" table:"current*,learn*,count*"`
+	Mode        string `json:"mode,omitempty" table:"mode,learn mode,learning mode,type"`                              // learning mode, e.g. "Dynamic", "Static", "Permanent"
+	Action      string `json:"action,omitempty" table:"exceed-action,violation,violation action,exceed action,action"` // violation action, e.g. "Drop", "Shutdown"
+	Violation   bool   `json:"violation"`                                                                              // the port is in a violation state or at its limit
+	Status      string `json:"status,omitempty" table:"status,state,security status,port security"`                    // status as printed
+}
+
+// ParsePortSecurity parses "show port-security" (or "show mac address-table
+// max-mac-count"): a port table with maximum and learned MAC counts.
+func ParsePortSecurity(output string, opts ...Option) (map[PortID]PortSecurity, error) {
+	ports := make(map[PortID]PortSecurity)
+	t, ok := FindTable(output, "port")
+	if !ok {
+		t, ok = FindTable(output, "interface")
+	}
+	if !ok {
+		return ports, nil
+	}
+	var rows []PortSecurity
+	if err := t.Decode(&rows, opts...); err != nil {
+		return nil, err
+	}
+	hasStatus := t.Col("status", "state", "security status", "port security") >= 0
+	for _, p := range rows {
+		if p.Port == "" {
+			continue
+		}
+		st := strings.ToLower(p.Status)
+		p.Enabled = !hasStatus || isEnabled(st) || strings.Contains(st, "secure") || strings.Contains(st, "violat")
+		p.Violation = strings.Contains(st, "violat") || strings.Contains(st, "shutdown") ||
+			p.Enabled && p.MaxMACs > 0 && p.LearnedMACs >= p.MaxMACs
+		ports[p.Port] = p
+	}
+	return ports, nil
+}
+
+func (p *PortSecurity) validate() error {
+	return checkPort(p.Port)
+}
@@ -0,0 +1,184 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// QoSConfig is the QoS configuration from the "show qos" commands.
+type QoSConfig struct {
+	Ports   []QoSPort   `json:"ports"`
+	CoSMap  map[int]int `json:"cos_map,omitempty"`  // 802.1p priority to queue
+	DSCPMap map[int]int `json:"dscp_map,omitempty"` // DSCP to queue
+}
+
+// QoSPort is the QoS setting of one port.
+type QoSPort struct {
+	Port       PortID `json:"port"`
+	Trust      string `json:"trust,omitempty"`     // "untrust", "dot1p" or "dscp"
+	Scheduler  string `json:"scheduler,omitempty"` // e.g. "SP", "WRR", "SP+WRR"
+	Weights    []int  `json:"weights,omitempty"`   // per-queue WRR weights
+	DefaultCoS int    `json:"default_cos,omitempty"`
+}
+
+// NotTrusting returns the ports whose trust mode is not mode, e.g. "dscp".
+func (q QoSConfig) NotTrusting(mode string) []PortID {
+	var out []PortID
+	for _, p := range q.Ports {
+		if p.Trust != mode {
+			out = append(out, p.Port)
+		}
+	}
+	return out
+}
+
+// ParseQoS parses the output of "show qos trust interface", "show qos
+// cos-map", "show qos dscp-map" and "show qos queue-mode" or "scheduler",
+// alone or concatenated. Maps may be printed as two-column tables or as a
+// priority row above a queue row.
+func ParseQoS(output string, opts ...Option) (QoSConfig, error) {
+	o := newOptions(opts)
+	var q QoSConfig
+	index := make(map[string]int)
+	port := func(name string) *QoSPort {
+		i, ok := index[name]
+		if !ok {
+			i = len(q.Ports)
+			index[name] = i
+			q.Ports = append(q.Ports, QoSPort{Port: portID(name)})
+		}
+		return &q.Ports[i]
+	}
+
+	for _, t := range Tables(output) {
+		pc := t.Col("port", "interface")
+		if pc >= 0 {
+			trust := t.Col("trust mode", "trust", "qos trust")
+			sched := t.Col("schedule mode", "scheduler", "queue mode", "scheduling", "mode")
+			cos := t.Col("default cos", "default priority", "port priority", "cos")
+			var weights []int
+			for i, h := range t.Headers {
+				if strings.HasPrefix(h, "tc") || strings.HasPrefix(h, "queue") || strings.HasPrefix(h, "weight") {
+					weights = append(weights, i)
+				}
+			}
+			for r, row := range t.Rows {
+				if ok, err := o.portRow(t, r, pc); !ok {
+					if err != nil {
+						return QoSConfig{}, err
+					}
+					continue
+				}
+				rr := t.reader(r)
+				defaultCoS := rr.int(cos)
+				var w []int
+				for _, i := range weights {
+					if value(row, i) != "" {
+						w = append(w, rr.int(i))
+					}
+				}
+				if rr.err != nil {
+					if err := o.malformed(rr.err); err != nil {
+						return QoSConfig{}, err
+					}
+					continue
+				}
+				p := port(value(row, pc))
+				if v := value(row, trust); v != "" {
+					p.Trust = trustMode(v)
+				}
+				if v := value(row, sched); v != "" {
+					p.Scheduler = v
+				}
+				if value(row, cos) != "" {
+					p.DefaultCoS = defaultCoS
+				}
+				p.Weights = append(p.Weights, w...)
+			}
+			continue
+		}
+		from, to := t.Col("cos", "dot1p", "802.1p", "priority", "cos value"), t.Col("queue", "tc", "traffic class", "queue id")
+		target := &q.CoSMap
+		if d := t.Col("dscp", "dscp value"); d >= 0 {
+			from, target = d, &q.DSCPMap
+		}
+		if from < 0 || to < 0 {
+			continue
+		}
+		for r, row := range t.Rows {
+			if value(row, from) == "" {
+				continue
+			}
+			rr := t.reader(r)
+			k, v := rr.int(from), rr.int(to)
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return QoSConfig{}, err
+				}
+				continue
+			}
+			if *target == nil {
+				*target = make(map[int]int)
+			}
+			(*target)[k] = v
+		}
+	}
+
+	// Horizontal maps: "CoS 0 1 2 ..." followed by "Queue TC1 TC0 ...".
+	lines := splitLines(output)
+	for i := 0; i+1 < len(lines); i++ {
+		keys, vals := strings.Fields(lines[i]), strings.Fields(lines[i+1])
+		if len(keys) < 2 || len(keys) != len(vals) {
+			continue
+		}
+		var target *map[int]int
+		switch strings.ToLower(strings.TrimRight(keys[0], ":")) {
+		case "cos", "dot1p", "802.1p", "priority":
+			target = &q.CoSMap
+		case "dscp":
+			target = &q.DSCPMap
+		default:
+			continue
+		}
+		switch strings.ToLower(strings.TrimRight(vals[0], ":")) {
+		case "queue", "tc", "queue id":
+		default:
+			continue
+		}
+		for j := 1; j < len(keys); j++ {
+			k, err1 := strconv.Atoi(keys[j])
+			v, err2 := strconv.Atoi(trimQueue(vals[j]))
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			if *target == nil {
+				*target = make(map[int]int)
+			}
+			(*target)[k] = v
+		}
+		i++
+	}
+	return q, nil
+}
+
+// trustMode normalises a trust mode such as "trust-dscp" or "Trust DSCP".
+func trustMode(s string) string {
+	s = strings.ToLower(s)
+	switch {
+	case strings.Contains(s, "untrust"):
+		return "untrust"
+	case strings.Contains(s, "dscp"):
+		return "dscp"
+	case strings.Contains(s, "dot1p") || strings.Contains(s, "802.1p") || strings.Contains(s, "cos"):
+		return "dot1p"
+	}
+	return s
+}
+
+// trimQueue strips the "TC" or "Queue" prefix from a queue name such as "TC3".
+func trimQueue(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "queue")
+	s = strings.TrimPrefix(s, "tc")
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNoParser is returned by Parse for commands without a registered parser.
+var ErrNoParser = errors.New("no parser for command")
+
+// Func parses the output of a command. The result is the parser's usual
+// return value, e.g. []ARPEntry for "show arp".
+type Func func(output string, opts ...Option) (any, error)
+
+// Entry registers a parser for a command.
+type Entry struct {
+	Command string   // CLI command, e.g. "show arp"; longer commands with arguments also match
+	Models  []string // hardware version prefixes the parser is for; none for all models
+	Parse   Func
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []Entry
+)
+
+// Register adds e to the registry, replacing any entry for the same
+// command and models. Parsers outside this package register the same way,
+// typically from an init function.
+func Register(e Entry) {
+	e.Command = normalizeCommand(e.Command)
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for i, r := range registry {
+		if r.Command == e.Command && strings.Join(r.Models, ",") == strings.Join(e.Models, ",") {
+			registry[i] = e
+			return
+		}
+	}
+	registry = append(registry, e)
+}
+
+// Lookup returns the parser for command on a switch of the given model,
+// which may be "" if unknown. The entry with the longest matching command
+// wins; among those, one for the model wins over one for all models.
+// Commands match on whole words, so "show vlan 10" uses the "show vlan"
+// parser.
+func Lookup(command, model string) (Func, bool) {
+	command = normalizeCommand(command)
+	model = strings.ToUpper(model)
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	var (
+		best               Func
+		bestCmd, bestModel = -1, -1
+	)
+	for _, e := range registry {
+		if command != e.Command && !strings.HasPrefix(command, e.Command+" ") {
+			continue
+		}
+		m := modelMatch(e.Models, model)
+		if m < 0 {
+			continue
+		}
+		if len(e.Command) > bestCmd || len(e.Command) == bestCmd && m > bestModel {
+			best, bestCmd, bestModel = e.Parse, len(e.Command), m
+		}
+	}
+	return best, best != nil
+}
+
+// modelMatch returns the length of the longest prefix in models matching
+// model, 0 if models is empty, or -1 if none matches.
+func modelMatch(models []string, model string) int {
+	if len(models) == 0 {
+		return 0
+	}
+	best := -1
+	for _, prefix := range models {
+		if strings.HasPrefix(model, strings.ToUpper(prefix)) && len(prefix) > best {
+			best = len(prefix)
+		}
+	}
+	return best
+}
+
+// Parse parses the output of command with its registered parser.
+func Parse(command, output string, opts ...Option) (any, error) {
+	return ParseModel(command, "", output, opts...)
+}
+
+// ParseModel is Parse for a switch of the given model, preferring parsers
+// registered for it. A *ParseError it returns has Command set.
+func ParseModel(command, model, output string, opts ...Option) (any, error) {
+	fn, ok := Lookup(command, model)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNoParser, command)
+	}
+	v, err := fn(output, opts...)
+	var pe *ParseError
+	if errors.As(err, &pe) && pe.Command == "" {
+		pe.Command = normalizeCommand(command)
+	}
+	return v, err
+}
+
+// Commands returns the commands with a registered parser, sorted.
+func Commands() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	seen := make(map[string]bool)
+	var cmds []string
+	for _, e := range registry {
+		if !seen[e.Command] {
+			seen[e.Command] = true
+			cmds = append(cmds, e.Command)
+		}
+	}
+	sort.Strings(cmds)
+	return cmds
+}
+
+// normalizeCommand lower-cases command and collapses its spaces.
+func normalizeCommand(command string) string {
+	return strings.Join(strings.Fields(strings.ToLower(command)), " ")
+}
+
+// FuncOf adapts a parser such as ParseARPTable to Func.
+func FuncOf[T any](parse func(string, ...Option) (T, error)) Func {
+	return func(output string, opts ...Option) (any, error) {
+		v, err := parse(output, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
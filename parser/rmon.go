@@ -0,0 +1,334 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RMONCounters are the counters shared by the RMON statistics and history
+// groups.
+type RMONCounters struct {
+	DropEvents     uint64 `json:"drop_events"`
+	Octets         uint64 `json:"octets"`
+	Packets        uint64 `json:"packets"`
+	Broadcast      uint64 `json:"broadcast"`
+	Multicast      uint64 `json:"multicast"`
+	CRCAlignErrors uint64 `json:"crc_align_errors"`
+	Undersize      uint64 `json:"undersize"`
+	Oversize       uint64 `json:"oversize"`
+	Fragments      uint64 `json:"fragments"`
+	Jabbers        uint64 `json:"jabbers"`
+	Collisions     uint64 `json:"collisions"`
+}
+
+// field returns the field for a counter name normalised by counterKey, or
+// nil.
+func (c *RMONCounters) field(key string) *uint64 {
+	switch strings.TrimSuffix(key, "pkts") {
+	case "dropevents", "drops", "droppedevents", "drop":
+		return &c.DropEvents
+	case "octets", "bytes":
+		return &c.Octets
+	case "", "packets":
+		return &c.Packets
+	case "broadcast", "bcast":
+		return &c.Broadcast
+	case "multicast", "mcast":
+		return &c.Multicast
+	case "crcalignerrors", "crcalign", "crcerrors":
+		return &c.CRCAlignErrors
+	case "undersize", "runts":
+		return &c.Undersize
+	case "oversize", "giants":
+		return &c.Oversize
+	case "fragments":
+		return &c.Fragments
+	case "jabbers", "jabber":
+		return &c.Jabbers
+	case "collisions", "collision":
+		return &c.Collisions
+	}
+	return nil
+}
+
+// RMONStatistics is an entry of the RMON statistics group: the traffic of
+// one port since the entry was created, with its packet size distribution.
+type RMONStatistics struct {
+	Index  int    `json:"index"`
+	Port   PortID `json:"port"`
+	Owner  string `json:"owner,omitempty"`
+	Status string `json:"status,omitempty"` // e.g. "Valid", "UnderCreation"
+	RMONCounters
+	Pkts64        uint64            `json:"pkts_64"`
+	Pkts65To127   uint64            `json:"pkts_65_127"`
+	Pkts128To255  uint64            `json:"pkts_128_255"`
+	Pkts256To511  uint64            `json:"pkts_256_511"`
+	Pkts512To1023 uint64            `json:"pkts_512_1023"`
+	Pkts1024ToMax uint64            `json:"pkts_1024_max"`
+	Other         map[string]uint64 `json:"other,omitempty"` // counters without a field, by the name the switch uses
+}
+
+// field returns the counter field for a normalised name, or nil.
+func (s *RMONStatistics) field(key string) *uint64 {
+	if f := s.RMONCounters.field(key); f != nil {
+		return f
+	}
+	switch strings.TrimSuffix(strings.TrimPrefix(key, "pkts"), "octets") {
+	case "64":
+		return &s.Pkts64
+	case "65to127", "65127":
+		return &s.Pkts65To127
+	case "128to255", "128255":
+		return &s.Pkts128To255
+	case "256to511", "256511":
+		return &s.Pkts256To511
+	case "512to1023", "5121023":
+		return &s.Pkts512To1023
+	case "1024to1518", "10241518", "1024tomax", "1024max", "1024to1522", "10241522":
+		return &s.Pkts1024ToMax
+	}
+	return nil
+}
+
+// RMONHistory is an entry of the RMON history group: the sampling
+// configuration of one port and the samples taken.
+type RMONHistory struct {
+	Index           int          `json:"index"`
+	Port            PortID       `json:"port"`
+	IntervalSeconds int          `json:"interval_seconds"`
+	Buckets         int          `json:"buckets"` // samples kept
+	Owner           string       `json:"owner,omitempty"`
+	Status          string       `json:"status,omitempty"`
+	Samples         []RMONSample `json:"samples"`
+}
+
+// RMONSample is the traffic of one history interval.
+type RMONSample struct {
+	Sample int    `json:"sample"`
+	Start  string `json:"start,omitempty"` // interval start as printed
+	RMONCounters
+	Utilization float64 `json:"utilization"` // percent of the link
+}
+
+// rmonBlocks splits output into the blocks starting at each "Index:" line.
+// Text before the first such line is its own block.
+func rmonBlocks(output string) [][]string {
+	var blocks [][]string
+	var cur []string
+	for _, line := range splitLines(output) {
+		key, _, _ := strings.Cut(strings.TrimSpace(line), ":")
+		if k := strings.ToLower(strings.TrimSpace(key)); (k == "index" || k == "entry index") && len(cur) > 0 {
+			blocks = append(blocks, cur)
+			cur = nil
+		}
+		cur = append(cur, line)
+	}
+	if len(cur) > 0 {
+		blocks = append(blocks, cur)
+	}
+	return blocks
+}
+
+// parseCounter parses a counter value such as "1,234".
+func parseCounter(s string) (uint64, error) {
+	f := strings.Fields(strings.ReplaceAll(s, ",", ""))
+	if len(f) == 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return strconv.ParseUint(f[0], 10, 64)
+}
+
+// counter returns the counter in column col as parseCounter does, 0 for a
+// placeholder or if col is -1.
+func (rr *rowReader) counter(col int) uint64 {
+	s := value(rr.t.Rows[rr.r], col)
+	if isPlaceholder(s) {
+		return 0
+	}
+	n, err := parseCounter(s)
+	if err != nil {
+		rr.fail(col, fmt.Errorf("invalid counter %q", s))
+	}
+	return n
+}
+
+// ParseRMONStatistics parses "show rmon statistics": an "Index:" block of
+// "key: value" lines per entry, or a table with a row per entry.
+func ParseRMONStatistics(output string, opts ...Option) ([]RMONStatistics, error) {
+	o := newOptions(opts)
+	var stats []RMONStatistics
+	line := 0
+	for _, block := range rmonBlocks(output) {
+		var s RMONStatistics
+		found := false
+		for _, text := range block {
+			line++
+			key, val, ok := strings.Cut(strings.TrimSpace(text), ":")
+			if !ok {
+				continue
+			}
+			key = strings.TrimSpace(key)
+			val = strings.TrimSpace(val)
+			k := counterKey(key)
+			switch k {
+			case "index", "entryindex":
+				var err error
+				if s.Index, err = parseInt(val); err != nil {
+					if err := o.malformed(&ParseError{Line: line, Text: text, Field: key, Err: err}); err != nil {
+						return nil, err
+					}
+				}
+				found = true
+				continue
+			case "port", "interface", "datasource":
+				s.Port, found = portID(val), true
+				continue
+			case "owner":
+				s.Owner = val
+				continue
+			case "status":
+				s.Status = val
+				continue
+			}
+			n, err := parseCounter(val)
+			f := s.field(k)
+			if err != nil {
+				if f == nil {
+					continue
+				}
+				if err := o.malformed(&ParseError{Line: line, Text: text, Field: key, Err: err}); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if f == nil {
+				if s.Other == nil {
+					s.Other = make(map[string]uint64)
+				}
+				s.Other[key] = n
+				continue
+			}
+			*f = n
+		}
+		if found {
+			stats = append(stats, s)
+		}
+	}
+	if len(stats) > 0 {
+		return stats, nil
+	}
+
+	for _, t := range Tables(output) {
+		port := t.Col("port", "interface", "data source")
+		if port < 0 {
+			continue
+		}
+		index := t.Col("index", "entry", "id")
+		owner := t.Col("owner")
+		status := t.Col("status")
+		for r, row := range t.Rows {
+			if ok, err := o.portRow(t, r, port); !ok {
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			rr := t.reader(r)
+			s := RMONStatistics{Index: rr.int(index), Port: portID(value(row, port)), Owner: value(row, owner), Status: value(row, status)}
+			for i, h := range t.Headers {
+				if f := s.field(counterKey(h)); f != nil {
+					*f = rr.counter(i)
+				}
+			}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			stats = append(stats, s)
+		}
+	}
+	return stats, nil
+}
+
+// ParseRMONHistory parses "show rmon history": an "Index:" block per
+// entry with its port, interval and buckets, followed by a table of the
+// samples taken, with a column per counter.
+func ParseRMONHistory(output string, opts ...Option) ([]RMONHistory, error) {
+	o := newOptions(opts)
+	var entries []RMONHistory
+	line := 0 // lines before the current block
+	for _, block := range rmonBlocks(output) {
+		var h RMONHistory
+		found := false
+		for i, text := range block {
+			key, val, ok := strings.Cut(strings.TrimSpace(text), ":")
+			if !ok {
+				continue
+			}
+			val = strings.TrimSpace(val)
+			var num *int // field of h taking a number
+			switch counterKey(key) {
+			case "index", "entryindex":
+				num, found = &h.Index, true
+			case "port", "interface", "datasource":
+				h.Port, found = portID(val), true
+			case "interval", "sampleinterval", "intervalsec", "intervalseconds", "interval(sec)":
+				num = &h.IntervalSeconds
+			case "buckets", "bucketsrequested", "requestedbuckets", "buckets(requested)":
+				num = &h.Buckets
+			case "owner":
+				h.Owner = val
+			case "status":
+				h.Status = val
+			}
+			if num == nil {
+				continue
+			}
+			n, err := parseInt(val)
+			if err != nil {
+				if err := o.malformed(lineError(line+i, text, strings.TrimSpace(key), err)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			*num = n
+		}
+		for _, t := range Tables(strings.Join(block, "\n")) {
+			sample := t.Col("sample", "sample index", "index", "no.", "no")
+			if sample < 0 {
+				continue
+			}
+			start := t.Col("start time", "interval start", "start*", "time")
+			util := t.Col("utilization", "utilization(%)", "util*")
+			for r, row := range t.Rows {
+				if value(row, sample) == "" {
+					continue
+				}
+				rr := t.reader(r)
+				s := RMONSample{Sample: rr.int(sample), Start: value(row, start), Utilization: rr.float(util)}
+				for i, hd := range t.Headers {
+					if f := s.field(counterKey(hd)); f != nil {
+						*f = rr.counter(i)
+					}
+				}
+				if rr.err != nil {
+					rr.err.Line += line
+					if err := o.malformed(rr.err); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				h.Samples = append(h.Samples, s)
+			}
+			found = true
+		}
+		if found {
+			entries = append(entries, h)
+		}
+		line += len(block)
+	}
+	return entries, nil
+}
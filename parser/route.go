@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// IPRoute is one entry of the routing table.
+type IPRoute struct {
+	Destination string `json:"destination"`
+	Mask        string `json:"mask"`
+	NextHop     string `json:"next_hop,omitempty"` // empty for connected routes
+	Interface   string `json:"interface"`
+	Type        string `json:"type"` // "connected", "static", "rip", "ospf"... or the code letter
+	Distance    int    `json:"distance,omitempty"`
+	Metric      int    `json:"metric,omitempty"`
+}
+
+// routeCodes names the route type codes from the "Codes:" legend.
+var routeCodes = map[string]string{
+	"C": "connected",
+	"S": "static",
+	"R": "rip",
+	"O": "ospf",
+	"B": "bgp",
+	"D": "eigrp",
+	"L": "local",
+}
+
+// ParseIPRoutes parses "show ip route", either the legend-and-codes listing
+// ("S  0.0.0.0/0 [1/0] via 10.0.0.1, Vlan1") or a column table.
+func ParseIPRoutes(output string, opts ...Option) ([]IPRoute, error) {
+	o := newOptions(opts)
+	var routes []IPRoute
+	for _, t := range Tables(output) {
+		dst := t.Col("destination", "destination/mask", "network", "dest")
+		if dst < 0 {
+			continue
+		}
+		mask := t.Col("mask", "netmask", "subnet mask")
+		hop := t.Col("next hop", "nexthop", "next-hop", "gateway")
+		iface := t.Col("interface", "vlan", "port")
+		typ := t.Col("type", "protocol", "proto")
+		metric := t.Col("metric", "cost")
+		for i, row := range t.Rows {
+			if value(row, dst) == "" {
+				continue
+			}
+			rr := t.reader(i)
+			r := IPRoute{NextHop: value(row, hop), Interface: value(row, iface), Type: strings.ToLower(value(row, typ)), Metric: rr.int(metric)}
+			r.Destination, r.Mask = splitAddr(value(row, dst))
+			if net.ParseIP(r.Destination) == nil {
+				rr.fail(dst, fmt.Errorf("invalid destination %q", value(row, dst)))
+			}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if m := value(row, mask); m != "" {
+				r.Mask = prefixMask(m)
+			}
+			routes = append(routes, r)
+		}
+	}
+	if len(routes) > 0 {
+		return routes, nil
+	}
+
+	for n, line := range splitLines(output) {
+		r, ok, err := parseRouteLine(line)
+		if err != nil {
+			pe := err.(*ParseError)
+			pe.Line = n + 1
+			if err := o.malformed(pe); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if ok {
+			routes = append(routes, r)
+		}
+	}
+	return routes, nil
+}
+
+// parseRouteLine parses one line of the codes listing.
+func parseRouteLine(line string) (IPRoute, bool, error) {
+	f := strings.Fields(strings.ReplaceAll(line, ",", " , "))
+	// Code letters come first, then the destination.
+	i := 0
+	for i < len(f) && net.ParseIP(strings.SplitN(f[i], "/", 2)[0]) == nil {
+		if len(f[i]) > 3 || f[i] == "," {
+			return IPRoute{}, false, nil
+		}
+		i++
+	}
+	if i == 0 || i == len(f) {
+		return IPRoute{}, false, nil
+	}
+	code := strings.TrimRight(f[0], "*")
+	r := IPRoute{Type: code}
+	if name, ok := routeCodes[code]; ok {
+		r.Type = name
+	}
+	r.Destination, r.Mask = splitAddr(f[i])
+	if r.Mask == "" && i+1 < len(f) && net.ParseIP(f[i+1]) != nil {
+		i++
+		r.Mask = f[i]
+	}
+	for i++; i < len(f); i++ {
+		switch tok := f[i]; {
+		case strings.HasPrefix(tok, "[") && strings.HasSuffix(tok, "]"):
+			d, m, _ := strings.Cut(strings.Trim(tok, "[]"), "/")
+			var err1, err2 error
+			r.Distance, err1 = strconv.Atoi(d)
+			r.Metric, err2 = strconv.Atoi(m)
+			if err1 != nil {
+				return IPRoute{}, false, &ParseError{Text: line, Field: "distance", Err: err1}
+			}
+			if err2 != nil {
+				return IPRoute{}, false, &ParseError{Text: line, Field: "metric", Err: err2}
+			}
+		case tok == "via" && i+1 < len(f):
+			i++
+			r.NextHop = f[i]
+		case tok == ",":
+			if i+1 < len(f) && isInterfaceName(f[i+1]) {
+				r.Interface = f[i+1]
+				i++
+			}
+		}
+	}
+	return r, true, nil
+}
+
+// isInterfaceName reports whether s names a port or VLAN interface.
+func isInterfaceName(s string) bool {
+	return isPortName(s) && !strings.Contains(s, ".")
+}
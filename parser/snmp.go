@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"strings"
+)
+
+// SNMPConfig is the SNMP agent configuration from the "show snmp-server"
+// commands.
+type SNMPConfig struct {
+	Enabled        bool            `json:"enabled"`
+	LocalEngineID  string          `json:"local_engine_id,omitempty"`
+	RemoteEngineID string          `json:"remote_engine_id,omitempty"`
+	Communities    []SNMPCommunity `json:"communities"`
+	Users          []SNMPUser      `json:"users"`
+	TrapHosts      []SNMPHost      `json:"trap_hosts"`
+}
+
+// SNMPCommunity is an SNMPv1/v2c community.
+type SNMPCommunity struct {
+	Name   string `json:"name"`
+	Access string `json:"access"` // "read-only" or "read-write"
+	View   string `json:"view,omitempty"`
+}
+
+// SNMPUser is an SNMPv3 user.
+type SNMPUser struct {
+	Name          string `json:"name"`
+	Type          string `json:"type,omitempty"` // "local" or "remote"
+	Group         string `json:"group"`
+	SecurityModel string `json:"security_model,omitempty"`
+	AuthMode      string `json:"auth_mode,omitempty"`
+	PrivacyMode   string `json:"privacy_mode,omitempty"`
+}
+
+// SNMPHost is a notification receiver.
+type SNMPHost struct {
+	Address       string `json:"address"`
+	Port          int    `json:"port,omitempty"`
+	Community     string `json:"community"` // community or v3 user name
+	SecurityModel string `json:"security_model,omitempty"`
+	SecurityLevel string `json:"security_level,omitempty"`
+	Type          string `json:"type,omitempty"` // "trap" or "inform"
+}
+
+// defaultCommunities are the factory community names.
+var defaultCommunities = []string{"public", "private"}
+
+// DefaultCommunities returns the configured communities with factory
+// default names such as "public".
+func (s SNMPConfig) DefaultCommunities() []SNMPCommunity {
+	var out []SNMPCommunity
+	for _, c := range s.Communities {
+		for _, d := range defaultCommunities {
+			if strings.EqualFold(c.Name, d) {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
+// ParseSNMPConfig parses the output of "show snmp-server" and its
+// "community", "user", "host" and "engineID" variants, alone or
+// concatenated.
+func ParseSNMPConfig(output string, opts ...Option) (SNMPConfig, error) {
+	o := newOptions(opts)
+	var cfg SNMPConfig
+	for _, line := range splitLines(output) {
+		key, val, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		val = strings.TrimSpace(val)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "snmp agent", "snmp", "snmp-server", "snmp server", "global state", "snmp state":
+			cfg.Enabled = isEnabled(val)
+		case "local engine id", "local engineid", "local engine-id":
+			cfg.LocalEngineID = val
+		case "remote engine id", "remote engineid", "remote engine-id":
+			cfg.RemoteEngineID = val
+		}
+	}
+	for _, t := range Tables(output) {
+		ip := t.Col("ip-address", "ip address", "host", "address", "host ip")
+		user := t.Col("user-name", "user name", "user")
+		community := t.Col("community-name", "community name", "community")
+		model := t.Col("sec-model", "security model", "sec model", "version")
+		switch {
+		case ip >= 0:
+			port := t.Col("udp-port", "udp port", "port")
+			level := t.Col("sec-level", "security level", "sec level")
+			typ := t.Col("type", "notify type")
+			name := community
+			if name < 0 {
+				name = user
+			}
+			for r, row := range t.Rows {
+				if value(row, ip) == "" {
+					continue
+				}
+				rr := t.reader(r)
+				h := SNMPHost{
+					Address:       value(row, ip),
+					Port:          rr.int(port),
+					Community:     value(row, name),
+					SecurityModel: value(row, model),
+					SecurityLevel: value(row, level),
+					Type:          value(row, typ),
+				}
+				if rr.err != nil {
+					if err := o.malformed(rr.err); err != nil {
+						return SNMPConfig{}, err
+					}
+					continue
+				}
+				cfg.TrapHosts = append(cfg.TrapHosts, h)
+			}
+		case user >= 0:
+			for _, row := range t.Rows {
+				if value(row, user) == "" {
+					continue
+				}
+				cfg.Users = append(cfg.Users, SNMPUser{
+					Name:          value(row, user),
+					Type:          value(row, t.Col("user-type", "user type", "type")),
+					Group:         value(row, t.Col("group-name", "group name", "group")),
+					SecurityModel: value(row, model),
+					AuthMode:      value(row, t.Col("auth-mode", "auth mode", "authentication")),
+					PrivacyMode:   value(row, t.Col("privacy-mode", "privacy mode", "privacy")),
+				})
+			}
+		case community >= 0:
+			for _, row := range t.Rows {
+				if value(row, community) == "" {
+					continue
+				}
+				cfg.Communities = append(cfg.Communities, SNMPCommunity{
+					Name:   value(row, community),
+					Access: value(row, t.Col("access-mode", "access mode", "access", "permission")),
+					View:   value(row, t.Col("mib-view", "mib view", "view")),
+				})
+			}
+		}
+	}
+	return cfg, nil
+}
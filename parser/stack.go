@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"strings"
+)
+
+// StackInfo describes the units of a switch stack and its stack links.
+type StackInfo struct {
+	Units []StackUnit `json:"units"`
+	Ports []StackPort `json:"ports,omitempty"`
+}
+
+// Master returns the master unit.
+func (s StackInfo) Master() (StackUnit, bool) {
+	for _, u := range s.Units {
+		if strings.EqualFold(u.Role, "master") {
+			return u, true
+		}
+	}
+	return StackUnit{}, false
+}
+
+// StackUnit is one member of a stack.
+type StackUnit struct {
+	Unit     int    `json:"unit"`
+	Role     string `json:"role"` // "Master", "Backup" or "Member"
+	MAC      string `json:"mac"`
+	Priority int    `json:"priority,omitempty"`
+	Version  string `json:"version,omitempty"` // firmware version
+	Status   string `json:"status,omitempty"`  // e.g. "Ready"
+	Model    string `json:"model,omitempty"`
+}
+
+// StackPort is a port used to link stack units.
+type StackPort struct {
+	Unit     int    `json:"unit"`
+	Port     PortID `json:"port"`
+	Status   string `json:"status"`
+	Up       bool   `json:"up"`
+	Neighbor int    `json:"neighbor,omitempty"` // unit at the other end, 0 if unknown
+}
+
+// ParseStackInfo parses "show stack" and, if included, "show stack-port".
+// The unit table has unit, role and MAC columns; the stack port table has
+// port and status columns.
+func ParseStackInfo(output string, opts ...Option) (StackInfo, error) {
+	o := newOptions(opts)
+	var info StackInfo
+	for _, t := range Tables(output) {
+		unit := t.Col("unit", "stack id", "unit id", "id", "member")
+		role := t.Col("role")
+		port := t.Col("port", "stack port", "interface")
+		switch {
+		case unit >= 0 && role >= 0:
+			mac := t.Col("mac address", "mac", "mac-address")
+			prio := t.Col("priority", "prio")
+			ver := t.Col("version", "firmware", "software version", "image version")
+			status := t.Col("status", "state")
+			model := t.Col("description", "model", "type", "hardware")
+			for r, row := range t.Rows {
+				if value(row, unit) == "" {
+					continue
+				}
+				rr := t.reader(r)
+				u := StackUnit{
+					Unit:     rr.int(unit), // "1*" marks the unit logged in to
+					Role:     value(row, role),
+					MAC:      value(row, mac),
+					Priority: rr.int(prio),
+					Version:  value(row, ver),
+					Status:   value(row, status),
+					Model:    value(row, model),
+				}
+				if rr.err != nil {
+					if err := o.malformed(rr.err); err != nil {
+						return StackInfo{}, err
+					}
+					continue
+				}
+				info.Units = append(info.Units, u)
+			}
+		case port >= 0:
+			status := t.Col("status", "state", "link status")
+			neighbor := t.Col("neighbor", "neighbor unit", "peer")
+			for r, row := range t.Rows {
+				if value(row, port) == "" {
+					continue
+				}
+				rr := t.reader(r)
+				p := StackPort{
+					Unit:     rr.int(unit),
+					Port:     portID(value(row, port)),
+					Status:   value(row, status),
+					Neighbor: rr.int(neighbor),
+				}
+				if rr.err != nil {
+					if err := o.malformed(rr.err); err != nil {
+						return StackInfo{}, err
+					}
+					continue
+				}
+				p.Up = isUp(p.Status) || strings.EqualFold(p.Status, "link up")
+				info.Ports = append(info.Ports, p)
+			}
+		}
+	}
+	return info, nil
+}
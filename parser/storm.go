@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StormControl is the storm control configuration of one port.
+type StormControl struct {
+	Port           PortID         `json:"port"`
+	Mode           string         `json:"mode,omitempty"` // rate unit, e.g. "kbps", "ratio", "pps"
+	Broadcast      StormThreshold `json:"broadcast"`
+	Multicast      StormThreshold `json:"multicast"`
+	UnknownUnicast StormThreshold `json:"unknown_unicast"`
+	Action         string         `json:"action,omitempty"`          // e.g. "Drop", "Shutdown"
+	RecoverSeconds int            `json:"recover_seconds,omitempty"` // auto-recovery time for "Shutdown"
+}
+
+// Protected reports whether any storm threshold is set on the port.
+func (s StormControl) Protected() bool {
+	return s.Broadcast.Enabled || s.Multicast.Enabled || s.UnknownUnicast.Enabled
+}
+
+// StormThreshold is a storm control limit for one traffic type.
+type StormThreshold struct {
+	Enabled bool    `json:"enabled"`
+	Rate    float64 `json:"rate,omitempty"` // in the port's Mode unit
+}
+
+// ParseStormControl parses "show storm-control" with per-port broadcast,
+// multicast and unknown-unicast rate columns; "Disable" or a blank rate
+// means no limit.
+func ParseStormControl(output string, opts ...Option) (map[PortID]StormControl, error) {
+	o := newOptions(opts)
+	ports := make(map[PortID]StormControl)
+	t, ok := FindTable(output, "port")
+	if !ok {
+		t, ok = FindTable(output, "interface")
+	}
+	if !ok {
+		return ports, nil
+	}
+	mode := t.Col("rate mode", "mode", "unit", "rate unit")
+	bc := t.Col("bc-rate", "broadcast", "bc rate", "broadcast rate", "broadcast(kbps)")
+	mc := t.Col("mc-rate", "multicast", "mc rate", "multicast rate", "multicast(kbps)")
+	ul := t.Col("ul-rate", "uc-rate", "unknown-unicast", "unknown unicast", "ul rate", "unicast")
+	action := t.Col("exceed-action", "action", "exceed action")
+	recover := t.Col("recover-time", "recover time", "recover", "recovery time")
+	for r, row := range t.Rows {
+		if ok, err := o.portRow(t, r, 0); !ok {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		rr := t.reader(r)
+		s := StormControl{
+			Port:           portID(value(row, 0)),
+			Mode:           value(row, mode),
+			Broadcast:      stormThreshold(rr, bc),
+			Multicast:      stormThreshold(rr, mc),
+			UnknownUnicast: stormThreshold(rr, ul),
+			Action:         value(row, action),
+			RecoverSeconds: rr.int(recover),
+		}
+		if rr.err != nil {
+			if err := o.malformed(rr.err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		ports[s.Port] = s
+	}
+	return ports, nil
+}
+
+// stormThreshold parses rate column col of rr's row.
+func stormThreshold(rr *rowReader, col int) StormThreshold {
+	s := value(rr.t.Rows[rr.r], col)
+	switch strings.ToLower(s) {
+	case "", "-", "disable", "disabled", "off", "none":
+		return StormThreshold{}
+	}
+	rate, ok := parseNumber(s)
+	if !ok {
+		rr.fail(col, fmt.Errorf("invalid rate %q", s))
+	}
+	return StormThreshold{Enabled: ok, Rate: rate}
+}
@@ -0,0 +1,159 @@
+package parser
+
+import "strings"
+
+// SpanningTree is the bridge and port state reported by "show spanning-tree".
+type SpanningTree struct {
+	Enabled      bool              `json:"enabled"`
+	Mode         string            `json:"mode,omitempty"` // e.g. "RSTP"
+	BridgeID     string            `json:"bridge_id"`
+	RootID       string            `json:"root_id"`
+	RootPort     PortID            `json:"root_port,omitempty"`
+	RootPathCost int               `json:"root_path_cost"`
+	Ports        []STPPort         `json:"ports"`
+	Fields       map[string]string `json:"fields"`
+}
+
+// IsRoot reports whether this switch is the root bridge.
+func (s SpanningTree) IsRoot() bool {
+	return s.BridgeID != "" && s.BridgeID == s.RootID
+}
+
+// STPPort is the spanning-tree state of one port.
+type STPPort struct {
+	Port             PortID `json:"port"`
+	Enabled          bool   `json:"enabled"`
+	Priority         int    `json:"priority"`
+	PathCost         int    `json:"path_cost"`
+	InternalCost     int    `json:"internal_cost,omitempty"` // MSTP internal path cost
+	State            string `json:"state"`                   // e.g. "Forwarding", "Discarding"
+	Role             string `json:"role"`                    // e.g. "Root", "Designated", "Alternate"
+	DesignatedBridge string `json:"designated_bridge,omitempty"`
+	Edge             string `json:"edge,omitempty"`
+	LinkType         string `json:"link_type,omitempty"`
+}
+
+// Blocked reports whether the port is not forwarding.
+func (p STPPort) Blocked() bool {
+	switch strings.ToLower(p.State) {
+	case "discarding", "blocking", "listening", "learning":
+		return true
+	}
+	return false
+}
+
+// ParseSpanningTree parses "show spanning-tree" and its per-interface
+// variant: bridge details as "key: value" lines, then a port table.
+func ParseSpanningTree(output string, opts ...Option) (SpanningTree, error) {
+	st := SpanningTree{Fields: make(map[string]string)}
+	o := newOptions(opts)
+	for n, line := range splitLines(output) {
+		if isSeparator(line) {
+			break // the port table, decoded below
+		}
+		trimmed := strings.TrimSpace(line)
+		lower := strings.ToLower(trimmed)
+		if strings.HasPrefix(lower, "spanning tree is ") || strings.HasPrefix(lower, "spanning-tree is ") {
+			st.Enabled = strings.Contains(lower, "enabled")
+			continue
+		}
+		key, val, ok := cutField(trimmed, ":")
+		if !ok {
+			continue
+		}
+		st.Fields[key] = val
+		switch strings.ToLower(key) {
+		case "spanning-tree's state", "spanning tree state", "state":
+			st.Enabled = isEnabled(val)
+		case "spanning-tree's mode", "spanning tree mode", "mode":
+			st.Mode = strings.Fields(val + " ")[0]
+		case "local bridge", "bridge id", "bridge":
+			st.BridgeID = val
+		case "root bridge", "root id", "root", "cist root bridge":
+			st.RootID = val
+		case "root port":
+			st.RootPort = portID(val)
+		case "extrpc", "root path cost", "external root path cost", "cist root path cost":
+			cost, err := parseInt(val)
+			if err != nil {
+				if err := o.malformed(lineError(n, trimmed, key, err)); err != nil {
+					return SpanningTree{}, err
+				}
+				continue
+			}
+			st.RootPathCost = cost
+		}
+	}
+
+	for _, t := range Tables(output) {
+		var rows []stpRow
+		if err := t.Decode(&rows, opts...); err != nil {
+			return SpanningTree{}, err
+		}
+		for _, r := range rows {
+			if r.Port != "" {
+				st.Ports = append(st.Ports, r.port())
+			}
+		}
+	}
+	return st, nil
+}
+
+// stpRow is a row of the spanning-tree port table. Firmware prints the
+// administrative state ("Enable") and the forwarding state ("Forwarding")
+// in columns headed "State" or "Status", in either order.
+type stpRow struct {
+	Port             PortID `table:"interface,port,required"`
+	State            string `table:"state"`
+	Status           string `table:"status,sts"`
+	Priority         int    `table:"prio,priority,port priority"`
+	PathCost         int    `table:"ext-cost,cost,path cost,ext-path-cost,port cost"`
+	InternalCost     int    `table:"int-cost,int-path-cost"`
+	Role             string `table:"role"`
+	DesignatedBridge string `table:"designated-bridge,designated bridge"`
+	Edge             string `table:"edge,edge port,edge-port"`
+	LinkType         string `table:"p2p,link type,link-type"`
+}
+
+func (r *stpRow) validate() error {
+	return checkPort(r.Port)
+}
+
+// port returns the STPPort of r.
+func (r *stpRow) port() STPPort {
+	p := STPPort{
+		Port:             r.Port,
+		Priority:         r.Priority,
+		PathCost:         r.PathCost,
+		InternalCost:     r.InternalCost,
+		Role:             r.Role,
+		DesignatedBridge: r.DesignatedBridge,
+		Edge:             r.Edge,
+		LinkType:         r.LinkType,
+	}
+	adminState := false
+	for _, v := range []string{r.State, r.Status} {
+		switch strings.ToLower(v) {
+		case "enable", "enabled":
+			p.Enabled, adminState = true, true
+		case "disable":
+			adminState = true
+		case "":
+		default:
+			p.State = v
+		}
+	}
+	if !adminState {
+		p.Enabled = p.State != "" && !strings.EqualFold(p.State, "disabled")
+	}
+	return p
+}
+
+// isEnabled reports whether v reads as an enabled setting.
+func isEnabled(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "enable", "enabled", "on", "yes", "true":
+		return true
+	}
+	return false
+}
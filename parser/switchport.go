@@ -0,0 +1,192 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Switchport is the 802.1Q configuration of one port.
+type Switchport struct {
+	Port             PortID `json:"port"`
+	Mode             string `json:"mode"` // "access", "trunk" or "general"
+	PVID             int    `json:"pvid"`
+	AllowedVLANs     []int  `json:"allowed_vlans"`
+	UntaggedVLANs    []int  `json:"untagged_vlans,omitempty"`
+	TaggedVLANs      []int  `json:"tagged_vlans,omitempty"`
+	AcceptableFrames string `json:"acceptable_frames,omitempty"`
+	IngressChecking  bool   `json:"ingress_checking"`
+}
+
+// ParseSwitchport parses "show interface switchport": one block per port,
+// headed "Port Gi1/0/1:", with "key: value" settings and a table of the
+// VLANs the port is a member of and their egress rule. Summary tables with
+// a port, type and PVID column are accepted too.
+func ParseSwitchport(output string, opts ...Option) ([]Switchport, error) {
+	var (
+		ports []Switchport
+		cur   *Switchport
+		cols  []column
+	)
+	o := newOptions(opts)
+	for n, line := range splitLines(output) {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			cols = nil
+			continue
+		}
+		if name, ok := switchportHeader(trimmed); ok {
+			ports = append(ports, Switchport{Port: portID(name)})
+			cur, cols = &ports[len(ports)-1], nil
+			continue
+		}
+		if isSeparator(line) {
+			cols = columnsOf(line)
+			if cur == nil {
+				// A summary table rather than per-port blocks.
+				return parseSwitchportTable(output, o)
+			}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if cols != nil {
+			f := fieldsOf(line, cols)
+			id, err := strconv.Atoi(f[0])
+			if err != nil {
+				continue
+			}
+			cur.AllowedVLANs = append(cur.AllowedVLANs, id)
+			switch strings.ToLower(f[len(f)-1]) {
+			case "untagged", "untag", "u":
+				cur.UntaggedVLANs = append(cur.UntaggedVLANs, id)
+			case "tagged", "tag", "t":
+				cur.TaggedVLANs = append(cur.TaggedVLANs, id)
+			}
+			continue
+		}
+		key, val, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		val = strings.TrimSpace(val)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "type", "link type", "mode", "administrative mode", "port mode":
+			if val != "" {
+				cur.Mode = strings.ToLower(val)
+			}
+		case "pvid", "native vlan", "access vlan", "default vlan":
+			id, err := strconv.Atoi(strings.Fields(val + " ")[0])
+			if err != nil {
+				if err := o.malformed(&ParseError{Line: n + 1, Text: line, Field: "pvid", Err: err}); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			cur.PVID = id
+		case "acceptable frame type", "acceptable frame types":
+			cur.AcceptableFrames = val
+		case "ingress checking", "ingress filtering":
+			cur.IngressChecking = isEnabled(val)
+		case "allowed vlans", "trunking vlans enabled", "vlan list":
+			ids, err := parseVLANList(val)
+			if err != nil {
+				if err := o.malformed(&ParseError{Line: n + 1, Text: line, Field: "allowed vlans", Err: err}); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			cur.AllowedVLANs = append(cur.AllowedVLANs, ids...)
+		}
+	}
+	return ports, nil
+}
+
+// switchportHeader returns the port named by a "Port Gi1/0/1:" block heading.
+func switchportHeader(line string) (string, bool) {
+	rest, ok := strings.CutPrefix(line, "Port ")
+	if !ok {
+		rest, ok = strings.CutPrefix(line, "Name: ")
+	}
+	rest = strings.TrimSuffix(strings.TrimSpace(rest), ":")
+	if !ok || !isPortName(rest) {
+		return "", false
+	}
+	return rest, true
+}
+
+// parseSwitchportTable parses the summary table form of the output.
+func parseSwitchportTable(output string, o *options) ([]Switchport, error) {
+	var ports []Switchport
+	for _, t := range Tables(output) {
+		port := t.Col("port", "interface")
+		if port < 0 {
+			continue
+		}
+		mode := t.Col("type", "mode", "link type")
+		pvid := t.Col("pvid", "native vlan", "access vlan")
+		allowed := t.Col("allowed vlans", "vlan", "vlans", "member vlans")
+		frames := t.Col("acceptable frame type", "acceptable frames")
+		ingress := t.Col("ingress checking", "ingress filtering")
+		for r, row := range t.Rows {
+			if ok, err := o.portRow(t, r, port); !ok {
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			rr := t.reader(r)
+			p := Switchport{
+				Port:             portID(value(row, port)),
+				Mode:             strings.ToLower(value(row, mode)),
+				PVID:             rr.int(pvid),
+				AcceptableFrames: value(row, frames),
+				IngressChecking:  isEnabled(value(row, ingress)),
+			}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if v := value(row, allowed); v != "" {
+				ids, err := parseVLANList(v)
+				if err != nil {
+					if err := o.malformed(t.rowError(r, t.Headers[allowed], err)); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				p.AllowedVLANs = ids
+			}
+			ports = append(ports, p)
+		}
+	}
+	return ports, nil
+}
+
+// parseVLANList expands a VLAN list such as "1,10-12,20" into IDs.
+func parseVLANList(s string) ([]int, error) {
+	var ids []int
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		lo, hi, isRange := strings.Cut(item, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil {
+				return nil, err
+			}
+		}
+		if last < first || last > 4094 {
+			return nil, fmt.Errorf("invalid VLAN range %q", item)
+		}
+		for id := first; id <= last; id++ {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SystemInfo holds the device details reported by "show system-info".
+type SystemInfo struct {
+	Description     string            `json:"description"`
+	Name            string            `json:"name"`
+	Location        string            `json:"location"`
+	Contact         string            `json:"contact"`
+	HardwareVersion string            `json:"hardware_version"`
+	BootVersion     string            `json:"bootloader_version"`
+	SoftwareVersion string            `json:"software_version"`
+	MACAddress      string            `json:"mac_address"`
+	SystemTime      string            `json:"system_time"`
+	RunningTime     string            `json:"running_time"`
+	SerialNumber    string            `json:"serial_number"`
+	Fields          map[string]string `json:"fields"`
+}
+
+// Model returns the model name from the hardware version, e.g. "SG2210XMP-M2".
+func (s SystemInfo) Model() string {
+	if f := strings.Fields(s.HardwareVersion); len(f) > 0 {
+		return f[0]
+	}
+	return ""
+}
+
+// uptimeRegex matches one part of a running time such as "5 day - 3 hour
+// - 22 min - 6 sec".
+var uptimeRegex = regexp.MustCompile(`(?i)(\d+)\s*(day|hour|hr|min|sec)`)
+
+// Uptime returns the running time as a duration, from the "5 day - 3 hour -
+// 22 min - 6 sec" form or a span such as "02:03:04". A running time that is
+// not printed is 0; one in neither form is an error.
+func (s SystemInfo) Uptime() (time.Duration, error) {
+	parts := uptimeRegex.FindAllStringSubmatch(s.RunningTime, -1)
+	if len(parts) == 0 {
+		d, ok := parseSpanOK(s.RunningTime)
+		if !ok && !isPlaceholder(s.RunningTime) {
+			return 0, fmt.Errorf("invalid running time %q", s.RunningTime)
+		}
+		return d, nil
+	}
+	units := map[string]time.Duration{"day": 24 * time.Hour, "hour": time.Hour, "hr": time.Hour, "min": time.Minute, "sec": time.Second}
+	var d time.Duration
+	for _, m := range parts {
+		n, _ := strconv.Atoi(m[1])
+		d += time.Duration(n) * units[strings.ToLower(m[2])]
+	}
+	return d, nil
+}
+
+// ParseSystemInfo parses the "show system-info" key/value listing. A MAC
+// address or running time that does not parse is malformed.
+func ParseSystemInfo(output string, opts ...Option) (SystemInfo, error) {
+	o := newOptions(opts)
+	info := SystemInfo{Fields: make(map[string]string)}
+
+	for i, line := range strings.Split(output, "\n") {
+		key, val, ok := cutField(line, " - ")
+		if !ok {
+			continue
+		}
+		if key == "" {
+			continue
+		}
+		info.Fields[key] = val
+
+		switch strings.ToLower(key) {
+		case "system description":
+			info.Description = val
+		case "system name", "device name":
+			info.Name = val
+		case "system location":
+			info.Location = val
+		case "contact information":
+			info.Contact = val
+		case "hardware version":
+			info.HardwareVersion = val
+		case "bootloader version":
+			info.BootVersion = val
+		case "software version", "firmware version":
+			info.SoftwareVersion = val
+		case "mac address":
+			if !isPlaceholder(val) && !isMAC(val) {
+				if err := o.malformed(lineError(i, line, "mac address", fmt.Errorf("invalid MAC address %q", val))); err != nil {
+					return SystemInfo{}, err
+				}
+				continue
+			}
+			info.MACAddress = val
+		case "system time":
+			info.SystemTime = val
+		case "running time":
+			if _, ok := parseSpanOK(val); !ok && !isPlaceholder(val) && !uptimeRegex.MatchString(val) {
+				if err := o.malformed(lineError(i, line, "running time", fmt.Errorf("invalid running time %q", val))); err != nil {
+					return SystemInfo{}, err
+				}
+				continue
+			}
+			info.RunningTime = val
+		case "serial number":
+			info.SerialNumber = val
+		}
+	}
+
+	return info, nil
+}
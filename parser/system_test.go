@@ -0,0 +1,29 @@
+package parser_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pascal71/tplink-go/parser"
+)
+
+func TestSystemInfoUptime(t *testing.T) {
+	tests := []struct {
+		running string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"5 day - 3 hour - 22 min - 6 sec", 5*24*time.Hour + 3*time.Hour + 22*time.Minute + 6*time.Second, false},
+		{"02:03:04", 2*time.Hour + 3*time.Minute + 4*time.Second, false},
+		{"00:00:00", 0, false},
+		{"", 0, false},
+		{"N/A", 0, false},
+		{"since Tuesday", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parser.SystemInfo{RunningTime: tt.running}.Uptime()
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("Uptime of %q = %v, %v, want %v with error %v", tt.running, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SystemTime is the clock state from "show system-time" and "show sntp".
+type SystemTime struct {
+	Time       time.Time         `json:"time,omitzero"` // current time, zero if unparsed
+	TimeText   string            `json:"time_text"`     // current time as printed
+	TimeZone   string            `json:"time_zone,omitempty"`
+	Source     string            `json:"source,omitempty"` // e.g. "NTP", "Manual"
+	Synced     bool              `json:"synced"`
+	SyncStatus string            `json:"sync_status,omitempty"`
+	LastSync   string            `json:"last_sync,omitempty"`
+	Servers    []string          `json:"servers"`
+	Fields     map[string]string `json:"fields"`
+}
+
+// Skew returns how far the switch clock is ahead of now, if its time was
+// parsed. Times are read in the printed zone when it is a UTC offset, and as
+// UTC otherwise.
+func (s SystemTime) Skew(now time.Time) (time.Duration, bool) {
+	if s.Time.IsZero() {
+		return 0, false
+	}
+	return s.Time.Sub(now), true
+}
+
+// utcOffsetRegex matches a zone such as "UTC+08:00" or "GMT-5".
+var utcOffsetRegex = regexp.MustCompile(`(?i)(?:UTC|GMT)\s*([+-])(\d{1,2})(?::?(\d\d))?`)
+
+// ParseSystemTime parses "show system-time", "show system-time ntp" and
+// "show sntp" output, alone or concatenated.
+func ParseSystemTime(output string, opts ...Option) (SystemTime, error) {
+	st := SystemTime{Fields: make(map[string]string)}
+	for _, line := range splitLines(output) {
+		key, val, ok := cutField(line, ":")
+		if !ok {
+			continue
+		}
+		st.Fields[key] = val
+		k := strings.ToLower(key)
+		switch {
+		case k == "time" || k == "system time" || k == "current time" || k == "current system time":
+			st.TimeText = val
+		case strings.Contains(k, "time zone") || k == "timezone":
+			st.TimeZone = val
+		case k == "time source" || k == "clock source" || k == "source" || k == "config mode" || k == "time config mode":
+			st.Source = val
+		case strings.Contains(k, "last") && (strings.Contains(k, "update") || strings.Contains(k, "sync")):
+			st.LastSync = val
+		case k == "status" || k == "sync status" || k == "ntp status" || k == "sntp status" || k == "synchronization":
+			st.SyncStatus = val
+			lv := strings.ToLower(val)
+			st.Synced = strings.Contains(lv, "sync") && !strings.Contains(lv, "unsync") && !strings.Contains(lv, "not")
+		case strings.Contains(k, "server"):
+			for _, f := range strings.FieldsFunc(val, func(r rune) bool { return r == ',' || r == ' ' }) {
+				if net.ParseIP(f) != nil || strings.Contains(f, ".") {
+					st.Servers = append(st.Servers, f)
+				}
+			}
+		}
+	}
+	loc := time.UTC
+	if m := utcOffsetRegex.FindStringSubmatch(st.TimeZone); m != nil {
+		h, _ := strconv.Atoi(m[2])
+		mins, _ := strconv.Atoi(m[3])
+		off := h*3600 + mins*60
+		if m[1] == "-" {
+			off = -off
+		}
+		loc = time.FixedZone(strings.ToUpper(m[0]), off)
+	}
+	st.Time = parseClock(st.TimeText, loc)
+	if st.LastSync != "" && st.SyncStatus == "" {
+		st.Synced = parseClock(st.LastSync, loc) != time.Time{}
+	}
+	return st, nil
+}
+
+// parseClock parses a switch clock reading such as
+// "2024-05-01 12:00:01 Wednesday" in loc, ignoring a trailing weekday.
+func parseClock(s string, loc *time.Location) time.Time {
+	f := strings.Fields(s)
+	for n := len(f); n >= 1; n-- {
+		joined := strings.Join(f[:n], " ")
+		for _, layout := range logTimeLayouts {
+			if t, err := time.ParseInLocation(layout, joined, loc); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
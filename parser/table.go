@@ -0,0 +1,237 @@
+package parser
+
+import (
+	"strings"
+)
+
+// splitLines splits output into lines with carriage returns removed.
+func splitLines(output string) []string {
+	return strings.Split(strings.ReplaceAll(output, "\r", ""), "\n")
+}
+
+// cutField splits a "key: value" line, or one with another separator, into
+// its trimmed key and value. Results with a Fields map keep every such line
+// of the output there, keyed as printed, so settings without a typed field
+// are still available.
+func cutField(line, sep string) (key, val string, ok bool) {
+	key, val, ok = strings.Cut(strings.TrimSpace(line), sep)
+	return strings.TrimSpace(key), strings.TrimSpace(val), ok
+}
+
+// isSeparator reports whether line is a table rule such as "----- -----".
+func isSeparator(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return false
+	}
+	for _, r := range line {
+		if r != '-' && r != '=' && r != '+' && r != ' ' {
+			return false
+		}
+	}
+	return true
+}
+
+// column is the byte range of one table column.
+type column struct {
+	start, end int // end is -1 for the last column, which runs to the end of the line
+}
+
+// columnsOf returns the columns marked out by the dash runs of a separator line.
+func columnsOf(sep string) []column {
+	var cols []column
+	start := -1
+	for i := 0; i <= len(sep); i++ {
+		dash := i < len(sep) && (sep[i] == '-' || sep[i] == '=')
+		switch {
+		case dash && start < 0:
+			start = i
+		case !dash && start >= 0:
+			cols = append(cols, column{start, i})
+			start = -1
+		}
+	}
+	if len(cols) > 0 {
+		cols[len(cols)-1].end = -1
+	}
+	return cols
+}
+
+// field returns the trimmed text of line in column c.
+func (c column) field(line string) string {
+	if c.start >= len(line) {
+		return ""
+	}
+	end := c.end
+	if end < 0 || end > len(line) {
+		end = len(line)
+	}
+	return strings.TrimSpace(line[c.start:end])
+}
+
+// fieldsOf returns the text of line in each of cols, widening each column to
+// the next column's start so values overrunning their rule are kept whole.
+// A value running on into the next column moves that column's start to the
+// following space.
+func fieldsOf(line string, cols []column) []string {
+	out := make([]string, len(cols))
+	start := 0
+	for i, c := range cols {
+		c.start = max(c.start, start)
+		if i+1 < len(cols) {
+			c.end = cols[i+1].start
+			for c.end > 0 && c.end < len(line) && line[c.end-1] != ' ' && line[c.end] != ' ' {
+				c.end++
+			}
+			start = c.end
+		}
+		out[i] = c.field(line)
+	}
+	return out
+}
+
+// headersOf returns the lower-cased column headings of header.
+func headersOf(header string, cols []column) []string {
+	h := fieldsOf(header, cols)
+	for i := range h {
+		h[i] = strings.ToLower(h[i])
+	}
+	return h
+}
+
+// Table is a column-aligned table found in command output. Column
+// boundaries come from the separator rule under the headings, or from the
+// headings themselves, and rows are split on them rather than on spaces, so
+// values containing spaces stay whole. Outputs made of "key: value" blocks,
+// such as LLDP neighbors, mirror sessions and interface counters, are not
+// tables; their parsers read them line by line with cutField.
+type Table struct {
+	Headers []string   // lower-cased column headings
+	Rows    [][]string // the fields of each row, one per column
+
+	lines []int    // 1-based line number of each row in the output
+	texts []string // each row as printed
+}
+
+// addRow appends the fields of line, number n (1-based), to t.
+func (t *Table) addRow(line string, n int, cols []column) {
+	t.Rows = append(t.Rows, fieldsOf(line, cols))
+	t.lines = append(t.lines, n)
+	t.texts = append(t.texts, line)
+}
+
+// rowError returns a ParseError for row r of t failing in field.
+func (t Table) rowError(r int, field string, err error) *ParseError {
+	pe := &ParseError{Field: field, Err: err}
+	if r < len(t.lines) {
+		pe.Line, pe.Text = t.lines[r], t.texts[r]
+	} else {
+		pe.Text = strings.Join(t.Rows[r], " ")
+	}
+	return pe
+}
+
+// isFooter reports whether line is a summary printed under a table, such as
+// "Total MAC Addresses for this criterion: 2", which ends the table.
+func isFooter(line string) bool {
+	f := strings.Fields(line)
+	return len(f) > 0 && strings.EqualFold(strings.TrimSuffix(f[0], ":"), "total")
+}
+
+// Tables returns the tables in output. A table is the heading line above
+// a separator rule and the rows below it, up to the next blank line or
+// summary line.
+func Tables(output string) []Table {
+	var (
+		tables []Table
+		cur    *Table
+		cols   []column
+		prev   string
+	)
+	for n, line := range splitLines(output) {
+		switch {
+		case isSeparator(line) && strings.TrimSpace(prev) != "":
+			cols = columnsOf(line)
+			tables = append(tables, Table{Headers: headersOf(prev, cols)})
+			cur = &tables[len(tables)-1]
+		case strings.TrimSpace(line) == "" || isFooter(line):
+			cur = nil
+		case cur != nil:
+			cur.addRow(line, n+1, cols)
+		}
+		prev = line
+	}
+	return tables
+}
+
+// Col returns the index of the first column headed by one of names, or -1.
+// Names are lower-case; a name ending in "*" matches headings starting with
+// the rest, such as "lease*" for "lease(sec)".
+func (t Table) Col(names ...string) int {
+	for _, name := range names {
+		prefix, wild := strings.CutSuffix(name, "*")
+		for i, h := range t.Headers {
+			if h == name || wild && strings.HasPrefix(h, prefix) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// value returns row's field in column i, or "" if i is -1.
+func value(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return row[i]
+}
+
+// FindTable returns the table whose heading line starts with first, such
+// as "Interface". Without a separator rule below the heading, columns start
+// at each heading word separated from the previous one by two or more
+// spaces; rows run up to the next blank line or summary line.
+func FindTable(output, first string) (Table, bool) {
+	lines := splitLines(output)
+	for i, line := range lines {
+		f := strings.Fields(line)
+		if len(f) < 2 || !strings.EqualFold(f[0], first) {
+			continue
+		}
+		var cols []column
+		first := i + 1 // index of the first row
+		if first < len(lines) && isSeparator(lines[first]) {
+			cols = columnsOf(lines[first])
+			first++
+		} else {
+			cols = columnsOfHeading(line)
+		}
+		t := Table{Headers: headersOf(line, cols)}
+		for j := first; j < len(lines) && strings.TrimSpace(lines[j]) != "" && !isFooter(lines[j]); j++ {
+			t.addRow(lines[j], j+1, cols)
+		}
+		return t, true
+	}
+	return Table{}, false
+}
+
+// columnsOfHeading returns columns starting at each heading of a line whose
+// headings are separated by at least two spaces.
+func columnsOfHeading(line string) []column {
+	var cols []column
+	for i := 0; i < len(line); i++ {
+		if line[i] == ' ' {
+			continue
+		}
+		if len(cols) == 0 || (i >= 2 && line[i-1] == ' ' && line[i-2] == ' ') {
+			cols = append(cols, column{start: i})
+		}
+	}
+	for i := range cols {
+		cols[i].end = -1
+		if i+1 < len(cols) {
+			cols[i].end = cols[i+1].start
+		}
+	}
+	return cols
+}
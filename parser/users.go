@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UserSession is a logged-in management session.
+type UserSession struct {
+	User       string        `json:"user"`
+	Connection string        `json:"connection"` // "ssh", "telnet", "console", "web" or as printed
+	SourceIP   string        `json:"source_ip,omitempty"`
+	Idle       time.Duration `json:"idle"`
+	Current    bool          `json:"current,omitempty"` // the session running the command, marked "*"
+}
+
+// ParseUserSessions parses "show users" (or "show user"): a table with a
+// user column and some of line/type, IP address or location, and idle time
+// columns.
+func ParseUserSessions(output string, opts ...Option) ([]UserSession, error) {
+	o := newOptions(opts)
+	var sessions []UserSession
+	for _, t := range Tables(output) {
+		user := t.Col("user", "user name", "username", "name")
+		if user < 0 {
+			continue
+		}
+		kind := t.Col("connection", "type", "connection type", "line", "mode")
+		ip := t.Col("ip address", "ip", "location", "host", "host(s)", "source", "from")
+		idle := t.Col("idle", "idle time", "idle(s)")
+		for r, row := range t.Rows {
+			if value(row, user) == "" {
+				continue
+			}
+			rr := t.reader(r)
+			s := UserSession{User: value(row, user), Connection: connectionType(value(row, kind)), Idle: rr.span(idle)}
+			if rr.err != nil {
+				if err := o.malformed(rr.err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			s.Current = strings.HasPrefix(strings.Join(row, " "), "*")
+			s.User = strings.TrimSpace(strings.TrimPrefix(s.User, "*"))
+			for _, i := range []int{ip, t.Col("location"), t.Col("host(s)")} {
+				if a := value(row, i); net.ParseIP(a) != nil {
+					s.SourceIP = a
+				}
+			}
+			if s.Connection == "" && s.SourceIP == "" {
+				s.Connection = "console"
+			}
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions, nil
+}
+
+// connectionType normalises the line or type column of a session.
+func connectionType(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case lower == "":
+		return ""
+	case strings.Contains(lower, "ssh"):
+		return "ssh"
+	case strings.Contains(lower, "telnet") || strings.Contains(lower, "vty"):
+		return "telnet"
+	case strings.Contains(lower, "con"):
+		return "console"
+	case strings.Contains(lower, "web") || strings.Contains(lower, "http"):
+		return "web"
+	}
+	return s
+}
+
+// parseSpan parses a time span such as "00:01:12", "01:12", "5m" or "30"
+// (seconds), returning 0 for anything else such as "infinite".
+func parseSpan(s string) time.Duration {
+	d, _ := parseSpanOK(s)
+	return d
+}
+
+// parseSpanOK is parseSpan reporting whether s is a time span.
+func parseSpanOK(s string) (time.Duration, bool) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, true
+	}
+	parts := strings.Split(s, ":")
+	var d time.Duration
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return 0, false
+		}
+		d = d*60 + time.Duration(n)
+	}
+	return d * time.Second, true
+}
@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// VLAN is one 802.1Q VLAN and its member ports.
+type VLAN struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	Status   string   `json:"status,omitempty"`
+	Ports    []PortID `json:"ports"`    // all member ports
+	Untagged []PortID `json:"untagged"` // members known to egress untagged
+	Tagged   []PortID `json:"tagged"`   // members known to egress tagged
+}
+
+// ParseVLANs parses the "show vlan" and "show vlan brief" tables. Ports
+// marked "(u)" or "(t)" are also listed under Untagged or Tagged; the brief
+// table does not mark ports. The "show vlan id" detail listing, with
+// "Untagged Ports:" and "Tagged Ports:" lines, is parsed too.
+func ParseVLANs(output string, opts ...Option) ([]VLAN, error) {
+	var (
+		vlans []VLAN
+		cur   *VLAN
+	)
+	o := newOptions(opts)
+	for n, line := range splitLines(output) {
+		if isSeparator(line) {
+			break // the table, decoded below
+		}
+		key, val, ok := cutField(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "vlan id", "vlan":
+			id, err := strconv.Atoi(val)
+			if err != nil {
+				if err := o.malformed(&ParseError{Line: n + 1, Text: line, Field: "vlan id", Err: err}); err != nil {
+					return nil, err
+				}
+				cur = nil
+				continue
+			}
+			vlans = append(vlans, VLAN{ID: id})
+			cur = &vlans[len(vlans)-1]
+		case "name", "vlan name":
+			if cur != nil {
+				cur.Name = val
+			}
+		case "status":
+			if cur != nil {
+				cur.Status = val
+			}
+		case "untagged ports", "untagged port", "untagged member ports":
+			if cur != nil {
+				cur.addPorts(val, "u")
+			}
+		case "tagged ports", "tagged port", "tagged member ports":
+			if cur != nil {
+				cur.addPorts(val, "t")
+			}
+		}
+	}
+
+	for _, t := range Tables(output) {
+		var rows []vlanRow
+		if err := t.Decode(&rows, opts...); err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			if r.ID == 0 {
+				// Continuation of the previous VLAN's port list.
+				if len(vlans) > 0 {
+					vlans[len(vlans)-1].addPorts(r.Ports, "")
+				}
+				continue
+			}
+			v := VLAN{ID: r.ID, Name: r.Name, Status: r.Status}
+			v.addPorts(r.Ports, "")
+			vlans = append(vlans, v)
+		}
+	}
+	return vlans, nil
+}
+
+// vlanRow is a row of the "show vlan" table.
+type vlanRow struct {
+	ID     int    `table:"vlan,vlan id,vid,required"`
+	Name   string `table:"name,vlan name"`
+	Status string `table:"status"`
+	Ports  string `table:"ports,member ports,members,ports*"`
+}
+
+// addPorts adds the ports in list, filing them by their marker or by tag.
+func (v *VLAN) addPorts(list, tag string) {
+	for _, p := range markedPorts(list) {
+		v.Ports = append(v.Ports, p.name)
+		marker := p.marker
+		if marker == "" {
+			marker = tag
+		}
+		switch strings.ToLower(marker) {
+		case "u", "untagged":
+			v.Untagged = append(v.Untagged, p.name)
+		case "t", "tagged":
+			v.Tagged = append(v.Tagged, p.name)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VoiceVLAN is the voice VLAN configuration.
+type VoiceVLAN struct {
+	Enabled      bool              `json:"enabled"`
+	VLAN         int               `json:"vlan,omitempty"`
+	Priority     int               `json:"priority,omitempty"`      // 802.1p priority of voice traffic
+	AgingMinutes int               `json:"aging_minutes,omitempty"` // how long a port stays a member without voice traffic
+	OUIs         []VoiceOUI        `json:"ouis"`
+	Ports        []VoiceVLANPort   `json:"ports"`
+	Fields       map[string]string `json:"fields"`
+}
+
+// VoiceOUI is an entry of the voice OUI table, matching phones by MAC
+// address prefix.
+type VoiceOUI struct {
+	OUI         string `json:"oui"`
+	Mask        string `json:"mask,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// VoiceVLANPort is the voice VLAN setting of one port.
+type VoiceVLANPort struct {
+	Port     PortID `json:"port"`
+	Mode     string `json:"mode"` // e.g. "Auto", "Manual", "Disable"
+	Security bool   `json:"security"`
+	Member   bool   `json:"member"`          // the port is currently in the voice VLAN
+	State    string `json:"state,omitempty"` // member state as printed
+}
+
+// ParseVoiceVLAN parses "show voice vlan", including the OUI and port
+// tables of "show voice vlan oui-table" and "show voice vlan interface",
+// alone or concatenated.
+func ParseVoiceVLAN(output string, opts ...Option) (VoiceVLAN, error) {
+	o := newOptions(opts)
+	v := VoiceVLAN{Fields: make(map[string]string)}
+	for i, line := range splitLines(output) {
+		if f := strings.Fields(line); len(f) > 0 && isMAC(f[0]) {
+			continue // an OUI table row
+		}
+		line = strings.TrimSpace(line)
+		key, val, ok := cutField(line, ":")
+		if !ok {
+			continue
+		}
+		v.Fields[key] = val
+		var num *int // field of v taking a number
+		switch strings.TrimPrefix(strings.ToLower(key), "voice vlan ") {
+		case "status", "state", "voice vlan", "global state":
+			v.Enabled = isEnabled(val)
+		case "id", "vlan", "vlan id":
+			num = &v.VLAN
+		case "priority", "cos", "voice priority":
+			num = &v.Priority
+		case "aging time", "aging", "aging time(minutes)":
+			num = &v.AgingMinutes
+		}
+		if num == nil {
+			continue
+		}
+		n, err := parseInt(val)
+		if err != nil {
+			if err := o.malformed(lineError(i, line, key, err)); err != nil {
+				return VoiceVLAN{}, err
+			}
+			continue
+		}
+		*num = n
+	}
+
+	for _, t := range Tables(output) {
+		oui := t.Col("oui address", "oui", "mac address", "oui-address")
+		if oui < 0 {
+			continue
+		}
+		mask := t.Col("mask", "oui mask")
+		desc := t.Col("description", "desc")
+		for r, row := range t.Rows {
+			if value(row, oui) == "" {
+				continue
+			}
+			if !isMAC(value(row, oui)) {
+				err := t.rowError(r, t.Headers[oui], fmt.Errorf("invalid OUI %q", value(row, oui)))
+				if err := o.malformed(err); err != nil {
+					return VoiceVLAN{}, err
+				}
+				continue
+			}
+			v.OUIs = append(v.OUIs, VoiceOUI{
+				OUI:         value(row, oui),
+				Mask:        value(row, mask),
+				Description: value(row, desc),
+			})
+		}
+	}
+
+	t, ok := FindTable(output, "port")
+	if !ok {
+		t, ok = FindTable(output, "interface")
+	}
+	if !ok {
+		return v, nil
+	}
+	mode := t.Col("mode", "voice vlan mode", "port mode")
+	security := t.Col("security", "security mode", "security state")
+	state := t.Col("member state", "member", "state", "status")
+	for r, row := range t.Rows {
+		if ok, err := o.portRow(t, r, 0); !ok {
+			if err != nil {
+				return VoiceVLAN{}, err
+			}
+			continue
+		}
+		p := VoiceVLANPort{
+			Port:     portID(value(row, 0)),
+			Mode:     value(row, mode),
+			Security: isEnabled(value(row, security)),
+			State:    value(row, state),
+		}
+		s := strings.ToLower(p.State)
+		p.Member = s == "active" || s == "yes" || s == "true" || s == "member"
+		v.Ports = append(v.Ports, p)
+	}
+	return v, nil
+}
@@ -0,0 +1,65 @@
+package profile
+
+import "regexp"
+
+// commonCommands are the show commands shared by the JetStream CLI.
+var commonCommands = map[string]string{
+	CmdSystemInfo:        "show system-info",
+	CmdRunningConfig:     "show running-config",
+	CmdStartupConfig:     "show startup-config",
+	CmdInterfaceCounters: "show interface counters",
+	CmdInterfaceStatus:   "show interface status",
+	CmdInterfaceDescr:    "show interface description",
+	CmdInterfaceConfig:   "show interface configuration",
+	CmdMACTable:          "show mac address-table all",
+	CmdVLAN:              "show vlan",
+	CmdSwitchport:        "show interface switchport",
+	CmdCPU:               "show cpu-utilization",
+	CmdIPInterfaces:      "show ip interface brief",
+	CmdStack:             "show stack",
+	CmdLAG:               "show etherchannel summary",
+	CmdSNMP:              "show snmp-server",
+	CmdSNMPCommunity:     "show snmp-server community",
+	CmdSNMPUser:          "show snmp-server user",
+	CmdSNMPHost:          "show snmp-server host",
+}
+
+// jetStreamPrompt matches the JetStream CLI prompt whatever the hostname:
+// the hostname, an optional configuration mode in parentheses and > or #.
+var jetStreamPrompt = regexp.MustCompile(`(?m)[\r\n]*([A-Za-z0-9][\w.\-]*(\([^)]*\))?[>#])\s*$`)
+
+// Default is used for models without a registered profile.
+var Default = Profile{
+	Family:   "JetStream",
+	Enable:   "enable",
+	Paging:   []string{"config", "no clipaging", "exit"},
+	Commands: commonCommands,
+}
+
+func init() {
+	Register(family("SG2210XMP", []string{"SG2210XMP"}, true))
+	Register(family("SG3428", []string{"SG3428"}, false))
+	Register(family("SG3428XMP", []string{"SG3428XMP", "SG3428MP"}, true))
+	Register(family("SG2008", []string{"SG2008"}, false))
+	Register(family("SG2008P", []string{"SG2008P"}, true))
+	Register(family("SX3008F", []string{"SX3008F"}, false))
+}
+
+// family builds a JetStream profile, adding the PoE commands when poe is set.
+// Unlike Default, it matches the prompt of a renamed switch.
+func family(name string, prefixes []string, poe bool) Profile {
+	cmds := make(map[string]string, len(commonCommands)+2)
+	for k, v := range commonCommands {
+		cmds[k] = v
+	}
+	if poe {
+		cmds[CmdPoEInterfaces] = "show power inline information interface"
+		cmds[CmdPoEConfig] = "show power inline configuration interface"
+	}
+	p := Default
+	p.Family = name
+	p.Prefixes = prefixes
+	p.Prompt = jetStreamPrompt
+	p.Commands = cmds
+	return p
+}
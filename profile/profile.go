@@ -0,0 +1,131 @@
+// Package profile describes the CLI conventions of TP-Link switch model families.
+package profile
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pascal71/tplink-go/client"
+	"github.com/pascal71/tplink-go/parser"
+)
+
+// Logical names of show commands, used as keys of Profile.Commands.
+const (
+	CmdSystemInfo        = "system-info"
+	CmdRunningConfig     = "running-config"
+	CmdStartupConfig     = "startup-config"
+	CmdInterfaceCounters = "interface-counters"
+	CmdInterfaceStatus   = "interface-status"
+	CmdInterfaceDescr    = "interface-description"
+	CmdInterfaceConfig   = "interface-config"
+	CmdPoEInterfaces     = "poe-interfaces"
+	CmdPoEConfig         = "poe-config"
+	CmdMACTable          = "mac-table"
+	CmdVLAN              = "vlan"
+	CmdSwitchport        = "switchport"
+	CmdCPU               = "cpu"
+	CmdIPInterfaces      = "ip-interfaces"
+	CmdStack             = "stack"
+	CmdLAG               = "lag"
+	CmdSNMP              = "snmp"
+	CmdSNMPCommunity     = "snmp-community"
+	CmdSNMPUser          = "snmp-user"
+	CmdSNMPHost          = "snmp-host"
+)
+
+// Profile describes how to drive the CLI of one switch model family.
+type Profile struct {
+	Family   string            // Model family, e.g. "SG2210XMP"
+	Prefixes []string          // Hardware version prefixes matching this family
+	Enable   string            // Command entering privileged mode
+	Paging   []string          // Commands run in privileged mode to disable paging
+	Prompt   *regexp.Regexp    // CLI prompt for any hostname, nil to keep the client's
+	Commands map[string]string // Supported show commands by logical name
+}
+
+// Command returns the CLI command for the logical name, if the family supports it.
+func (p Profile) Command(name string) (string, bool) {
+	cmd, ok := p.Commands[name]
+	return cmd, ok
+}
+
+// Setup enters privileged mode and disables paging on c.
+func (p Profile) Setup(ctx context.Context, c client.Interface) error {
+	for _, cmd := range append([]string{p.Enable}, p.Paging...) {
+		if _, err := c.RunCommand(ctx, cmd); err != nil {
+			return fmt.Errorf("%s: %w", cmd, err)
+		}
+	}
+	return nil
+}
+
+var (
+	mu       sync.RWMutex
+	profiles = make(map[string]Profile)
+)
+
+// Register adds p to the registry, replacing any profile of the same family.
+func Register(p Profile) {
+	mu.Lock()
+	defer mu.Unlock()
+	profiles[p.Family] = p
+}
+
+// Lookup returns the registered profile for family.
+func Lookup(family string) (Profile, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	p, ok := profiles[family]
+	return p, ok
+}
+
+// Families returns the names of all registered families, sorted.
+func Families() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Match returns the profile whose hardware version prefix best matches model,
+// e.g. "SG3428XMP" selects the SG3428 family.
+func Match(model string) (Profile, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	var best Profile
+	bestLen := 0
+	model = strings.ToUpper(model)
+	for _, p := range profiles {
+		for _, prefix := range p.Prefixes {
+			if len(prefix) > bestLen && strings.HasPrefix(model, strings.ToUpper(prefix)) {
+				best, bestLen = p, len(prefix)
+			}
+		}
+	}
+	return best, bestLen > 0
+}
+
+// Detect runs "show system-info" on c and selects the matching profile,
+// falling back to Default for unknown models.
+func Detect(ctx context.Context, c client.Interface) (Profile, parser.SystemInfo, error) {
+	out, err := c.RunCommand(ctx, Default.Commands[CmdSystemInfo])
+	if err != nil {
+		return Profile{}, parser.SystemInfo{}, err
+	}
+	info, err := parser.ParseSystemInfo(out)
+	if err != nil {
+		return Profile{}, info, err
+	}
+	if p, ok := Match(info.Model()); ok {
+		return p, info, nil
+	}
+	return Default, info, nil
+}
@@ -0,0 +1,183 @@
+package profile_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/pascal71/tplink-go/profile"
+)
+
+func TestFamilies(t *testing.T) {
+	families := profile.Families()
+	for _, want := range []string{"SG2008", "SG2008P", "SG2210XMP", "SG3428", "SG3428XMP", "SX3008F"} {
+		if !slices.Contains(families, want) {
+			t.Errorf("Families() = %q, missing %s", families, want)
+		}
+	}
+	if !slices.IsSorted(families) {
+		t.Errorf("Families() = %q, want sorted", families)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, tc := range []struct {
+		family string
+		poe    bool
+	}{
+		{"SG2210XMP", true},
+		{"SG3428", false},
+		{"SG3428XMP", true},
+		{"SG2008P", true},
+		{"SX3008F", false},
+	} {
+		p, ok := profile.Lookup(tc.family)
+		if !ok {
+			t.Errorf("Lookup(%q) found nothing", tc.family)
+			continue
+		}
+		if p.Family != tc.family || p.Enable != "enable" || p.Prompt == nil {
+			t.Errorf("Lookup(%q) = %+v, want the family with an enable command and a prompt", tc.family, p)
+		}
+		if _, ok := p.Command(profile.CmdPoEConfig); ok != tc.poe {
+			t.Errorf("%s has PoE commands = %v, want %v", tc.family, ok, tc.poe)
+		}
+		if cmd, _ := p.Command(profile.CmdSystemInfo); cmd != "show system-info" {
+			t.Errorf("%s system-info command = %q", tc.family, cmd)
+		}
+	}
+	if _, ok := profile.Lookup("T9999"); ok {
+		t.Error("Lookup found an unregistered family")
+	}
+}
+
+func TestRegisterReplaces(t *testing.T) {
+	profile.Register(profile.Profile{Family: "TEST-REPLACE", Enable: "enable"})
+	profile.Register(profile.Profile{Family: "TEST-REPLACE", Enable: "enable 15"})
+	p, ok := profile.Lookup("TEST-REPLACE")
+	if !ok || p.Enable != "enable 15" {
+		t.Errorf("Lookup = %+v, %v; want the second registration", p, ok)
+	}
+	if n := len(slices.DeleteFunc(profile.Families(), func(f string) bool { return f != "TEST-REPLACE" })); n != 1 {
+		t.Errorf("family listed %d times, want once", n)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	for _, tc := range []struct {
+		model  string
+		family string
+	}{
+		{"SG2210XMP-M2", "SG2210XMP"},
+		{"SG3428", "SG3428"},
+		{"SG3428XMP", "SG3428XMP"}, // longest prefix wins over SG3428
+		{"SG3428MP", "SG3428XMP"},
+		{"SG2008P", "SG2008P"},
+		{"sg2008", "SG2008"},
+		{"SX3008F", "SX3008F"},
+	} {
+		p, ok := profile.Match(tc.model)
+		if !ok || p.Family != tc.family {
+			t.Errorf("Match(%q) = %q, %v; want %q", tc.model, p.Family, ok, tc.family)
+		}
+	}
+	if p, ok := profile.Match("T1600G-28TS"); ok {
+		t.Errorf("Match(T1600G-28TS) = %q, want no match", p.Family)
+	}
+}
+
+func TestFamilyPrompt(t *testing.T) {
+	p, _ := profile.Lookup("SG3428XMP")
+	for _, out := range []string{
+		"\r\nSG3428XMP>",
+		"\r\ncore-1.lab#",
+		"\r\ncore1(config)#",
+		"\r\ncore1(config-if-range)# ",
+	} {
+		if !p.Prompt.MatchString(out) {
+			t.Errorf("prompt pattern does not match %q", out)
+		}
+	}
+	for _, out := range []string{
+		"\r\nPassword:",
+		"\r\nPress any key to continue (Q to quit)",
+		"\r\nSG3428XMP#show vlan",
+		"\r\n#",
+	} {
+		if p.Prompt.MatchString(out) {
+			t.Errorf("prompt pattern matches %q", out)
+		}
+	}
+	if profile.Default.Prompt != nil {
+		t.Error("Default replaces the client's prompt pattern")
+	}
+}
+
+// fakeClient answers "show system-info" with info, or fails with err.
+type fakeClient struct {
+	info string
+	err  error
+	cmds []string
+}
+
+func (c *fakeClient) Connect(context.Context) error { return nil }
+func (c *fakeClient) Close()                        {}
+
+func (c *fakeClient) RunCommand(_ context.Context, cmd string) (string, error) {
+	c.cmds = append(c.cmds, cmd)
+	if c.err != nil {
+		return "", c.err
+	}
+	if cmd == "show system-info" {
+		return c.info, nil
+	}
+	return "", nil
+}
+
+func systemInfo(hardware string) string {
+	return ` System Name            - core1
+ Hardware Version       - ` + hardware + `
+ Software Version       - 2.0.5 Build 20211111 Rel.44043(s)
+ Mac Address            - 00-11-22-33-44-55
+`
+}
+
+func TestDetect(t *testing.T) {
+	for _, tc := range []struct {
+		hardware string
+		family   string
+	}{
+		{"SG3428XMP 2.0", "SG3428XMP"},
+		{"SG2210XMP-M2 1.0", "SG2210XMP"},
+		{"SG2008P 3.0", "SG2008P"},
+		{"T1600G-28TS 3.0", "JetStream"}, // unknown models get Default
+	} {
+		c := &fakeClient{info: systemInfo(tc.hardware)}
+		p, info, err := profile.Detect(context.Background(), c)
+		if err != nil {
+			t.Errorf("Detect(%s): %v", tc.hardware, err)
+			continue
+		}
+		if p.Family != tc.family {
+			t.Errorf("Detect(%s) = %q, want %q", tc.hardware, p.Family, tc.family)
+		}
+		if info.Name != "core1" {
+			t.Errorf("Detect(%s) system name = %q", tc.hardware, info.Name)
+		}
+		if !slices.Equal(c.cmds, []string{"show system-info"}) {
+			t.Errorf("Detect(%s) ran %q", tc.hardware, c.cmds)
+		}
+	}
+}
+
+func TestDetectErrors(t *testing.T) {
+	boom := errors.New("boom")
+	if _, _, err := profile.Detect(context.Background(), &fakeClient{err: boom}); !errors.Is(err, boom) {
+		t.Errorf("Detect error = %v, want the client's", err)
+	}
+	bad := &fakeClient{info: " Mac Address            - not-a-mac\n"}
+	if _, _, err := profile.Detect(context.Background(), bad); err == nil {
+		t.Error("Detect accepted malformed system-info")
+	}
+}
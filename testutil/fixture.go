@@ -0,0 +1,47 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+)
+
+// Fixture is a recorded CLI session: the raw bytes a device sent in reply to
+// each command, including echo, ANSI sequences and prompts.
+type Fixture struct {
+	Device       string        `json:"device,omitempty"` // Free-form description, e.g. model and firmware
+	Greeting     string        `json:"greeting"`         // Output sent before the first command
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded command and the device's raw reply.
+type Interaction struct {
+	Command string `json:"command"`
+	Output  string `json:"output"` // Raw reply up to and including the next prompt
+	Prompt  string `json:"prompt"` // Prompt that ended the reply
+}
+
+// LoadFixture reads a fixture written by Fixture.Save.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Save writes the fixture to path as indented JSON.
+func (f *Fixture) Save(path string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
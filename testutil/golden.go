@@ -0,0 +1,173 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pascal71/tplink-go/parser"
+)
+
+// UpdateGoldenEnv names the environment variable that makes Golden rewrite
+// the expected JSON files from the current parser output instead of
+// comparing against them.
+const UpdateGoldenEnv = "TPLINK_UPDATE_GOLDEN"
+
+// GoldenCase is one captured command output and its expected parse result.
+//
+// Cases live under a directory per command, named after the command with
+// spaces replaced by underscores, e.g.
+//
+//	testdata/golden/show_vlan/SG3428XMP-2.0.5.txt
+//	testdata/golden/show_vlan/SG3428XMP-2.0.5.json
+//
+// The .txt file is the raw output of the command and the .json file the
+// expected result of parser.ParseModel. The file name, usually the model
+// and firmware version, is passed as the model, so parsers registered for a
+// model family are used for its captures.
+type GoldenCase struct {
+	Command  string // CLI command, from the directory name
+	Name     string // file name without extension
+	Input    string // path of the captured output
+	Expected string // path of the expected JSON
+}
+
+// GoldenCases returns the cases under dir, sorted by command and name.
+func GoldenCases(dir string) ([]GoldenCase, error) {
+	inputs, err := filepath.Glob(filepath.Join(dir, "*", "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(inputs)
+	cases := make([]GoldenCase, 0, len(inputs))
+	for _, in := range inputs {
+		name := strings.TrimSuffix(filepath.Base(in), ".txt")
+		cases = append(cases, GoldenCase{
+			Command:  strings.ReplaceAll(filepath.Base(filepath.Dir(in)), "_", " "),
+			Name:     name,
+			Input:    in,
+			Expected: strings.TrimSuffix(in, ".txt") + ".json",
+		})
+	}
+	return cases, nil
+}
+
+// Result parses the captured output and returns the result as indented
+// JSON, as stored in the expected file.
+func (c GoldenCase) Result() ([]byte, error) {
+	raw, err := os.ReadFile(c.Input)
+	if err != nil {
+		return nil, err
+	}
+	v, err := parser.ParseModel(c.Command, c.Name, string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.Input, err)
+	}
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// Check compares the parse result with the expected JSON. It returns a
+// description of the first difference, or "" if they match.
+func (c GoldenCase) Check() (string, error) {
+	got, err := c.Result()
+	if err != nil {
+		return "", err
+	}
+	want, err := os.ReadFile(c.Expected)
+	if err != nil {
+		return "", err
+	}
+	return jsonDiff(got, want)
+}
+
+// Update rewrites the expected JSON from the current parse result.
+func (c GoldenCase) Update() error {
+	got, err := c.Result()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.Expected, got, 0o644)
+}
+
+// Golden runs each case under dir as a subtest of t, failing those whose
+// parse result differs from the expected JSON. With UpdateGoldenEnv set, the
+// expected files are rewritten instead. A test using it is one line:
+//
+//	func TestGolden(t *testing.T) { testutil.Golden(t, "testdata/golden") }
+func Golden(t *testing.T, dir string) {
+	t.Helper()
+	cases, err := GoldenCases(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) == 0 {
+		t.Fatalf("no golden cases under %s", dir)
+	}
+	update := os.Getenv(UpdateGoldenEnv) != ""
+	for _, c := range cases {
+		t.Run(c.Command+"/"+c.Name, func(t *testing.T) {
+			if update {
+				if err := c.Update(); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			diff, err := c.Check()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff != "" {
+				t.Errorf("%s: %s", c.Input, diff)
+			}
+		})
+	}
+}
+
+// jsonDiff compares two JSON documents by value, so formatting and key
+// order do not matter, and describes the first differing line of their
+// indented forms.
+func jsonDiff(got, want []byte) (string, error) {
+	g, err := canonicalJSON(got)
+	if err != nil {
+		return "", fmt.Errorf("parse result: %w", err)
+	}
+	w, err := canonicalJSON(want)
+	if err != nil {
+		return "", fmt.Errorf("expected JSON: %w", err)
+	}
+	if bytes.Equal(g, w) {
+		return "", nil
+	}
+	gl, wl := strings.Split(string(g), "\n"), strings.Split(string(w), "\n")
+	for i := 0; i < len(gl) || i < len(wl); i++ {
+		var a, b string
+		if i < len(gl) {
+			a = gl[i]
+		}
+		if i < len(wl) {
+			b = wl[i]
+		}
+		if a != b {
+			return fmt.Sprintf("line %d: got %s, want %s", i+1, strings.TrimSpace(a), strings.TrimSpace(b)), nil
+		}
+	}
+	return "results differ", nil
+}
+
+// canonicalJSON re-encodes data with sorted keys and fixed indentation.
+func canonicalJSON(data []byte) ([]byte, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(v, "", "  ")
+}
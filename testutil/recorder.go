@@ -0,0 +1,70 @@
+package testutil
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pascal71/tplink-go/client"
+)
+
+// Recorder wraps a client connected to a real device and records every
+// command and raw reply into a Fixture.
+type Recorder struct {
+	c *client.Client
+
+	mu      sync.Mutex
+	fixture Fixture
+}
+
+var _ client.Interface = (*Recorder)(nil)
+
+// NewRecorder returns a Recorder for c, which must not be connected yet.
+// device describes the recorded switch in the fixture.
+func NewRecorder(c *client.Client, device string) *Recorder {
+	return &Recorder{c: c, fixture: Fixture{Device: device}}
+}
+
+// Connect connects the client and records the greeting. An empty line is sent
+// so the greeting ends with a fresh prompt that replay can serve on its own.
+func (r *Recorder) Connect(ctx context.Context) error {
+	if err := r.c.Connect(ctx); err != nil {
+		return err
+	}
+	f, err := r.c.RunCommandFrame(ctx, "", client.RawOutput())
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.fixture.Greeting = string(f.Raw)
+	r.mu.Unlock()
+	return nil
+}
+
+// RunCommand runs cmd on the device and records its raw reply.
+func (r *Recorder) RunCommand(ctx context.Context, cmd string) (string, error) {
+	f, err := r.c.RunCommandFrame(ctx, cmd, client.RawOutput())
+	if f.Raw != nil {
+		r.mu.Lock()
+		r.fixture.Interactions = append(r.fixture.Interactions, Interaction{
+			Command: cmd,
+			Output:  string(f.Raw),
+			Prompt:  f.Prompt,
+		})
+		r.mu.Unlock()
+	}
+	return f.Payload, err
+}
+
+// Close closes the client.
+func (r *Recorder) Close() {
+	r.c.Close()
+}
+
+// Fixture returns a copy of everything recorded so far.
+func (r *Recorder) Fixture() *Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := r.fixture
+	f.Interactions = append([]Interaction(nil), r.fixture.Interactions...)
+	return &f
+}
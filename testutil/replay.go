@@ -0,0 +1,99 @@
+package testutil
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/pascal71/tplink-go/client"
+)
+
+// ReplayTransport is a client.Transport serving the replies of a Fixture.
+// Each command gets its recorded replies in order, repeating the last one
+// once they run out; unrecorded commands are answered with a CLI error.
+type ReplayTransport struct {
+	fixture *Fixture
+
+	mu      sync.Mutex
+	served  map[string]int
+	prompt  string
+	closers []io.Closer
+}
+
+var _ client.Transport = (*ReplayTransport)(nil)
+
+// NewReplayTransport returns a transport replaying f. Use it with
+// client.WithTransport.
+func NewReplayTransport(f *Fixture) *ReplayTransport {
+	return &ReplayTransport{fixture: f}
+}
+
+// Open starts a replay session, sending the recorded greeting first.
+func (t *ReplayTransport) Open(ctx context.Context) (io.Writer, io.Reader, error) {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+
+	t.mu.Lock()
+	t.served = make(map[string]int)
+	t.prompt = lastLine(t.fixture.Greeting)
+	t.closers = []io.Closer{inR, inW, outR, outW}
+	t.mu.Unlock()
+
+	go func() {
+		defer outW.Close()
+		if _, err := io.WriteString(outW, t.fixture.Greeting); err != nil {
+			return
+		}
+		sc := bufio.NewScanner(inR)
+		for sc.Scan() {
+			cmd := strings.TrimSpace(sc.Text())
+			if _, err := io.WriteString(outW, t.reply(cmd)); err != nil {
+				return
+			}
+		}
+	}()
+	return inW, outR, nil
+}
+
+// Close ends the replay session.
+func (t *ReplayTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, c := range t.closers {
+		c.Close()
+	}
+	t.closers = nil
+	return nil
+}
+
+// reply returns the next recorded reply to cmd.
+func (t *ReplayTransport) reply(cmd string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var matches []Interaction
+	for _, in := range t.fixture.Interactions {
+		if strings.TrimSpace(in.Command) == cmd {
+			matches = append(matches, in)
+		}
+	}
+	if len(matches) == 0 {
+		return cmd + "\r\n" + badCommand + "\r\n" + t.prompt
+	}
+	i := min(t.served[cmd], len(matches)-1)
+	t.served[cmd]++
+	if matches[i].Prompt != "" {
+		t.prompt = matches[i].Prompt
+	}
+	return matches[i].Output
+}
+
+// lastLine returns the text after the last line break of s.
+func lastLine(s string) string {
+	if i := strings.LastIndexAny(s, "\r\n"); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
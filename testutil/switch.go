@@ -0,0 +1,499 @@
+// Package testutil provides an in-process SSH server and an in-memory
+// transport emulating the TP-Link switch CLI, so client, parser and CLI tests
+// can run without hardware.
+package testutil
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Mode is a CLI mode of the emulated switch.
+type Mode int
+
+// CLI modes, in the order entered by enable, config and interface.
+const (
+	ModeUser Mode = iota
+	ModePrivileged
+	ModeConfig
+	ModeInterface // interface, VLAN and time-range configuration
+)
+
+// subModes maps the commands entering a configuration sub-mode to the
+// prompt suffix of that mode.
+var subModes = map[string]string{
+	"interface":  "if",
+	"vlan":       "vlan",
+	"time-range": "time-range",
+}
+
+// morePrompt is what the TP-Link CLI prints between pages of output.
+const morePrompt = "Press any key to continue (Q to quit)"
+
+// badCommand is what the TP-Link CLI prints for input it does not understand.
+const badCommand = "Error: Bad command"
+
+// Switch is a fake TP-Link switch reachable over SSH on a loopback port.
+// Configure its fields before calling Start.
+type Switch struct {
+	Hostname       string            // Prompt hostname, defaults to "SG2210XMP-M2"
+	User           string            // Accepted username, defaults to "admin"
+	Password       string            // Accepted password, defaults to "admin"
+	EnablePassword string            // Secret asked for by enable, none when empty
+	Banner         string            // Text printed before the first prompt
+	Responses      map[string]string // Canned output per command, lines separated by "\n"
+	Questions      map[string]string // Confirmation asked before a command runs, e.g. "Continue? (Y/N):"
+	PageLines      int               // Output lines per page until "no clipaging", no paging when 0
+
+	// Handler, when set, is consulted for commands without a canned response
+	// and returns their output and whether the command is known.
+	Handler func(mode Mode, cmd string) (string, bool)
+
+	mu       sync.Mutex
+	listener net.Listener
+	signer   ssh.Signer
+	commands []string
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// NewSwitch returns a Switch with default credentials and no canned responses.
+func NewSwitch() *Switch {
+	return &Switch{
+		Hostname:  "SG2210XMP-M2",
+		User:      "admin",
+		Password:  "admin",
+		Responses: make(map[string]string),
+	}
+}
+
+// SetQuestion makes cmd ask question and run only if answered "y".
+func (s *Switch) SetQuestion(cmd, question string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Questions == nil {
+		s.Questions = make(map[string]string)
+	}
+	s.Questions[cmd] = question
+}
+
+// SetResponse sets the canned output of cmd.
+func (s *Switch) SetResponse(cmd, output string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Responses == nil {
+		s.Responses = make(map[string]string)
+	}
+	s.Responses[cmd] = output
+}
+
+// Start listens on a random loopback port and serves SSH connections until Close.
+func (s *Switch) Start() error {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return err
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.signer = signer
+	s.listener = l
+	s.conns = make(map[net.Conn]struct{})
+	s.mu.Unlock()
+
+	cfg := s.serverConfig()
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.track(conn, true)
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer s.track(conn, false)
+				s.serveConn(conn, cfg)
+			}()
+		}
+	}()
+	return nil
+}
+
+// Addr returns the host:port the switch listens on.
+func (s *Switch) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// HostKey returns the public host key presented to clients.
+func (s *Switch) HostKey() ssh.PublicKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.signer == nil {
+		return nil
+	}
+	return s.signer.PublicKey()
+}
+
+// Commands returns every command received so far, in order.
+func (s *Switch) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+// Sessions returns the number of open connections.
+func (s *Switch) Sessions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// Close stops the listener, drops open connections and waits for them to end.
+func (s *Switch) Close() error {
+	s.mu.Lock()
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// Drop closes the open connections, as a rebooting switch does, and keeps
+// accepting new ones. Handler may call it.
+func (s *Switch) Drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *Switch) track(conn net.Conn, add bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if add {
+		s.conns[conn] = struct{}{}
+	} else {
+		delete(s.conns, conn)
+	}
+}
+
+// serverConfig accepts the configured credentials over password and
+// keyboard-interactive authentication.
+func (s *Switch) serverConfig() *ssh.ServerConfig {
+	cfg := &ssh.ServerConfig{
+		PasswordCallback: func(meta ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if meta.User() == s.User && string(pass) == s.Password {
+				return nil, nil
+			}
+			return nil, errors.New("access denied")
+		},
+		KeyboardInteractiveCallback: func(meta ssh.ConnMetadata, challenge ssh.KeyboardInteractiveChallenge) (*ssh.Permissions, error) {
+			answers, err := challenge(meta.User(), "", []string{"Password: "}, []bool{false})
+			if err != nil {
+				return nil, err
+			}
+			if meta.User() == s.User && len(answers) == 1 && answers[0] == s.Password {
+				return nil, nil
+			}
+			return nil, errors.New("access denied")
+		},
+	}
+	cfg.AddHostKey(s.signer)
+	return cfg
+}
+
+func (s *Switch) serveConn(conn net.Conn, cfg *ssh.ServerConfig) {
+	defer conn.Close()
+	sconn, chans, reqs, err := ssh.NewServerConn(conn, cfg)
+	if err != nil {
+		return
+	}
+	defer sconn.Close()
+	go ssh.DiscardRequests(reqs)
+
+	var wg sync.WaitGroup
+	for nc := range chans {
+		if nc.ChannelType() != "session" {
+			nc.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		ch, requests, err := nc.Accept()
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveSession(ch, requests)
+		}()
+	}
+	wg.Wait()
+}
+
+// serveSession handles pty-req, shell and exec requests on a session channel.
+func (s *Switch) serveSession(ch ssh.Channel, requests <-chan *ssh.Request) {
+	defer ch.Close()
+	for req := range requests {
+		switch req.Type {
+		case "pty-req", "env", "window-change":
+			req.Reply(true, nil)
+		case "shell":
+			req.Reply(true, nil)
+			s.runShell(ch)
+			ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+			return
+		case "exec":
+			var payload struct{ Command string }
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+				req.Reply(false, nil)
+				continue
+			}
+			req.Reply(true, nil)
+			sh := &shell{sw: s, mode: ModePrivileged}
+			out, _ := sh.execute(payload.Command)
+			io.WriteString(ch, crlf(out))
+			status := uint32(0)
+			if strings.Contains(out, badCommand) {
+				status = 1
+			}
+			ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+			return
+		default:
+			req.Reply(false, nil)
+		}
+	}
+}
+
+// runShell runs an interactive CLI session on ch until the user logs out.
+func (s *Switch) runShell(ch io.ReadWriter) {
+	sh := &shell{sw: s, mode: ModeUser, rw: ch}
+	if s.Banner != "" {
+		io.WriteString(ch, crlf(s.Banner)+"\r\n")
+	}
+	for {
+		io.WriteString(ch, "\r\n"+sh.prompt())
+		line, err := sh.readLine(true)
+		if err != nil {
+			return
+		}
+		out, logout := sh.execute(line)
+		if out != "" {
+			if err := sh.write(out); err != nil {
+				return
+			}
+		}
+		if logout {
+			return
+		}
+	}
+}
+
+// shell is the state of one CLI session.
+type shell struct {
+	sw   *Switch
+	mode Mode
+	rw   io.ReadWriter
+	sub  string // prompt suffix of the sub-mode in ModeInterface, e.g. "vlan"
+	last byte   // previous byte read, to fold CR LF into one line end
+
+	noPaging bool // set by "no clipaging"
+}
+
+func (sh *shell) prompt() string {
+	switch sh.mode {
+	case ModePrivileged:
+		return sh.sw.Hostname + "#"
+	case ModeConfig:
+		return sh.sw.Hostname + "(config)#"
+	case ModeInterface:
+		return sh.sw.Hostname + "(config-" + sh.sub + ")#"
+	default:
+		return sh.sw.Hostname + ">"
+	}
+}
+
+// write prints command output, pausing at morePrompt after every PageLines
+// lines while paging is on. Any key shows the next page and q the prompt.
+func (sh *shell) write(out string) error {
+	lines := strings.SplitAfter(crlf(out), "\r\n")
+	page := sh.sw.PageLines
+	if page <= 0 || sh.noPaging {
+		page = len(lines)
+	}
+	for len(lines) > page {
+		io.WriteString(sh.rw, strings.Join(lines[:page], "")+morePrompt)
+		buf := make([]byte, 1)
+		if _, err := sh.rw.Read(buf); err != nil {
+			return err
+		}
+		sh.last = buf[0]
+		io.WriteString(sh.rw, "\r"+strings.Repeat(" ", len(morePrompt))+"\r")
+		if buf[0] == 'q' || buf[0] == 'Q' {
+			return nil
+		}
+		lines = lines[page:]
+	}
+	_, err := io.WriteString(sh.rw, strings.Join(lines, ""))
+	return err
+}
+
+// readLine reads one line of input, echoing it back when echo is set as a
+// PTY would.
+func (sh *shell) readLine(echo bool) (string, error) {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		if _, err := sh.rw.Read(buf); err != nil {
+			return "", err
+		}
+		b := buf[0]
+		prev := sh.last
+		sh.last = b
+		switch b {
+		case '\n':
+			if prev == '\r' {
+				continue
+			}
+			fallthrough
+		case '\r':
+			io.WriteString(sh.rw, "\r\n")
+			return string(line), nil
+		case '\b', 0x7f:
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+				if echo {
+					io.WriteString(sh.rw, "\b \b")
+				}
+			}
+		default:
+			line = append(line, b)
+			if echo {
+				sh.rw.Write(buf)
+			}
+		}
+	}
+}
+
+// execute runs one command line and returns its output and whether the
+// session ends.
+func (sh *shell) execute(line string) (string, bool) {
+	cmd := strings.Join(strings.Fields(line), " ")
+	if cmd == "" {
+		return "", false
+	}
+	sh.sw.mu.Lock()
+	sh.sw.commands = append(sh.sw.commands, cmd)
+	sh.sw.mu.Unlock()
+
+	kind, _, hasArgs := strings.Cut(cmd, " ")
+	switch {
+	case cmd == "enable" && sh.mode == ModeUser:
+		if sh.sw.EnablePassword != "" && sh.rw != nil {
+			io.WriteString(sh.rw, "Password:")
+			pass, err := sh.readLine(false)
+			if err != nil {
+				return "", true
+			}
+			if pass != sh.sw.EnablePassword {
+				return "Error: Bad password", false
+			}
+		}
+		sh.mode = ModePrivileged
+		return "", false
+	case cmd == "enable":
+		return "", false
+	case cmd == "disable" && sh.mode == ModePrivileged:
+		sh.mode = ModeUser
+		return "", false
+	case (cmd == "config" || cmd == "configure") && sh.mode == ModePrivileged:
+		sh.mode = ModeConfig
+		return "", false
+	case sh.mode >= ModeConfig && hasArgs && subModes[kind] != "":
+		sh.mode, sh.sub = ModeInterface, subModes[kind]
+		return "", false
+	case (cmd == "no clipaging" || cmd == "clipaging") && sh.mode == ModeConfig:
+		sh.noPaging = cmd == "no clipaging"
+		return "", false
+	case cmd == "end" && sh.mode >= ModeConfig:
+		sh.mode = ModePrivileged
+		return "", false
+	case cmd == "exit" || cmd == "logout" || cmd == "quit":
+		if sh.mode == ModeUser || cmd != "exit" {
+			return "", true
+		}
+		sh.mode--
+		return "", false
+	}
+
+	sh.sw.mu.Lock()
+	question, ask := sh.sw.Questions[cmd]
+	sh.sw.mu.Unlock()
+	if ask && sh.rw != nil {
+		io.WriteString(sh.rw, question)
+		answer, err := sh.readLine(true)
+		if err != nil {
+			return "", true
+		}
+		if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+			return "", false
+		}
+	}
+	if out, ok := sh.response(cmd); ok {
+		return out, false
+	}
+	if sh.mode >= ModeConfig {
+		// Configuration commands are accepted silently.
+		return "", false
+	}
+	pad := strings.Repeat(" ", len(sh.prompt()))
+	return fmt.Sprintf("%s^\n%s", pad, badCommand), false
+}
+
+// response looks up the output of cmd from the canned responses or Handler.
+func (sh *shell) response(cmd string) (string, bool) {
+	sh.sw.mu.Lock()
+	out, ok := sh.sw.Responses[cmd]
+	handler := sh.sw.Handler
+	sh.sw.mu.Unlock()
+	if ok {
+		return out, true
+	}
+	if handler != nil {
+		return handler(sh.mode, cmd)
+	}
+	return "", false
+}
+
+// crlf converts line endings to the CR LF pairs a PTY produces.
+func crlf(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
+}
@@ -0,0 +1,124 @@
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/pascal71/tplink-go/client"
+)
+
+// Transport is a client.Transport running the CLI of a Switch over
+// in-memory pipes, without SSH or a listener. Commands, Sessions and Drop
+// of the switch see its sessions like SSH ones.
+type Transport struct {
+	sw *Switch
+
+	mu   sync.Mutex
+	conn net.Conn // switch end of the open session
+	ours net.Conn // client end
+}
+
+var _ client.Transport = (*Transport)(nil)
+
+// Transport returns a transport for s, to use with client.WithTransport.
+// Start is not needed.
+func (s *Switch) Transport() *Transport {
+	return &Transport{sw: s}
+}
+
+// Open starts a CLI session in user mode.
+func (t *Transport) Open(ctx context.Context) (io.Writer, io.Reader, error) {
+	ours, theirs := net.Pipe()
+	s := t.sw
+	s.mu.Lock()
+	if s.conns == nil {
+		s.conns = make(map[net.Conn]struct{})
+	}
+	s.mu.Unlock()
+	s.track(theirs, true)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.track(theirs, false)
+		defer theirs.Close()
+		s.runShell(theirs)
+	}()
+
+	t.mu.Lock()
+	t.conn, t.ours = theirs, ours
+	t.mu.Unlock()
+	return ours, newBufferedReader(ours), nil
+}
+
+// Close ends the session.
+func (t *Transport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.ours == nil {
+		return nil
+	}
+	err := t.ours.Close()
+	t.conn, t.ours = nil, nil
+	return err
+}
+
+// KeepAlive fails once the switch has dropped the session, for
+// client.WithKeepAlive.
+func (t *Transport) KeepAlive() error {
+	t.mu.Lock()
+	conn := t.conn
+	t.mu.Unlock()
+	t.sw.mu.Lock()
+	defer t.sw.mu.Unlock()
+	if _, ok := t.sw.conns[conn]; !ok || conn == nil {
+		return net.ErrClosed
+	}
+	return nil
+}
+
+// bufferedReader reads r from a goroutine into an unbounded buffer, so the
+// switch can keep writing while the client is busy sending, as it can into
+// an SSH channel window.
+type bufferedReader struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	buf  bytes.Buffer
+	err  error // error ending r, returned once buf is drained
+}
+
+func newBufferedReader(r io.Reader) *bufferedReader {
+	b := &bufferedReader{}
+	b.cond = sync.NewCond(&b.mu)
+	go func() {
+		p := make([]byte, 4096)
+		for {
+			n, err := r.Read(p)
+			b.mu.Lock()
+			b.buf.Write(p[:n])
+			if err != nil {
+				b.err = err
+			}
+			b.cond.Broadcast()
+			b.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return b
+}
+
+func (b *bufferedReader) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.buf.Len() == 0 && b.err == nil {
+		b.cond.Wait()
+	}
+	if b.buf.Len() > 0 {
+		return b.buf.Read(p)
+	}
+	return 0, b.err
+}